@@ -0,0 +1,64 @@
+package vl53l1x
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"testing"
+)
+
+// writeBus is a Bus recording the writes made to it
+type writeBus struct {
+	resultBus
+	writes [][]byte
+}
+
+func (b *writeBus) WriteBytes(buf []byte) (int, error) {
+
+	b.writes = append(b.writes, bytes.Clone(buf))
+
+	return len(buf), nil
+}
+
+func TestSetAddressRollsBackFailedOpen(t *testing.T) {
+
+	bus := &writeBus{}
+	v, err := new(bus, Short, 20, WithLogger(log.New(io.Discard, "", 0)),
+		WithBusOpener(func(uint8, string) (Bus, error) {
+			return nil, errors.New("address busy")
+		}))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old, err := v.SetAddress(0x30)
+
+	if err == nil {
+		t.Fatal("expected error when the new connection can not be opened")
+	}
+
+	if old != Address {
+		t.Errorf("previous address = 0x%02X, want 0x%02X", old, Address)
+	}
+
+	if v.bus != bus {
+		t.Error("old connection replaced")
+	}
+
+	want := [][]byte{
+		{byte(I2C_SLAVE_DEVICE_ADDRESS >> 8), byte(I2C_SLAVE_DEVICE_ADDRESS), 0x30},
+		{byte(I2C_SLAVE_DEVICE_ADDRESS >> 8), byte(I2C_SLAVE_DEVICE_ADDRESS), Address},
+	}
+
+	if len(bus.writes) != len(want) {
+		t.Fatalf("writes = % X, want % X", bus.writes, want)
+	}
+
+	for i := range want {
+		if !bytes.Equal(bus.writes[i], want[i]) {
+			t.Errorf("write %d = % X, want % X", i, bus.writes[i], want[i])
+		}
+	}
+}
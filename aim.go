@@ -0,0 +1,90 @@
+package vl53l1x
+
+import (
+	"context"
+	"fmt"
+)
+
+// AimConfig configures the ROI sweep made by AutoAim()
+type AimConfig struct {
+	// Width and Height of the candidate ROI's, defaults to 4x4
+	Width, Height uint8
+	// Step is the number of SPADs between candidate ROI's, defaults to 2
+	Step uint8
+	// Samples is the number of measurements taken at each candidate,
+	// defaults to 3
+	Samples int
+}
+
+// AimResult is the ROI selected by AutoAim()
+type AimResult ZoneResult
+
+// AutoAim sweeps candidate ROI centers across the SPAD array measuring the
+// peak signal rate at each, then locks the ROI onto the candidate with the
+// strongest return.  This is useful when the mechanical alignment to a small
+// target such as a reflector is imperfect.  If no candidate returns a valid
+// range the previous ROI is restored
+func (v *VL53L1X) AutoAim(ctx context.Context, cfg AimConfig) (AimResult, error) {
+
+	if cfg.Width == 0 || cfg.Height == 0 {
+		cfg.Width, cfg.Height = 4, 4
+	}
+
+	if cfg.Step == 0 {
+		cfg.Step = 2
+	}
+
+	if cfg.Samples <= 0 {
+		cfg.Samples = 3
+	}
+
+	width, height, err := v.GetROISize()
+
+	if err != nil {
+		return AimResult{}, err
+	}
+
+	center, err := v.GetROICenter()
+
+	if err != nil {
+		return AimResult{}, err
+	}
+
+	var best ZoneResult
+
+	for _, zone := range ZoneSweep(cfg.Width, cfg.Height, cfg.Step) {
+		res, err := v.MeasureZone(ctx, zone, cfg.Samples)
+
+		if err != nil {
+			v.restoreROI(width, height, center)
+			return AimResult{}, err
+		}
+
+		if res.Valid > 0 && res.PeakSignalCountRateMCPS > best.PeakSignalCountRateMCPS {
+			best = res
+		}
+	}
+
+	if best.Valid == 0 {
+		v.restoreROI(width, height, center)
+		return AimResult{}, fmt.Errorf("no ROI returned a valid range")
+	}
+
+	// lock onto the strongest return
+	if err := v.SetROISize(best.Zone.Width, best.Zone.Height); err != nil {
+		return AimResult{}, err
+	}
+
+	if err := v.SetROICenter(best.Zone.Center()); err != nil {
+		return AimResult{}, err
+	}
+
+	return AimResult(best), nil
+}
+
+// restoreROI makes a best effort attempt to return the ROI to its previous
+// size and center
+func (v *VL53L1X) restoreROI(width, height, center uint8) {
+	v.SetROISize(width, height)
+	v.SetROICenter(center)
+}
@@ -0,0 +1,222 @@
+// Package altitude estimates true vertical altitude from a downward facing
+// VL53L1X sensor on a drone, compensating for the tilt of the airframe
+// reported by an IMU, as a companion to a flight controller altitude hold.
+package altitude
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+// Sensor is the sensor interface used by Hold, satisfied by
+// *vl53l1x.VL53L1X
+type Sensor interface {
+	ReadContext(ctx context.Context) (vl53l1x.RangingData, error)
+	GetDistanceMode() vl53l1x.DistanceMode
+	SetDistanceMode(mode vl53l1x.DistanceMode) error
+}
+
+// Config holds the validity limits and mode selection settings
+type Config struct {
+	// MinSignalMCPS is the minimum peak signal rate for a valid estimate,
+	// defaults to 1 MCPS
+	MinSignalMCPS float32
+	// MaxSigmaMM is the maximum range sigma for a valid estimate, defaults
+	// to 15mm
+	MaxSigmaMM float32
+	// MaxTiltDeg is the maximum tilt from level for a valid estimate, beyond
+	// this the beam is likely to miss the ground below.  Defaults to 30
+	MaxTiltDeg float64
+	// AutoMode switches between Short and Long distance mode by altitude
+	// when running with Run()
+	AutoMode bool
+	// LongAboveMM is the altitude above which Long mode is selected and
+	// ShortBelowMM the altitude below which Short mode is selected, the gap
+	// between them provides hysteresis.  Defaults to 1100mm and 900mm
+	LongAboveMM  float64
+	ShortBelowMM float64
+}
+
+// DefaultConfig returns the default validity limits with automatic mode
+// selection enabled
+func DefaultConfig() Config {
+	return Config{
+		MinSignalMCPS: 1,
+		MaxSigmaMM:    15,
+		MaxTiltDeg:    30,
+		AutoMode:      true,
+		LongAboveMM:   1100,
+		ShortBelowMM:  900,
+	}
+}
+
+// Reason explains why an Estimate is not valid
+type Reason int
+
+const (
+	// OK is set on valid estimates
+	OK Reason = iota
+	// BadStatus is set when the range status is not valid
+	BadStatus
+	// LowSignal is set when the signal rate is below Config.MinSignalMCPS
+	LowSignal
+	// HighSigma is set when the sigma is above Config.MaxSigmaMM
+	HighSigma
+	// TooTilted is set when the tilt is above Config.MaxTiltDeg
+	TooTilted
+)
+
+// String implement Stringer interface for Reason
+func (r Reason) String() string {
+	switch r {
+	case OK:
+		return "ok"
+	case BadStatus:
+		return "bad range status"
+	case LowSignal:
+		return "low signal"
+	case HighSigma:
+		return "high sigma"
+	case TooTilted:
+		return "too tilted"
+	default:
+		return "unknown"
+	}
+}
+
+// Estimate is a tilt compensated altitude
+type Estimate struct {
+	Time time.Time
+	// AltitudeMM is the vertical altitude above the ground
+	AltitudeMM float64
+	// RangeMM is the slant range measured by the sensor
+	RangeMM uint16
+	// Valid is true when the estimate can be used for altitude hold, Reason
+	// gives the cause when it is not
+	Valid  bool
+	Reason Reason
+}
+
+// Hold produces altitude estimates from sensor readings
+type Hold struct {
+	cfg Config
+}
+
+// New returns a Hold for the configuration, zero values are replaced by the
+// defaults
+func New(cfg Config) *Hold {
+
+	def := DefaultConfig()
+
+	if cfg.MinSignalMCPS == 0 {
+		cfg.MinSignalMCPS = def.MinSignalMCPS
+	}
+
+	if cfg.MaxSigmaMM == 0 {
+		cfg.MaxSigmaMM = def.MaxSigmaMM
+	}
+
+	if cfg.MaxTiltDeg == 0 {
+		cfg.MaxTiltDeg = def.MaxTiltDeg
+	}
+
+	if cfg.LongAboveMM == 0 {
+		cfg.LongAboveMM = def.LongAboveMM
+	}
+
+	if cfg.ShortBelowMM == 0 {
+		cfg.ShortBelowMM = def.ShortBelowMM
+	}
+
+	return &Hold{cfg: cfg}
+}
+
+// Update returns the altitude for a reading taken with the airframe at the
+// given roll and pitch in degrees
+func (h *Hold) Update(data vl53l1x.RangingData, rollDeg, pitchDeg float64, now time.Time) Estimate {
+
+	roll := rollDeg * math.Pi / 180
+	pitch := pitchDeg * math.Pi / 180
+
+	// the cosine of the angle between the beam and vertical
+	cosTilt := math.Cos(roll) * math.Cos(pitch)
+
+	est := Estimate{
+		Time:       now,
+		RangeMM:    data.RangeMM,
+		AltitudeMM: float64(data.RangeMM) * cosTilt,
+	}
+
+	switch {
+	case !data.RangeStatus.IsValid():
+		est.Reason = BadStatus
+	case data.PeakSignalCountRateMCPS < h.cfg.MinSignalMCPS:
+		est.Reason = LowSignal
+	case data.SigmaMM > h.cfg.MaxSigmaMM:
+		est.Reason = HighSigma
+	case math.Acos(cosTilt)*180/math.Pi > h.cfg.MaxTiltDeg:
+		est.Reason = TooTilted
+	default:
+		est.Valid = true
+	}
+
+	return est
+}
+
+// SelectMode returns the distance mode suited to the altitude of the
+// estimate given the current mode
+func (h *Hold) SelectMode(current vl53l1x.DistanceMode, est Estimate) vl53l1x.DistanceMode {
+
+	switch {
+	case !est.Valid && est.Reason == BadStatus && current == vl53l1x.Short:
+		// lost the ground in Short mode, try the longer range
+		return vl53l1x.Long
+	case !est.Valid:
+		return current
+	case est.AltitudeMM > h.cfg.LongAboveMM:
+		return vl53l1x.Long
+	case est.AltitudeMM < h.cfg.ShortBelowMM:
+		return vl53l1x.Short
+	default:
+		return current
+	}
+}
+
+// Run reads measurements from the sensor, which must already be ranging,
+// compensating each with the tilt returned by the tilt function and calling
+// fn with the estimate until the context is cancelled.  When
+// Config.AutoMode is set the distance mode is switched by altitude
+func (h *Hold) Run(ctx context.Context, sensor Sensor,
+	tilt func() (rollDeg, pitchDeg float64), fn func(Estimate)) error {
+
+	for {
+		data, err := sensor.ReadContext(ctx)
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		roll, pitch := tilt()
+		est := h.Update(data, roll, pitch, time.Now())
+		fn(est)
+
+		if !h.cfg.AutoMode {
+			continue
+		}
+
+		current := sensor.GetDistanceMode()
+
+		if mode := h.SelectMode(current, est); mode != current {
+			if err := sensor.SetDistanceMode(mode); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,199 @@
+// Package conveyor detects objects passing under a VL53L1X sensor mounted
+// above a conveyor belt, counting them and estimating their height from the
+// distance to the empty belt.
+package conveyor
+
+import (
+	"context"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+// Reader is the sensor interface used by the Counter, satisfied by
+// *vl53l1x.VL53L1X
+type Reader interface {
+	ReadContext(ctx context.Context) (vl53l1x.RangingData, error)
+}
+
+// Config holds the detection settings
+type Config struct {
+	// BaselineMM is the distance to the empty belt, when 0 it is learnt by
+	// averaging the first BaselineSamples readings
+	BaselineMM      uint16
+	BaselineSamples int
+	// MinHeightMM is the distance step above the belt that marks the edge
+	// of an object, defaults to 20mm
+	MinHeightMM uint16
+	// Debounce is how long a step must be seen, or not seen, before the
+	// leading or trailing edge of an object is accepted, defaults to 20ms
+	Debounce time.Duration
+	// MinDuration is the shortest time an object must be under the sensor to
+	// be counted, defaults to 50ms
+	MinDuration time.Duration
+}
+
+// Object is an object that passed under the sensor
+type Object struct {
+	// Start and End are the times of the leading and trailing edges
+	Start, End time.Time
+	// HeightMM is the greatest height measured above the belt
+	HeightMM uint16
+	// Count is the number of objects counted including this one
+	Count int
+}
+
+// Duration returns how long the object was under the sensor
+func (o Object) Duration() time.Duration {
+	return o.End.Sub(o.Start)
+}
+
+// Counter detects and counts objects from sensor readings
+type Counter struct {
+	cfg Config
+
+	baselineSum uint32
+	baselineN   int
+
+	inObject bool
+	// edge is the time a change of presence was first seen and pending is
+	// set while it is being debounced
+	edge    time.Time
+	pending bool
+	start   time.Time
+	height  uint16
+	count   int
+}
+
+// New returns a Counter for the configuration, zero values are replaced by
+// the defaults
+func New(cfg Config) *Counter {
+
+	if cfg.BaselineSamples <= 0 {
+		cfg.BaselineSamples = 10
+	}
+
+	if cfg.MinHeightMM == 0 {
+		cfg.MinHeightMM = 20
+	}
+
+	if cfg.Debounce == 0 {
+		cfg.Debounce = 20 * time.Millisecond
+	}
+
+	if cfg.MinDuration == 0 {
+		cfg.MinDuration = 50 * time.Millisecond
+	}
+
+	return &Counter{cfg: cfg}
+}
+
+// Count returns the number of objects counted
+func (c *Counter) Count() int {
+	return c.count
+}
+
+// Baseline returns the distance to the belt, 0 while it is being learnt
+func (c *Counter) Baseline() uint16 {
+	return c.cfg.BaselineMM
+}
+
+// Update processes a reading taken at the given time and returns the Object
+// when its trailing edge is detected.  Readings without a valid range are
+// ignored
+func (c *Counter) Update(data vl53l1x.RangingData, now time.Time) (Object, bool) {
+
+	if !data.RangeStatus.IsValid() {
+		return Object{}, false
+	}
+
+	if c.cfg.BaselineMM == 0 {
+		c.learnBaseline(data.RangeMM)
+		return Object{}, false
+	}
+
+	var height uint16
+
+	if data.RangeMM < c.cfg.BaselineMM {
+		height = c.cfg.BaselineMM - data.RangeMM
+	}
+
+	present := height >= c.cfg.MinHeightMM
+
+	if present == c.inObject {
+		c.pending = false
+		c.height = max(c.height, height)
+		return Object{}, false
+	}
+
+	if !c.pending {
+		c.pending = true
+		c.edge = now
+
+		if present {
+			c.height = 0
+		}
+	}
+
+	if present {
+		c.height = max(c.height, height)
+	}
+
+	if now.Sub(c.edge) < c.cfg.Debounce {
+		return Object{}, false
+	}
+
+	c.pending = false
+
+	if present {
+		// leading edge
+		c.inObject = true
+		c.start = c.edge
+		return Object{}, false
+	}
+
+	// trailing edge
+	c.inObject = false
+	obj := Object{Start: c.start, End: c.edge, HeightMM: c.height}
+
+	if obj.Duration() < c.cfg.MinDuration {
+		return Object{}, false
+	}
+
+	c.count++
+	obj.Count = c.count
+
+	return obj, true
+}
+
+// Run reads measurements from the sensor, which must already be ranging, and
+// calls fn on each object counted until the context is cancelled
+func (c *Counter) Run(ctx context.Context, sensor Reader, fn func(Object)) error {
+
+	for {
+		data, err := sensor.ReadContext(ctx)
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if obj, ok := c.Update(data, time.Now()); ok {
+			fn(obj)
+		}
+	}
+}
+
+// learnBaseline averages readings of the empty belt
+func (c *Counter) learnBaseline(mm uint16) {
+
+	c.baselineSum += uint32(mm)
+	c.baselineN++
+
+	if c.baselineN >= c.cfg.BaselineSamples {
+		c.cfg.BaselineMM = uint16(c.baselineSum / uint32(c.baselineN))
+	}
+}
@@ -0,0 +1,181 @@
+// Package dispenser detects a hand held under a touchless dispenser by a
+// VL53L1X sensor, triggering dispensing with a lockout period and detecting
+// a permanent near reading that indicates the dispenser needs attention.
+package dispenser
+
+import (
+	"context"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+// Reader is the sensor interface used by the Trigger, satisfied by
+// *vl53l1x.VL53L1X
+type Reader interface {
+	ReadContext(ctx context.Context) (vl53l1x.RangingData, error)
+}
+
+// Config holds the trigger settings
+type Config struct {
+	// TriggerMM is the distance below which a hand is detected, defaults to
+	// 100mm
+	TriggerMM uint16
+	// Hold is how long a hand must be detected before triggering, defaults
+	// to 150ms
+	Hold time.Duration
+	// Lockout is the time after triggering before it can trigger again,
+	// defaults to 2s
+	Lockout time.Duration
+	// RefillAfter is how long a continuous near reading lasts before it is
+	// reported as the dispenser needing a refill or being blocked, defaults
+	// to 30s
+	RefillAfter time.Duration
+}
+
+// Event is returned by Update().  Use a type switch to determine which of
+// TriggerEvent, RefillNeededEvent or RefillClearedEvent it is
+type Event interface {
+	// EventTime returns when the event occurred
+	EventTime() time.Time
+}
+
+// TriggerEvent is emitted when the dispenser should dispense
+type TriggerEvent struct {
+	Time    time.Time
+	RangeMM uint16
+}
+
+// RefillNeededEvent is emitted when a near reading has not cleared within
+// Config.RefillAfter
+type RefillNeededEvent struct {
+	Time time.Time
+	// Since is when the near reading started
+	Since time.Time
+}
+
+// RefillClearedEvent is emitted when the near reading that caused a
+// RefillNeededEvent clears
+type RefillClearedEvent struct {
+	Time time.Time
+}
+
+// EventTime returns when the event occurred
+func (e TriggerEvent) EventTime() time.Time { return e.Time }
+
+// EventTime returns when the event occurred
+func (e RefillNeededEvent) EventTime() time.Time { return e.Time }
+
+// EventTime returns when the event occurred
+func (e RefillClearedEvent) EventTime() time.Time { return e.Time }
+
+// Trigger detects hands from sensor readings
+type Trigger struct {
+	cfg Config
+
+	near      bool
+	nearSince time.Time
+	// fired is set once triggered until the hand is removed
+	fired        bool
+	lockoutUntil time.Time
+	refill       bool
+}
+
+// New returns a Trigger for the configuration, zero values are replaced by
+// the defaults
+func New(cfg Config) *Trigger {
+
+	if cfg.TriggerMM == 0 {
+		cfg.TriggerMM = 100
+	}
+
+	if cfg.Hold == 0 {
+		cfg.Hold = 150 * time.Millisecond
+	}
+
+	if cfg.Lockout == 0 {
+		cfg.Lockout = 2 * time.Second
+	}
+
+	if cfg.RefillAfter == 0 {
+		cfg.RefillAfter = 30 * time.Second
+	}
+
+	return &Trigger{cfg: cfg}
+}
+
+// RefillNeeded returns true while a permanent near reading is detected
+func (t *Trigger) RefillNeeded() bool {
+	return t.refill
+}
+
+// Update processes a reading taken at the given time and returns an Event
+// if one occurred.  A reading with no target found is treated as no hand and
+// other readings without a valid range are ignored
+func (t *Trigger) Update(data vl53l1x.RangingData, now time.Time) (Event, bool) {
+
+	var near bool
+
+	switch {
+	case data.RangeStatus.IsValid():
+		near = data.RangeMM < t.cfg.TriggerMM
+	case data.RangeStatus == vl53l1x.SignalFail:
+		near = false
+	default:
+		return nil, false
+	}
+
+	if !near {
+		t.near = false
+		t.fired = false
+
+		if t.refill {
+			t.refill = false
+			return RefillClearedEvent{Time: now}, true
+		}
+
+		return nil, false
+	}
+
+	if !t.near {
+		t.near = true
+		t.nearSince = now
+	}
+
+	held := now.Sub(t.nearSince)
+
+	if !t.refill && held >= t.cfg.RefillAfter {
+		t.refill = true
+		return RefillNeededEvent{Time: now, Since: t.nearSince}, true
+	}
+
+	if t.refill || t.fired || held < t.cfg.Hold || now.Before(t.lockoutUntil) {
+		return nil, false
+	}
+
+	t.fired = true
+	t.lockoutUntil = now.Add(t.cfg.Lockout)
+
+	return TriggerEvent{Time: now, RangeMM: data.RangeMM}, true
+}
+
+// Run reads measurements from the sensor, which must already be ranging, and
+// calls fn on each event until the context is cancelled
+func (t *Trigger) Run(ctx context.Context, sensor Reader, fn func(Event)) error {
+
+	for {
+		data, err := sensor.ReadContext(ctx)
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if e, ok := t.Update(data, time.Now()); ok {
+			fn(e)
+		}
+	}
+}
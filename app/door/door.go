@@ -0,0 +1,215 @@
+// Package door classifies the state of a door or garage door from the
+// distance measured by a VL53L1X sensor looking along its path, emitting
+// transitions between open, closed, moving and obstructed states.
+package door
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+// Reader is the sensor interface used by the Detector, satisfied by
+// *vl53l1x.VL53L1X
+type Reader interface {
+	ReadContext(ctx context.Context) (vl53l1x.RangingData, error)
+}
+
+// State of the door
+type State int
+
+const (
+	// Unknown is the state until a reading has settled in a band
+	Unknown State = iota
+	// Closed door
+	Closed
+	// Open door
+	Open
+	// Moving door, the reading is between the open and closed bands
+	Moving
+	// ObjectInPath is reported when a reading falls in the obstruction band
+	ObjectInPath
+	// Stuck is reported when the door has been moving for longer than
+	// Config.StuckAfter
+	Stuck
+)
+
+// String implement Stringer interface for State
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case Moving:
+		return "moving"
+	case ObjectInPath:
+		return "object in path"
+	case Stuck:
+		return "stuck"
+	default:
+		return "unknown"
+	}
+}
+
+// Band is a range of distances in millimeters, inclusive
+type Band struct {
+	MinMM, MaxMM uint16
+}
+
+// contains returns true if the distance is within the band
+func (b Band) contains(mm uint16) bool {
+	return mm >= b.MinMM && mm <= b.MaxMM
+}
+
+// empty returns true if the band is not set
+func (b Band) empty() bool {
+	return b.MinMM == 0 && b.MaxMM == 0
+}
+
+// Config maps distance bands to door states
+type Config struct {
+	// Closed and Open are the bands measured with the door closed and open
+	Closed Band
+	Open   Band
+	// Obstruction is an optional band in which a reading means an object is
+	// in the path of the door, it takes priority over the other bands
+	Obstruction Band
+	// Settle is how long readings must stay in a band before the state
+	// changes, defaults to 500ms
+	Settle time.Duration
+	// StuckAfter is how long the door may be moving before it is reported as
+	// stuck, defaults to 30s
+	StuckAfter time.Duration
+}
+
+// Transition is a change of door state
+type Transition struct {
+	Time     time.Time
+	From, To State
+	// RangeMM is the reading that caused the transition
+	RangeMM uint16
+}
+
+// Detector tracks the door state from sensor readings
+type Detector struct {
+	cfg       Config
+	state     State
+	candidate State
+	since     time.Time
+	movingAt  time.Time
+}
+
+// New returns a Detector for the configuration
+func New(cfg Config) (*Detector, error) {
+
+	if cfg.Closed.empty() || cfg.Open.empty() {
+		return nil, fmt.Errorf("open and closed bands must be set")
+	}
+
+	if cfg.Closed.MinMM > cfg.Closed.MaxMM || cfg.Open.MinMM > cfg.Open.MaxMM ||
+		cfg.Obstruction.MinMM > cfg.Obstruction.MaxMM {
+		return nil, fmt.Errorf("band minimum is beyond maximum")
+	}
+
+	if cfg.Open.MinMM <= cfg.Closed.MaxMM && cfg.Closed.MinMM <= cfg.Open.MaxMM {
+		return nil, fmt.Errorf("open and closed bands overlap")
+	}
+
+	if cfg.Settle == 0 {
+		cfg.Settle = 500 * time.Millisecond
+	}
+
+	if cfg.StuckAfter == 0 {
+		cfg.StuckAfter = 30 * time.Second
+	}
+
+	return &Detector{cfg: cfg}, nil
+}
+
+// State returns the current door state
+func (d *Detector) State() State {
+	return d.state
+}
+
+// Update processes a reading taken at the given time and returns the
+// transition if the door state changed.  Readings without a valid range are
+// ignored
+func (d *Detector) Update(data vl53l1x.RangingData, now time.Time) (Transition, bool) {
+
+	if !data.RangeStatus.IsValid() {
+		return Transition{}, false
+	}
+
+	next := d.classify(data.RangeMM)
+
+	if next != d.candidate {
+		d.candidate = next
+		d.since = now
+	}
+
+	// a door that has been moving too long is stuck
+	if d.state == Moving && next == Moving && now.Sub(d.movingAt) >= d.cfg.StuckAfter {
+		return d.transition(Stuck, data.RangeMM, now), true
+	}
+
+	if next == d.state || (d.state == Stuck && next == Moving) {
+		return Transition{}, false
+	}
+
+	if now.Sub(d.since) < d.cfg.Settle {
+		return Transition{}, false
+	}
+
+	if next == Moving {
+		d.movingAt = d.since
+	}
+
+	return d.transition(next, data.RangeMM, now), true
+}
+
+// Run reads measurements from the sensor, which must already be ranging, and
+// calls fn on each transition until the context is cancelled
+func (d *Detector) Run(ctx context.Context, sensor Reader, fn func(Transition)) error {
+
+	for {
+		data, err := sensor.ReadContext(ctx)
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if t, ok := d.Update(data, time.Now()); ok {
+			fn(t)
+		}
+	}
+}
+
+// classify returns the state a single reading indicates
+func (d *Detector) classify(mm uint16) State {
+
+	switch {
+	case !d.cfg.Obstruction.empty() && d.cfg.Obstruction.contains(mm):
+		return ObjectInPath
+	case d.cfg.Closed.contains(mm):
+		return Closed
+	case d.cfg.Open.contains(mm):
+		return Open
+	default:
+		return Moving
+	}
+}
+
+// transition changes the state and returns the Transition
+func (d *Detector) transition(to State, mm uint16, now time.Time) Transition {
+
+	t := Transition{Time: now, From: d.state, To: to, RangeMM: mm}
+	d.state = to
+	return t
+}
@@ -0,0 +1,172 @@
+// Package occupancy aggregates enter and exit events, such as from a people
+// counter or presence detector built on a VL53L1X sensor, into occupancy
+// counts, dwell time statistics and hourly histograms exportable as JSON for
+// retail and room occupancy deployments.
+package occupancy
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Kind of presence event
+type Kind int
+
+const (
+	// Enter is a person entering the space or joining the queue
+	Enter Kind = iota
+	// Exit is a person leaving the space or queue
+	Exit
+)
+
+// Event is a presence event consumed by the Aggregator
+type Event struct {
+	Time time.Time
+	Kind Kind
+}
+
+// DwellStats summarises how long people stayed, assuming they leave in the
+// order they arrived as in a queue
+type DwellStats struct {
+	Count       int     `json:"count"`
+	MeanSeconds float64 `json:"meanSeconds"`
+	MinSeconds  float64 `json:"minSeconds"`
+	MaxSeconds  float64 `json:"maxSeconds"`
+}
+
+// HourStats holds the activity in one hour of the day
+type HourStats struct {
+	Entries       int `json:"entries"`
+	Exits         int `json:"exits"`
+	PeakOccupancy int `json:"peakOccupancy"`
+}
+
+// Stats is a snapshot of the aggregated events
+type Stats struct {
+	Occupancy     int        `json:"occupancy"`
+	PeakOccupancy int        `json:"peakOccupancy"`
+	Entries       int        `json:"entries"`
+	Exits         int        `json:"exits"`
+	Dwell         DwellStats `json:"dwell"`
+	// Hourly is indexed by the local hour of the day the events occurred in
+	Hourly [24]HourStats `json:"hourly"`
+}
+
+// Aggregator accumulates presence events, it is safe for concurrent use
+type Aggregator struct {
+	mu       sync.Mutex
+	stats    Stats
+	arrivals []time.Time
+	dwellSum time.Duration
+}
+
+// New returns an empty Aggregator
+func New() *Aggregator {
+	return &Aggregator{}
+}
+
+// Add records a presence event.  An exit when the space is already empty is
+// counted but does not reduce the occupancy below zero
+func (a *Aggregator) Add(e Event) {
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	hour := &a.stats.Hourly[e.Time.Hour()]
+
+	switch e.Kind {
+	case Enter:
+		a.stats.Entries++
+		a.stats.Occupancy++
+		hour.Entries++
+		a.arrivals = append(a.arrivals, e.Time)
+
+	case Exit:
+		a.stats.Exits++
+		hour.Exits++
+
+		if len(a.arrivals) == 0 {
+			return
+		}
+
+		a.stats.Occupancy--
+		a.addDwell(e.Time.Sub(a.arrivals[0]))
+		a.arrivals = a.arrivals[1:]
+	}
+
+	a.stats.PeakOccupancy = max(a.stats.PeakOccupancy, a.stats.Occupancy)
+	hour.PeakOccupancy = max(hour.PeakOccupancy, a.stats.Occupancy)
+}
+
+// Enter records a person entering at the given time
+func (a *Aggregator) Enter(t time.Time) {
+	a.Add(Event{Time: t, Kind: Enter})
+}
+
+// Exit records a person leaving at the given time
+func (a *Aggregator) Exit(t time.Time) {
+	a.Add(Event{Time: t, Kind: Exit})
+}
+
+// Stats returns a snapshot of the aggregated statistics
+func (a *Aggregator) Stats() Stats {
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.stats
+}
+
+// Reset clears the statistics, people present remain counted in the
+// occupancy
+func (a *Aggregator) Reset() {
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.stats = Stats{Occupancy: a.stats.Occupancy, PeakOccupancy: a.stats.Occupancy}
+	a.dwellSum = 0
+}
+
+// WriteJSON writes the statistics to w as JSON
+func (a *Aggregator) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(a.Stats())
+}
+
+// Run adds events received on the channel until it is closed or the context
+// is cancelled
+func (a *Aggregator) Run(ctx context.Context, events <-chan Event) error {
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case e, ok := <-events:
+			if !ok {
+				return nil
+			}
+
+			a.Add(e)
+		}
+	}
+}
+
+// addDwell adds a dwell time to the statistics
+func (a *Aggregator) addDwell(d time.Duration) {
+
+	dw := &a.stats.Dwell
+	sec := d.Seconds()
+
+	if dw.Count == 0 || sec < dw.MinSeconds {
+		dw.MinSeconds = sec
+	}
+
+	dw.MaxSeconds = max(dw.MaxSeconds, sec)
+	dw.Count++
+	a.dwellSum += d
+	dw.MeanSeconds = a.dwellSum.Seconds() / float64(dw.Count)
+}
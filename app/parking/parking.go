@@ -0,0 +1,184 @@
+// Package parking maps the distance to a vehicle measured by a VL53L1X sensor
+// mounted on a garage wall to alert levels, suitable for driving LEDs or a
+// buzzer while parking.
+package parking
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+// Reader is the sensor interface used by the Assistant, satisfied by
+// *vl53l1x.VL53L1X
+type Reader interface {
+	ReadContext(ctx context.Context) (vl53l1x.RangingData, error)
+}
+
+// Level is the alert level for the distance to the vehicle, higher levels are
+// more severe
+type Level int
+
+const (
+	// None is reported when no vehicle is within the far threshold
+	None Level = iota
+	// Far is reported when a vehicle is approaching
+	Far
+	// Near is reported when the vehicle should slow down
+	Near
+	// Stop is reported when the vehicle is in position
+	Stop
+	// TooClose is reported when the vehicle has passed the stop position
+	TooClose
+)
+
+// String implement Stringer interface for Level
+func (l Level) String() string {
+	switch l {
+	case Far:
+		return "far"
+	case Near:
+		return "near"
+	case Stop:
+		return "stop"
+	case TooClose:
+		return "too close"
+	default:
+		return "none"
+	}
+}
+
+// Config holds the alert thresholds in centimeters, a level is entered when
+// the distance is below its threshold
+type Config struct {
+	FarCM      uint16
+	NearCM     uint16
+	StopCM     uint16
+	TooCloseCM uint16
+	// HysteresisCM is how far the distance must move back past a threshold
+	// before returning to a less severe level
+	HysteresisCM uint16
+	// MinInterval is the minimum time between alerts that lower the level,
+	// alerts that raise the level are never delayed
+	MinInterval time.Duration
+}
+
+// DefaultConfig returns thresholds suited to a typical single car garage
+func DefaultConfig() Config {
+	return Config{
+		FarCM:        300,
+		NearCM:       150,
+		StopCM:       60,
+		TooCloseCM:   40,
+		HysteresisCM: 5,
+		MinInterval:  250 * time.Millisecond,
+	}
+}
+
+// Alert is a change of alert level
+type Alert struct {
+	Time       time.Time
+	Level      Level
+	DistanceCM uint16
+}
+
+// Assistant tracks the alert level from sensor readings
+type Assistant struct {
+	cfg       Config
+	level     Level
+	lastAlert time.Time
+}
+
+// New returns an Assistant for the configuration
+func New(cfg Config) (*Assistant, error) {
+
+	if !(cfg.FarCM > cfg.NearCM && cfg.NearCM > cfg.StopCM && cfg.StopCM > cfg.TooCloseCM) {
+		return nil, fmt.Errorf("thresholds must decrease from far to too close")
+	}
+
+	return &Assistant{cfg: cfg}, nil
+}
+
+// Level returns the current alert level
+func (a *Assistant) Level() Level {
+	return a.level
+}
+
+// Update processes a reading taken at the given time and returns an Alert if
+// the level changed.  A reading with no target found is treated as no
+// vehicle and other readings without a valid range are ignored
+func (a *Assistant) Update(data vl53l1x.RangingData, now time.Time) (Alert, bool) {
+
+	var cm uint16
+	next := None
+
+	switch {
+	case data.RangeStatus.IsValid():
+		cm = data.RangeMM / 10
+		next = a.classify(cm)
+
+		// only lower the level once clear of the threshold by the hysteresis
+		if next < a.level {
+			next = min(a.level, a.classify(cm-min(cm, a.cfg.HysteresisCM)))
+		}
+
+	case data.RangeStatus == vl53l1x.SignalFail:
+		next = None
+
+	default:
+		return Alert{}, false
+	}
+
+	if next == a.level {
+		return Alert{}, false
+	}
+
+	if next < a.level && now.Sub(a.lastAlert) < a.cfg.MinInterval {
+		return Alert{}, false
+	}
+
+	a.level = next
+	a.lastAlert = now
+
+	return Alert{Time: now, Level: next, DistanceCM: cm}, true
+}
+
+// Run reads measurements from the sensor, which must already be ranging, and
+// calls fn on each alert until the context is cancelled
+func (a *Assistant) Run(ctx context.Context, sensor Reader, fn func(Alert)) error {
+
+	for {
+		data, err := sensor.ReadContext(ctx)
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if alert, ok := a.Update(data, time.Now()); ok {
+			fn(alert)
+		}
+	}
+}
+
+// classify returns the level for the distance
+func (a *Assistant) classify(cm uint16) Level {
+
+	switch {
+	case cm < a.cfg.TooCloseCM:
+		return TooClose
+	case cm < a.cfg.StopCM:
+		return Stop
+	case cm < a.cfg.NearCM:
+		return Near
+	case cm < a.cfg.FarCM:
+		return Far
+	default:
+		return None
+	}
+}
@@ -0,0 +1,146 @@
+// Package thickness measures the thickness or width of an object passing
+// between two VL53L1X sensors mounted facing each other a known distance
+// apart, from the baseline between the sensors minus the distance each
+// measures to its side of the object.
+package thickness
+
+import (
+	"context"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+// Reader is the sensor interface used by the Gauge, satisfied by
+// *vl53l1x.VL53L1X
+type Reader interface {
+	ReadContext(ctx context.Context) (vl53l1x.RangingData, error)
+}
+
+// Config holds the gauge settings
+type Config struct {
+	// BaselineMM is the distance between the faces of the two sensors
+	BaselineMM uint16
+	// OffsetAMM and OffsetBMM are added to the distances measured by sensor A
+	// and B, found by comparing each sensor against a known distance or with
+	// vl53l1x.CalibrateOffset()
+	OffsetAMM int16
+	OffsetBMM int16
+	// MaxSkew is the greatest time between the two measurements of a pair
+	// for them to be combined, defaults to 20ms
+	MaxSkew time.Duration
+}
+
+// Measurement is the thickness computed from a pair of measurements
+type Measurement struct {
+	// ThicknessMM is the baseline minus both corrected distances
+	ThicknessMM int
+	// DistanceAMM and DistanceBMM are the offset corrected distances
+	DistanceAMM int
+	DistanceBMM int
+	// A and B are the measurements the thickness was computed from
+	A, B vl53l1x.RangingData
+	// Time is when the later of the two measurements was taken and Skew the
+	// time between them
+	Time time.Time
+	Skew time.Duration
+}
+
+// Gauge computes thickness from pairs of measurements of opposing sensors
+type Gauge struct {
+	cfg Config
+}
+
+// New returns a Gauge for the configuration, zero values are replaced by the
+// defaults
+func New(cfg Config) *Gauge {
+
+	if cfg.MaxSkew == 0 {
+		cfg.MaxSkew = 20 * time.Millisecond
+	}
+
+	return &Gauge{cfg: cfg}
+}
+
+// Update combines measurement a from sensor A taken at ta with b from sensor
+// B taken at tb.  The pair is rejected when either range is invalid, the
+// measurements are further apart than MaxSkew or no object is between the
+// sensors
+func (g *Gauge) Update(a vl53l1x.RangingData, ta time.Time, b vl53l1x.RangingData, tb time.Time) (Measurement, bool) {
+
+	if !a.RangeStatus.IsValid() || !b.RangeStatus.IsValid() {
+		return Measurement{}, false
+	}
+
+	skew := ta.Sub(tb)
+	at := ta
+
+	if skew < 0 {
+		skew = -skew
+		at = tb
+	}
+
+	if skew > g.cfg.MaxSkew {
+		return Measurement{}, false
+	}
+
+	m := Measurement{
+		DistanceAMM: int(a.RangeMM) + int(g.cfg.OffsetAMM),
+		DistanceBMM: int(b.RangeMM) + int(g.cfg.OffsetBMM),
+		A:           a,
+		B:           b,
+		Time:        at,
+		Skew:        skew,
+	}
+
+	m.ThicknessMM = int(g.cfg.BaselineMM) - m.DistanceAMM - m.DistanceBMM
+
+	if m.ThicknessMM <= 0 {
+		return Measurement{}, false
+	}
+
+	return m, true
+}
+
+// Run reads both sensors, which must already be ranging with the same
+// inter-measurement period, and calls fn with each thickness measured until
+// the context is cancelled.  The sensors are read concurrently so each pair
+// is taken as close together in time as the bus allows.  Start both sensors
+// back to back so their measurements stay in phase within MaxSkew
+func (g *Gauge) Run(ctx context.Context, a, b Reader, fn func(Measurement)) error {
+
+	type result struct {
+		data vl53l1x.RangingData
+		at   time.Time
+		err  error
+	}
+
+	for {
+		rb := make(chan result, 1)
+
+		go func() {
+			data, err := b.ReadContext(ctx)
+			rb <- result{data, time.Now(), err}
+		}()
+
+		da, errA := a.ReadContext(ctx)
+		ta := time.Now()
+		resB := <-rb
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if errA != nil {
+			return errA
+		}
+
+		if resB.err != nil {
+			return resB.err
+		}
+
+		if m, ok := g.Update(da, ta, resB.data, resB.at); ok {
+			fn(m)
+		}
+	}
+}
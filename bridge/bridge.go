@@ -0,0 +1,23 @@
+// Package bridge provides vl53l1x.Bus transports for reaching the sensor
+// through I2C bridge chips rather than a native I2C bus.  Bridges share the
+// upstream connection, so sensors at several addresses can be opened from one
+// bridge with Open(), which is also suitable for vl53l1x.WithBusOpener()
+package bridge
+
+import (
+	"errors"
+	"time"
+)
+
+// DefaultTimeout is the time a bridge waits for an I2C transaction to
+// complete unless changed with SetTimeout()
+const DefaultTimeout = 100 * time.Millisecond
+
+// ErrClosed is returned when a transfer is made on a closed bridge
+var ErrClosed = errors.New("bridge is closed")
+
+// SPI is a full duplex SPI connection, such as a periph.io spi.Conn
+type SPI interface {
+	// Tx writes w while reading into r, which is nil or the same length as w
+	Tx(w, r []byte) error
+}
@@ -0,0 +1,298 @@
+package bridge
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+// MPSSE opcodes used to drive I2C on the FT232H
+const (
+	mpsseWriteBytesNeg = 0x11
+	mpsseWriteBitsNeg  = 0x13
+	mpsseReadBytesPos  = 0x20
+	mpsseReadBitsPos   = 0x22
+	mpsseSetLow        = 0x80
+	mpsseLoopbackOff   = 0x85
+	mpsseClockDivisor  = 0x86
+	mpsseSendImmediate = 0x87
+	mpsseDiv5Off       = 0x8A
+	mpsse3PhaseOn      = 0x8C
+	mpsseAdaptiveOff   = 0x97
+	mpsseDriveZero     = 0x9E
+)
+
+// ADBUS pin states, SCL is AD0 and SDA is driven on AD1 and read on AD2 which
+// must be wired together
+const (
+	ftSCL    = 0x01
+	ftSDA    = 0x02
+	ftOutput = ftSCL | ftSDA
+	ftInput  = ftSCL
+)
+
+// ftBaseClock is the MPSSE clock with the divide by 5 disabled
+const ftBaseClock = 60000000
+
+// ftRepeat is the number of times pin changes are repeated to meet the I2C
+// setup and hold times at 400kHz
+const ftRepeat = 4
+
+// ftConn is the MPSSE connection to the FT232H shared by all devices opened
+// on it
+type ftConn struct {
+	mu  sync.Mutex
+	rw  io.ReadWriter
+	cmd []byte
+}
+
+// FT232H is a vl53l1x.Bus to a device on the I2C bus driven by an FTDI FT232H
+// in MPSSE mode
+type FT232H struct {
+	conn   *ftConn
+	addr   uint8
+	closed bool
+}
+
+// NewFT232H configures the MPSSE engine for I2C at clockHz and returns a Bus to
+// the device at addr.  mpsse is the FT232H opened in MPSSE mode by a USB or
+// FTDI library with the modem status bytes removed from reads.  The MPSSE
+// connection is owned by the caller and is not closed by Close()
+func NewFT232H(mpsse io.ReadWriter, addr uint8, clockHz int) (*FT232H, error) {
+
+	div, err := ftDivisor(clockHz)
+
+	if err != nil {
+		return nil, err
+	}
+
+	conn := &ftConn{rw: mpsse}
+
+	conn.cmd = append(conn.cmd[:0],
+		mpsseDiv5Off, mpsseAdaptiveOff, mpsse3PhaseOn, mpsseLoopbackOff,
+		mpsseClockDivisor, byte(div), byte(div>>8),
+		mpsseDriveZero, ftOutput|0x04, 0x00,
+	)
+	conn.idle()
+
+	if _, err := conn.rw.Write(conn.cmd); err != nil {
+		return nil, fmt.Errorf("failed to configure FT232H: %w", err)
+	}
+
+	return &FT232H{conn: conn, addr: addr}, nil
+}
+
+// SetClock sets the I2C clock rate closest to but not above hz for all devices
+// on the FT232H and returns the rate set
+func (b *FT232H) SetClock(hz int) (int, error) {
+
+	div, err := ftDivisor(hz)
+
+	if err != nil {
+		return 0, err
+	}
+
+	c := b.conn
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.rw.Write([]byte{mpsseClockDivisor, byte(div), byte(div >> 8)}); err != nil {
+		return 0, err
+	}
+
+	return ftBaseClock / ((div + 1) * 3), nil
+}
+
+// ftDivisor returns the MPSSE clock divisor for an I2C clock rate not above
+// hz.  Three phase clocking stretches each bit to 1.5 periods of the clock
+func ftDivisor(hz int) (int, error) {
+
+	if hz <= 0 {
+		return 0, fmt.Errorf("invalid FT232H clock rate %dHz", hz)
+	}
+
+	div := (ftBaseClock+hz*3-1)/(hz*3) - 1
+
+	if div < 0 || div > 0xFFFF {
+		return 0, fmt.Errorf("FT232H can not clock I2C at %dHz", hz)
+	}
+
+	return div, nil
+}
+
+// Open returns a Bus to the device at addr on the same FT232H
+func (b *FT232H) Open(addr uint8, dev string) (vl53l1x.Bus, error) {
+	return &FT232H{conn: b.conn, addr: addr}, nil
+}
+
+// GetAddr returns the I2C address of the device
+func (b *FT232H) GetAddr() uint8 {
+	return b.addr
+}
+
+// GetDev returns the name of the adapter
+func (b *FT232H) GetDev() string {
+	return "ft232h"
+}
+
+// Close the device, the MPSSE connection remains open
+func (b *FT232H) Close() error {
+
+	b.closed = true
+	return nil
+}
+
+// WriteBytes writes buf to the device
+func (b *FT232H) WriteBytes(buf []byte) (int, error) {
+
+	if b.closed {
+		return 0, ErrClosed
+	}
+
+	c := b.conn
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cmd = c.cmd[:0]
+	c.start()
+	c.writeByte(b.addr << 1)
+
+	for _, d := range buf {
+		c.writeByte(d)
+	}
+
+	c.stop()
+	c.cmd = append(c.cmd, mpsseSendImmediate)
+
+	acks, err := c.exchange(len(buf) + 1)
+
+	if err != nil {
+		return 0, err
+	}
+
+	// the ack bit is clocked into bit 0, high when not acknowledged
+	if acks[0]&0x01 != 0 {
+		return 0, fmt.Errorf("FT232H address: %w", vl53l1x.ErrNACK)
+	}
+
+	for i, ack := range acks[1:] {
+		if ack&0x01 != 0 {
+			return i, fmt.Errorf("FT232H data: %w", vl53l1x.ErrNACK)
+		}
+	}
+
+	return len(buf), nil
+}
+
+// ReadBytes reads len(buf) bytes from the device
+func (b *FT232H) ReadBytes(buf []byte) (int, error) {
+
+	if b.closed {
+		return 0, ErrClosed
+	}
+
+	if len(buf) == 0 {
+		return 0, nil
+	}
+
+	c := b.conn
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cmd = c.cmd[:0]
+	c.start()
+	c.writeByte(b.addr<<1 | 1)
+
+	// ack every byte except the last to end the read
+	for i := range buf {
+		c.cmd = append(c.cmd,
+			mpsseSetLow, 0x00, ftInput,
+			mpsseReadBytesPos, 0x00, 0x00,
+			mpsseSetLow, 0x00, ftOutput,
+		)
+
+		ack := byte(0x00)
+
+		if i == len(buf)-1 {
+			ack = 0xFF
+		}
+
+		c.cmd = append(c.cmd, mpsseWriteBitsNeg, 0x00, ack)
+	}
+
+	c.stop()
+	c.cmd = append(c.cmd, mpsseSendImmediate)
+
+	resp, err := c.exchange(len(buf) + 1)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if resp[0]&0x01 != 0 {
+		return 0, fmt.Errorf("FT232H address: %w", vl53l1x.ErrNACK)
+	}
+
+	return copy(buf, resp[1:]), nil
+}
+
+// start queues an I2C start condition
+func (c *ftConn) start() {
+
+	c.idle()
+	c.pins(ftSCL)
+	c.pins(0x00)
+}
+
+// stop queues an I2C stop condition
+func (c *ftConn) stop() {
+
+	c.pins(0x00)
+	c.pins(ftSCL)
+	c.idle()
+}
+
+// idle queues both lines released high
+func (c *ftConn) idle() {
+	c.pins(ftSCL | ftSDA)
+}
+
+// pins queues setting SCL and SDA, repeated to hold the state
+func (c *ftConn) pins(state byte) {
+
+	for i := 0; i < ftRepeat; i++ {
+		c.cmd = append(c.cmd, mpsseSetLow, state, ftOutput)
+	}
+}
+
+// writeByte queues clocking out a byte and reading its ack bit
+func (c *ftConn) writeByte(d byte) {
+
+	c.cmd = append(c.cmd,
+		mpsseWriteBytesNeg, 0x00, 0x00, d,
+		mpsseSetLow, 0x00, ftInput,
+		mpsseReadBitsPos, 0x00,
+		mpsseSetLow, 0x00, ftOutput,
+	)
+}
+
+// exchange sends the queued commands and reads n response bytes
+func (c *ftConn) exchange(n int) ([]byte, error) {
+
+	if _, err := c.rw.Write(c.cmd); err != nil {
+		return nil, err
+	}
+
+	resp := make([]byte, n)
+
+	if _, err := io.ReadFull(c.rw, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
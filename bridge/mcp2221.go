@@ -0,0 +1,387 @@
+package bridge
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+// MCP2221 HID command codes
+const (
+	mcpStatus    = 0x10
+	mcpWriteData = 0x90
+	mcpReadData  = 0x91
+	mcpGetData   = 0x40
+)
+
+// MCP2221 response and I2C engine state codes
+const (
+	mcpOK          = 0x00
+	mcpCancel      = 0x10
+	mcpSetSpeed    = 0x20
+	mcpAddrNACK    = 0x25
+	mcpReadErr     = 0x7F
+	mcpStateIdle   = 0x00
+	mcpStateOffset = 8
+)
+
+// mcpReportSize is the size of the MCP2221 HID reports
+const mcpReportSize = 64
+
+// MCP2221MaxTransfer is the number of data bytes carried in one HID report,
+// which limits the size of a single read
+const MCP2221MaxTransfer = 60
+
+// mcpClock is the MCP2221 system clock used to derive the I2C clock divider
+const mcpClock = 12000000
+
+// mcpConn is the HID connection to the adapter shared by all devices opened
+// on it
+type mcpConn struct {
+	mu      sync.Mutex
+	hid     io.ReadWriter
+	closer  io.Closer
+	timeout time.Duration
+	report  [mcpReportSize]byte
+	resp    [mcpReportSize]byte
+	retries uint64
+}
+
+// MCP2221 is a vl53l1x.Bus to a device behind a Microchip MCP2221 or MCP2221A
+// USB to I2C adapter
+type MCP2221 struct {
+	conn   *mcpConn
+	addr   uint8
+	closed bool
+}
+
+// NewMCP2221 sets the adapters I2C clock rate in Hz and returns a Bus to the
+// device at addr.  hid exchanges 64 byte HID reports with the adapter, such
+// as a device opened with a HID library
+func NewMCP2221(hid io.ReadWriter, addr uint8, clockHz int) (*MCP2221, error) {
+
+	conn := &mcpConn{hid: hid, timeout: DefaultTimeout}
+
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+
+	// cancel any transfer left over from a previous session then set the
+	// clock rate
+	r := conn.clear(mcpStatus)
+	r[2] = mcpCancel
+
+	if _, err := conn.xfer(); err != nil {
+		return nil, fmt.Errorf("failed to reset MCP2221: %w", err)
+	}
+
+	if _, err := conn.setClock(clockHz); err != nil {
+		return nil, err
+	}
+
+	return &MCP2221{conn: conn, addr: addr}, nil
+}
+
+// OpenMCP2221 opens the adapter at the Linux hidraw device path, eg:
+// /dev/hidraw0, and returns a Bus to the device at addr.  The hidraw device
+// is closed when the returned Bus is closed
+func OpenMCP2221(path string, addr uint8, clockHz int) (*MCP2221, error) {
+
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := NewMCP2221(hidraw{f}, addr, clockHz)
+
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	b.conn.closer = f
+
+	return b, nil
+}
+
+// Open returns a Bus to the device at addr on the same adapter
+func (b *MCP2221) Open(addr uint8, dev string) (vl53l1x.Bus, error) {
+	return &MCP2221{conn: b.conn, addr: addr}, nil
+}
+
+// SetTimeout sets the time to wait for an I2C transaction to complete for all
+// devices on the adapter
+func (b *MCP2221) SetTimeout(d time.Duration) {
+
+	b.conn.mu.Lock()
+	defer b.conn.mu.Unlock()
+
+	b.conn.timeout = d
+}
+
+// GetAddr returns the I2C address of the device
+func (b *MCP2221) GetAddr() uint8 {
+	return b.addr
+}
+
+// GetDev returns the name of the adapter
+func (b *MCP2221) GetDev() string {
+	return "mcp2221"
+}
+
+// MaxTransferSize returns the number of bytes read in one HID report
+func (b *MCP2221) MaxTransferSize() int {
+	return MCP2221MaxTransfer
+}
+
+// Retries returns the number of commands resent as the I2C engine was busy
+func (b *MCP2221) Retries() uint64 {
+
+	b.conn.mu.Lock()
+	defer b.conn.mu.Unlock()
+
+	return b.conn.retries
+}
+
+// Close the device.  If the adapter was opened with OpenMCP2221() the hidraw
+// device is closed
+func (b *MCP2221) Close() error {
+
+	b.closed = true
+
+	if b.conn.closer != nil {
+		return b.conn.closer.Close()
+	}
+
+	return nil
+}
+
+// WriteBytes writes buf to the device
+func (b *MCP2221) WriteBytes(buf []byte) (int, error) {
+
+	if b.closed {
+		return 0, ErrClosed
+	}
+
+	if len(buf) > MCP2221MaxTransfer {
+		return 0, fmt.Errorf("write of %d bytes exceeds MCP2221 report", len(buf))
+	}
+
+	b.conn.mu.Lock()
+	defer b.conn.mu.Unlock()
+
+	if err := b.conn.command(mcpWriteData, b.addr<<1, len(buf), buf); err != nil {
+		return 0, err
+	}
+
+	if err := b.conn.waitIdle(); err != nil {
+		return 0, err
+	}
+
+	return len(buf), nil
+}
+
+// ReadBytes reads len(buf) bytes from the device
+func (b *MCP2221) ReadBytes(buf []byte) (int, error) {
+
+	if b.closed {
+		return 0, ErrClosed
+	}
+
+	b.conn.mu.Lock()
+	defer b.conn.mu.Unlock()
+
+	if err := b.conn.command(mcpReadData, b.addr<<1|1, len(buf), nil); err != nil {
+		return 0, err
+	}
+
+	total := 0
+	deadline := time.Now().Add(b.conn.timeout)
+
+	for total < len(buf) {
+
+		b.conn.clear(mcpGetData)
+		resp, err := b.conn.xfer()
+
+		if err != nil {
+			return total, err
+		}
+
+		if resp[2] == mcpAddrNACK {
+			return total, fmt.Errorf("MCP2221 address: %w", vl53l1x.ErrNACK)
+		}
+
+		if resp[1] != mcpOK || resp[3] == mcpReadErr {
+			// data not available yet
+			if time.Now().After(deadline) {
+				b.conn.cancel()
+				return total, fmt.Errorf("MCP2221 read did not complete within %s", b.conn.timeout)
+			}
+
+			continue
+		}
+
+		n := min(int(resp[3]), MCP2221MaxTransfer, len(buf)-total)
+		total += copy(buf[total:total+n], resp[4:4+n])
+	}
+
+	return total, nil
+}
+
+// SetClock sets the I2C clock rate closest to but not above hz for all devices
+// on the adapter and returns the rate set
+func (b *MCP2221) SetClock(hz int) (int, error) {
+
+	b.conn.mu.Lock()
+	defer b.conn.mu.Unlock()
+
+	return b.conn.setClock(hz)
+}
+
+// setClock sets the I2C clock divider for the rate hz
+func (c *mcpConn) setClock(hz int) (int, error) {
+
+	if hz <= 0 {
+		return 0, fmt.Errorf("invalid MCP2221 clock rate %dHz", hz)
+	}
+
+	// round the divider up so the rate does not exceed hz
+	div := (mcpClock+hz-1)/hz - 3
+
+	if div < 0 || div > 0xFF {
+		return 0, fmt.Errorf("MCP2221 can not clock I2C at %dHz", hz)
+	}
+
+	r := c.clear(mcpStatus)
+	r[3] = mcpSetSpeed
+	r[4] = byte(div)
+
+	resp, err := c.xfer()
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to set MCP2221 clock: %w", err)
+	}
+
+	if resp[3] != mcpSetSpeed {
+		return 0, fmt.Errorf("MCP2221 rejected clock rate of %dHz", hz)
+	}
+
+	return mcpClock / (div + 3), nil
+}
+
+// command sends an I2C read or write command, resending while the I2C engine
+// is busy
+func (c *mcpConn) command(cmd, addr byte, n int, data []byte) error {
+
+	deadline := time.Now().Add(c.timeout)
+
+	for {
+		r := c.clear(cmd)
+		r[1], r[2], r[3] = byte(n), byte(n>>8), addr
+		copy(r[4:], data)
+
+		resp, err := c.xfer()
+
+		if err != nil {
+			return err
+		}
+
+		if resp[1] == mcpOK {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			c.cancel()
+			return fmt.Errorf("MCP2221 I2C engine busy")
+		}
+
+		c.retries++
+	}
+}
+
+// waitIdle polls the I2C engine state until the transfer completes
+func (c *mcpConn) waitIdle() error {
+
+	deadline := time.Now().Add(c.timeout)
+
+	for {
+		c.clear(mcpStatus)
+		resp, err := c.xfer()
+
+		if err != nil {
+			return err
+		}
+
+		switch resp[mcpStateOffset] {
+		case mcpStateIdle:
+			return nil
+		case mcpAddrNACK:
+			c.cancel()
+			return fmt.Errorf("MCP2221 address: %w", vl53l1x.ErrNACK)
+		}
+
+		if time.Now().After(deadline) {
+			c.cancel()
+			return fmt.Errorf("MCP2221 write did not complete within %s", c.timeout)
+		}
+	}
+}
+
+// cancel aborts the current I2C transfer, best effort
+func (c *mcpConn) cancel() {
+
+	r := c.clear(mcpStatus)
+	r[2] = mcpCancel
+	c.xfer()
+}
+
+// clear zeroes the output report, sets its command and returns it
+func (c *mcpConn) clear(cmd byte) []byte {
+
+	c.report = [mcpReportSize]byte{}
+	c.report[0] = cmd
+
+	return c.report[:]
+}
+
+// xfer sends the output report and reads the response report
+func (c *mcpConn) xfer() ([]byte, error) {
+
+	if _, err := c.hid.Write(c.report[:]); err != nil {
+		return nil, err
+	}
+
+	if _, err := io.ReadFull(c.hid, c.resp[:]); err != nil {
+		return nil, err
+	}
+
+	if c.resp[0] != c.report[0] {
+		return nil, fmt.Errorf("MCP2221 response 0x%02X to command 0x%02X",
+			c.resp[0], c.report[0])
+	}
+
+	return c.resp[:], nil
+}
+
+// hidraw adapts a Linux hidraw device, which expects the report number before
+// each output report, the MCP2221 does not number its reports
+type hidraw struct {
+	f *os.File
+}
+
+// Write writes the report prefixed with report number 0
+func (h hidraw) Write(p []byte) (int, error) {
+
+	n, err := h.f.Write(append([]byte{0}, p...))
+
+	return max(n-1, 0), err
+}
+
+// Read reads an input report
+func (h hidraw) Read(p []byte) (int, error) {
+	return h.f.Read(p)
+}
@@ -0,0 +1,243 @@
+package bridge
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+// SC18IS602 function IDs sent as the first byte of each SPI frame
+const (
+	sc18WriteI2C   = 0x00
+	sc18ReadI2C    = 0x01
+	sc18ReadBuffer = 0x06
+	sc18Configure  = 0xF0
+	sc18Status     = 0xF4
+)
+
+// SC18IS602 I2C status codes returned by the status function
+const (
+	sc18Success   = 0xF0
+	sc18AddrNACK  = 0xF1
+	sc18DataNACK  = 0xF2
+	sc18Busy      = 0xF3
+	sc18TimedOut  = 0xF8
+	sc18BadLength = 0xF9
+)
+
+// SC18IS602BufferSize is the size of the bridges data buffer, which limits the
+// number of bytes in a single I2C transfer
+const SC18IS602BufferSize = 96
+
+// SC18Clock is the I2C clock rate of the SC18IS602, given for the 7.3728MHz
+// oscillator
+type SC18Clock uint8
+
+const (
+	SC18Clock369kHz SC18Clock = 0x00
+	SC18Clock246kHz SC18Clock = 0x01
+	SC18Clock61kHz  SC18Clock = 0x02
+	SC18Clock7kHz   SC18Clock = 0x03
+)
+
+// sc18Rates are the I2C clock rates in Hz of each SC18Clock setting
+var sc18Rates = map[SC18Clock]int{
+	SC18Clock369kHz: 369000,
+	SC18Clock246kHz: 246000,
+	SC18Clock61kHz:  61000,
+	SC18Clock7kHz:   7200,
+}
+
+// sc18Conn is the SPI connection to the bridge shared by all devices opened
+// on it
+type sc18Conn struct {
+	mu      sync.Mutex
+	spi     SPI
+	timeout time.Duration
+}
+
+// SC18IS602 is a vl53l1x.Bus to a device behind an NXP SC18IS602/603 SPI to
+// I2C bridge
+type SC18IS602 struct {
+	conn   *sc18Conn
+	addr   uint8
+	closed bool
+}
+
+// NewSC18IS602 configures the bridge on the SPI connection with the I2C clock
+// rate and returns a Bus to the device at addr.  The SPI connection is owned
+// by the caller and is not closed by Close()
+func NewSC18IS602(spi SPI, addr uint8, clock SC18Clock) (*SC18IS602, error) {
+
+	conn := &sc18Conn{spi: spi, timeout: DefaultTimeout}
+
+	if err := spi.Tx([]byte{sc18Configure, byte(clock)}, nil); err != nil {
+		return nil, fmt.Errorf("failed to configure SC18IS602: %w", err)
+	}
+
+	return &SC18IS602{conn: conn, addr: addr}, nil
+}
+
+// Open returns a Bus to the device at addr on the same bridge
+func (b *SC18IS602) Open(addr uint8, dev string) (vl53l1x.Bus, error) {
+	return &SC18IS602{conn: b.conn, addr: addr}, nil
+}
+
+// SetClock sets the fastest of the bridges fixed I2C clock rates not above hz
+// for all devices on the bridge and returns the rate set
+func (b *SC18IS602) SetClock(hz int) (int, error) {
+
+	clock, rate := SC18Clock7kHz, 0
+
+	for c, r := range sc18Rates {
+		if r <= hz && r > rate {
+			clock, rate = c, r
+		}
+	}
+
+	if rate == 0 {
+		return 0, fmt.Errorf("SC18IS602 can not clock I2C at %dHz", hz)
+	}
+
+	b.conn.mu.Lock()
+	defer b.conn.mu.Unlock()
+
+	if err := b.conn.spi.Tx([]byte{sc18Configure, byte(clock)}, nil); err != nil {
+		return 0, err
+	}
+
+	return rate, nil
+}
+
+// SetTimeout sets the time to wait for an I2C transaction to complete for all
+// devices on the bridge
+func (b *SC18IS602) SetTimeout(d time.Duration) {
+
+	b.conn.mu.Lock()
+	defer b.conn.mu.Unlock()
+
+	b.conn.timeout = d
+}
+
+// GetAddr returns the I2C address of the device
+func (b *SC18IS602) GetAddr() uint8 {
+	return b.addr
+}
+
+// GetDev returns the name of the bridge
+func (b *SC18IS602) GetDev() string {
+	return "sc18is602"
+}
+
+// MaxTransferSize returns the size of the bridges data buffer
+func (b *SC18IS602) MaxTransferSize() int {
+	return SC18IS602BufferSize
+}
+
+// Close the device, the SPI connection remains open
+func (b *SC18IS602) Close() error {
+
+	b.closed = true
+	return nil
+}
+
+// WriteBytes writes buf to the device
+func (b *SC18IS602) WriteBytes(buf []byte) (int, error) {
+
+	if b.closed {
+		return 0, ErrClosed
+	}
+
+	if len(buf) > SC18IS602BufferSize {
+		return 0, fmt.Errorf("write of %d bytes exceeds SC18IS602 buffer", len(buf))
+	}
+
+	b.conn.mu.Lock()
+	defer b.conn.mu.Unlock()
+
+	frame := make([]byte, 0, len(buf)+2)
+	frame = append(frame, sc18WriteI2C, b.addr<<1)
+	frame = append(frame, buf...)
+
+	if err := b.conn.spi.Tx(frame, nil); err != nil {
+		return 0, err
+	}
+
+	if err := b.conn.wait(); err != nil {
+		return 0, err
+	}
+
+	return len(buf), nil
+}
+
+// ReadBytes reads len(buf) bytes from the device
+func (b *SC18IS602) ReadBytes(buf []byte) (int, error) {
+
+	if b.closed {
+		return 0, ErrClosed
+	}
+
+	if len(buf) > SC18IS602BufferSize {
+		return 0, fmt.Errorf("read of %d bytes exceeds SC18IS602 buffer", len(buf))
+	}
+
+	b.conn.mu.Lock()
+	defer b.conn.mu.Unlock()
+
+	if err := b.conn.spi.Tx([]byte{sc18ReadI2C, byte(len(buf)), b.addr<<1 | 1}, nil); err != nil {
+		return 0, err
+	}
+
+	if err := b.conn.wait(); err != nil {
+		return 0, err
+	}
+
+	// the read data is clocked out of the bridges buffer after the function
+	// ID byte
+	w := make([]byte, len(buf)+1)
+	r := make([]byte, len(buf)+1)
+	w[0] = sc18ReadBuffer
+
+	if err := b.conn.spi.Tx(w, r); err != nil {
+		return 0, err
+	}
+
+	return copy(buf, r[1:]), nil
+}
+
+// wait polls the bridges I2C status until the transaction completes
+func (c *sc18Conn) wait() error {
+
+	deadline := time.Now().Add(c.timeout)
+	r := make([]byte, 2)
+
+	for {
+		if err := c.spi.Tx([]byte{sc18Status, 0x00}, r); err != nil {
+			return err
+		}
+
+		switch r[1] {
+		case sc18Success:
+			return nil
+		case sc18AddrNACK:
+			return fmt.Errorf("SC18IS602 address: %w", vl53l1x.ErrNACK)
+		case sc18DataNACK:
+			return fmt.Errorf("SC18IS602 data: %w", vl53l1x.ErrNACK)
+		case sc18TimedOut:
+			return fmt.Errorf("SC18IS602 I2C bus timed out")
+		case sc18BadLength:
+			return fmt.Errorf("SC18IS602 invalid transfer length")
+		case sc18Busy:
+		default:
+			return fmt.Errorf("SC18IS602 unknown status 0x%02X", r[1])
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("SC18IS602 transaction did not complete within %s", c.timeout)
+		}
+
+		time.Sleep(100 * time.Microsecond)
+	}
+}
@@ -0,0 +1,86 @@
+package vl53l1x
+
+import (
+	"context"
+	"time"
+)
+
+// Burst performs n single shot measurements back to back, waiting gap
+// between the end of one and the start of the next, for quick
+// characterization scans of a site
+func (v *VL53L1X) Burst(n int, gap time.Duration) ([]RangingData, error) {
+	return v.BurstContext(context.Background(), n, gap)
+}
+
+// BurstContext performs the measurements of Burst() with the waits bound by
+// the context.  The DSS switch and startup discard of ReadSingle() are made
+// once for the burst and each shot clears the interrupt only as it is
+// started.  Measurements are returned whatever their status, and on error
+// those taken so far are returned with it
+func (v *VL53L1X) BurstContext(ctx context.Context, n int, gap time.Duration) ([]RangingData, error) {
+
+	if n <= 0 {
+		return nil, nil
+	}
+
+	v.holdOff = 0
+
+	v.singleShot = true
+	defer func() { v.singleShot = false }()
+
+	restoreDSS, err := v.singleShotDSS()
+
+	if err != nil {
+		return nil, err
+	}
+
+	shots, err := v.burst(ctx, n, gap)
+
+	if rerr := restoreDSS(); err == nil {
+		err = rerr
+	}
+
+	return shots, err
+}
+
+// burst takes the startup discard and n single shot measurements
+func (v *VL53L1X) burst(ctx context.Context, n int, gap time.Duration) ([]RangingData, error) {
+
+	shots := make([]RangingData, 0, n)
+
+	for i := 0; i < v.startupDiscard; i++ {
+		if err := v.startSingle(); err != nil {
+			return shots, err
+		}
+
+		if _, err := v.read(ctx, true, false); err != nil {
+			return shots, err
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		if i > 0 && gap > 0 {
+			select {
+			case <-ctx.Done():
+				return shots, ctx.Err()
+			case <-time.After(gap):
+			}
+		}
+
+		if err := v.startSingle(); err != nil {
+			return shots, err
+		}
+
+		// the next shot clears the interrupt as it starts, so only the last
+		// clears it here
+		rData, err := v.readEvent(ctx, true, i == n-1)
+
+		if err != nil {
+			return shots, err
+		}
+
+		shots = append(shots, rData)
+	}
+
+	return shots, nil
+}
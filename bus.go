@@ -0,0 +1,44 @@
+package vl53l1x
+
+import "errors"
+
+// ErrI2CUnsupported is returned by NewFromPath() and DetectSensors() on
+// platforms without the Linux i2c-dev interface
+var ErrI2CUnsupported = errors.New("I2C device paths are only supported on Linux")
+
+// ErrNACK is wrapped by Bus transports in the error returned when the device
+// does not acknowledge a transfer, so it is counted in Stats()
+var ErrNACK = errors.New("device did not acknowledge")
+
+// Bus is the transport used to communicate with the sensor.  The go-i2c
+// Options type satisfies this interface so an opened I2C device can be passed
+// directly to New().  Other bus paths such as the bridges in the bridge package
+// are supported by implementing this interface, optionally along with
+// TransferSizer and RetryCounter
+type Bus interface {
+	// GetAddr returns the I2C address the transport is communicating with
+	GetAddr() uint8
+	// GetDev returns the device path of the transport
+	GetDev() string
+	// ReadBytes reads len(buf) bytes from the device
+	ReadBytes(buf []byte) (int, error)
+	// WriteBytes writes buf to the device
+	WriteBytes(buf []byte) (int, error)
+	// Close the transport
+	Close() error
+}
+
+// TransferSizer is an optional capability implemented by a Bus that can only
+// read a limited number of bytes in a single transfer, such as USB or SPI
+// bridges like the CH341 and FT232H
+type TransferSizer interface {
+	// MaxTransferSize returns the maximum number of bytes that can be read in
+	// a single transfer
+	MaxTransferSize() int
+}
+
+// MaxTransferSize returns the maximum number of bytes read from the bus in a
+// single transfer.  A value of 0 means reads are not split
+func (v *VL53L1X) MaxTransferSize() int {
+	return v.maxTransfer
+}
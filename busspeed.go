@@ -0,0 +1,123 @@
+package vl53l1x
+
+import "fmt"
+
+// I2C bus clock rates in Hz
+const (
+	// StandardMode is the 100kHz I2C standard mode rate, the default of most
+	// Linux I2C controllers
+	StandardMode = 100000
+	// FastMode is the 400kHz I2C fast mode rate
+	FastMode = 400000
+	// FastModePlus is the 1MHz I2C fast mode plus rate, the maximum the sensor
+	// supports
+	FastModePlus = 1000000
+)
+
+// ClockSetter is an optional capability implemented by a Bus that can change
+// its I2C clock rate, such as USB and SPI bridges
+type ClockSetter interface {
+	// SetClock sets the clock rate closest to but not above hz and returns the
+	// rate set
+	SetClock(hz int) (int, error)
+}
+
+// SetBusSpeed requests the I2C clock rate in Hz, up to FastModePlus.  If the
+// transport implements ClockSetter the clock is changed and the rate it
+// settled on is recorded, otherwise the rate is taken as a hint of the clock
+// configured outside the driver, eg: by the i2c_arm_baudrate device tree
+// parameter on a Raspberry Pi
+func (v *VL53L1X) SetBusSpeed(hz int) error {
+
+	if hz <= 0 || hz > FastModePlus {
+		return fmt.Errorf("bus speed %dHz not supported, maximum is %dHz", hz, FastModePlus)
+	}
+
+	cs, ok := v.bus.(ClockSetter)
+
+	if !ok {
+		v.log.Printf("Transport can not set bus speed, assuming %dHz", hz)
+		v.busSpeed = hz
+		return nil
+	}
+
+	set, err := cs.SetClock(hz)
+
+	if err != nil {
+		return fmt.Errorf("failed to set bus speed to %dHz: %w", hz, err)
+	}
+
+	if set != hz {
+		v.log.Printf("Bus speed %dHz requested, transport set %dHz", hz, set)
+	}
+
+	v.busSpeed = set
+	v.checkBusRate()
+
+	return nil
+}
+
+// BusSpeed returns the I2C clock rate in Hz set with SetBusSpeed(), or
+// StandardMode if it has not been set
+func (v *VL53L1X) BusSpeed() int {
+
+	if v.busSpeed == 0 {
+		return StandardMode
+	}
+
+	return v.busSpeed
+}
+
+// MaxBusRate returns the maximum number of measurements per second the bus
+// can carry at BusSpeed(), from the transfers made for each measurement by
+// Read(): one data ready poll, the result block read, the DSS update and the
+// interrupt clear.  Additional data ready polls while waiting reduce the rate
+// further
+func (v *VL53L1X) MaxBusRate() float64 {
+
+	resultSize := resultBufferSize
+
+	if v.readSD1 {
+		resultSize = resultBufferSizeSD1
+	}
+
+	bits := readBits(1) + readBits(resultSize) + writeBits(4) + writeBits(3)
+
+	return float64(v.BusSpeed()) / float64(bits)
+}
+
+// checkBusRate logs a warning when continuous ranging produces measurements
+// faster than the bus can read them
+func (v *VL53L1X) checkBusRate() {
+
+	// back to back ranging measures once per timing budget
+	period := v.periodMs
+
+	if period == 0 {
+		period = v.timingBudget
+	}
+
+	if !v.ranging || period == 0 {
+		return
+	}
+
+	rate := 1000 / float64(period)
+
+	if limit := v.MaxBusRate(); rate > limit {
+		v.log.Printf("Measurement rate of %.1fHz exceeds %.1fHz the bus can carry at %dHz",
+			rate, limit, v.BusSpeed())
+	}
+}
+
+// writeBits returns the bits clocked on the bus to write n bytes, being the
+// start condition, address byte, data bytes each with an ack bit and the stop
+// condition
+func writeBits(n int) int {
+	return 1 + 9*(n+1) + 1
+}
+
+// readBits returns the bits clocked on the bus to read n bytes from a
+// register, which is a write of the 16-bit register address then the read
+func readBits(n int) int {
+	return writeBits(2) + writeBits(n)
+}
@@ -0,0 +1,277 @@
+package vl53l1x
+
+import (
+	"context"
+	"fmt"
+)
+
+// range offset limits in millimeters of the 13 bit 11.2 fixed point
+// ALGO_PART_TO_PART_RANGE_OFFSET_MM register
+const (
+	minRangeOffsetMM int16 = -1024
+	maxRangeOffsetMM int16 = 1023
+)
+
+// calibrationSamples is the number of measurements averaged by the calibration
+// routines, matching ST's ULD
+const calibrationSamples = 50
+
+// CalibrationData holds the results of offset and crosstalk calibration so
+// they can be stored and reapplied without recalibrating the sensor
+type CalibrationData struct {
+	// OffsetMM is the part to part range offset in millimeters
+	OffsetMM int16
+	// XtalkKCPS is the crosstalk compensation plane offset in kcps per SPAD
+	// in 7.9 fixed point format
+	XtalkKCPS uint16
+	// XtalkXGradientKCPS and XtalkYGradientKCPS are the crosstalk plane
+	// gradients of XtalkPlane
+	XtalkXGradientKCPS int16
+	XtalkYGradientKCPS int16
+	// Zones are the inner and outer zone offsets from CalibrateZoneOffsets()
+	Zones ZoneOffsets
+}
+
+// CalibrateOffset performs offset calibration with a target placed at the
+// given distance in millimeters, based on VL53L1X_CalibrateOffset() from the
+// ULD.  ST recommends a white target at 140mm in the dark.  The calculated
+// offset is applied to the sensor and returned in millimeters
+func (v *VL53L1X) CalibrateOffset(targetMM uint16) (int16, error) {
+
+	end := v.traceOp(SpanCalibrateOffset)
+	offset, err := v.calibrateOffset(targetMM)
+	end(err)
+
+	return offset, err
+}
+
+// calibrateOffset performs the offset calibration of CalibrateOffset()
+func (v *VL53L1X) calibrateOffset(targetMM uint16) (int16, error) {
+
+	v.log.Printf("Calibrating offset with target at %dmm", targetMM)
+
+	// clear existing offsets so raw distances are measured
+	if err := v.SetRangeOffsetMM(0); err != nil {
+		return 0, err
+	}
+
+	avgRange, _, _, err := v.sampleCalibration()
+
+	if err != nil {
+		return 0, err
+	}
+
+	// the offset is applied by the sensor before the range gain correction
+	// so scale the difference back out of corrected units
+	offset := (float64(targetMM) - avgRange) * 0x0800 / 2011
+
+	if offset < float64(minRangeOffsetMM) || offset > float64(maxRangeOffsetMM) {
+		return 0, fmt.Errorf("calculated offset of %.0fmm is outside range %d to %dmm, check target placement",
+			offset, minRangeOffsetMM, maxRangeOffsetMM)
+	}
+
+	if err := v.writeRangeOffset(int16(offset)); err != nil {
+		return 0, err
+	}
+
+	return int16(offset), nil
+}
+
+// CalibrateXtalk performs crosstalk calibration with a grey 17% target placed
+// at the given distance in millimeters, based on VL53L1X_CalibrateXtalk() from
+// the ULD.  The distance should be where the sensor starts to under-range
+// through the cover glass.  The calculated compensation is applied to the
+// sensor and returned in 7.9 fixed point kcps per SPAD
+func (v *VL53L1X) CalibrateXtalk(targetMM uint16) (uint16, error) {
+
+	end := v.traceOp(SpanCalibrateXtalk)
+	xtalk, err := v.calibrateXtalk(targetMM)
+	end(err)
+
+	return xtalk, err
+}
+
+// calibrateXtalk performs the crosstalk calibration of CalibrateXtalk()
+func (v *VL53L1X) calibrateXtalk(targetMM uint16) (uint16, error) {
+
+	if targetMM == 0 {
+		return 0, fmt.Errorf("target distance must be greater than zero")
+	}
+
+	v.log.Printf("Calibrating crosstalk with target at %dmm", targetMM)
+
+	if err := v.writeXtalk(0); err != nil {
+		return 0, err
+	}
+
+	avgRange, avgSignal, avgSpads, err := v.sampleCalibration()
+
+	if err != nil {
+		return 0, err
+	}
+
+	if avgSpads == 0 {
+		return 0, fmt.Errorf("no SPADs enabled during calibration")
+	}
+
+	xtalk := 512 * (avgSignal * (1 - avgRange/float64(targetMM))) / avgSpads
+
+	if xtalk < 0 {
+		xtalk = 0
+	} else if xtalk > 0xFFFF {
+		xtalk = 0xFFFF
+	}
+
+	if err := v.writeXtalk(uint16(xtalk)); err != nil {
+		return 0, err
+	}
+
+	return uint16(xtalk), nil
+}
+
+// GetCalibration reads the offsets and crosstalk compensation currently
+// applied to the sensor
+func (v *VL53L1X) GetCalibration() (CalibrationData, error) {
+
+	offset, err := v.readRangeOffset()
+
+	if err != nil {
+		return CalibrationData{}, err
+	}
+
+	xtalk, err := v.GetXtalkPlane()
+
+	if err != nil {
+		return CalibrationData{}, err
+	}
+
+	zones, err := v.GetZoneOffsets()
+
+	if err != nil {
+		return CalibrationData{}, err
+	}
+
+	return CalibrationData{
+		OffsetMM:           offset,
+		XtalkKCPS:          xtalk.OffsetKCPS,
+		XtalkXGradientKCPS: xtalk.XGradientKCPS,
+		XtalkYGradientKCPS: xtalk.YGradientKCPS,
+		Zones:              zones,
+	}, nil
+}
+
+// SetCalibration applies previously obtained offset, zone offset and crosstalk
+// calibration to the sensor
+func (v *VL53L1X) SetCalibration(cal CalibrationData) error {
+
+	if err := v.writeRangeOffset(cal.OffsetMM); err != nil {
+		return err
+	}
+
+	if err := v.SetZoneOffsets(cal.Zones); err != nil {
+		return err
+	}
+
+	return v.SetXtalkPlane(XtalkPlane{
+		OffsetKCPS:    cal.XtalkKCPS,
+		XGradientKCPS: cal.XtalkXGradientKCPS,
+		YGradientKCPS: cal.XtalkYGradientKCPS,
+	})
+}
+
+// sampleCalibration ranges continuously for calibrationSamples measurements
+// and returns the average range in millimeters, signal rate in kcps and
+// number of enabled SPADs.  Continuous ranging started by the caller is
+// restarted afterwards with the same period
+func (v *VL53L1X) sampleCalibration() (avgRange, avgSignal, avgSpads float64, err error) {
+
+	resume, err := v.suspendRanging()
+
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	if err := v.StartContinuous(v.timingBudget + 5); err != nil {
+		return 0, 0, 0, err
+	}
+
+	for i := 0; i < calibrationSamples; i++ {
+
+		rData, err := v.readEvent(context.Background(), true, true)
+
+		if err != nil {
+			v.StopContinuous()
+			resume()
+			return 0, 0, 0, fmt.Errorf("calibration sample %d failed: %w", i, err)
+		}
+
+		avgRange += float64(rData.RangeMM)
+		avgSignal += float64(rData.Raw.peakSignalMCPS()) * 1000
+		avgSpads += float64(v.results.dssActualEffectiveSpadsSD0) / 256
+	}
+
+	if err := v.StopContinuous(); err != nil {
+		return 0, 0, 0, err
+	}
+
+	if err := resume(); err != nil {
+		return 0, 0, 0, err
+	}
+
+	return avgRange / calibrationSamples, avgSignal / calibrationSamples,
+		avgSpads / calibrationSamples, nil
+}
+
+// SetRangeOffsetMM applies a known range offset in millimeters, which is
+// added to measured distances, without running CalibrateOffset().  The offset
+// must be between -1024 and 1023mm.  As with VL53L1X_SetOffset() in the ULD
+// the inner and outer MM config offsets are cleared so only this offset is
+// applied
+func (v *VL53L1X) SetRangeOffsetMM(offsetMM int16) error {
+
+	if offsetMM < minRangeOffsetMM || offsetMM > maxRangeOffsetMM {
+		return fmt.Errorf("range offset %dmm outside of %d to %dmm",
+			offsetMM, minRangeOffsetMM, maxRangeOffsetMM)
+	}
+
+	if err := v.writeRangeOffset(offsetMM); err != nil {
+		return err
+	}
+
+	if err := v.writeReg16Bit(MM_CONFIG_INNER_OFFSET_MM, 0); err != nil {
+		return err
+	}
+
+	return v.writeReg16Bit(MM_CONFIG_OUTER_OFFSET_MM, 0)
+}
+
+// GetRangeOffsetMM returns the range offset in millimeters applied to
+// measured distances
+func (v *VL53L1X) GetRangeOffsetMM() (int16, error) {
+	return v.readRangeOffset()
+}
+
+// writeRangeOffset writes the part to part range offset in millimeters which
+// the sensor stores in 11.2 fixed point 2's complement format
+func (v *VL53L1X) writeRangeOffset(offsetMM int16) error {
+	return v.writeReg16Bit(ALGO_PART_TO_PART_RANGE_OFFSET_MM, uint16(offsetMM*4)&0x1FFF)
+}
+
+// readRangeOffset reads the part to part range offset in millimeters
+func (v *VL53L1X) readRangeOffset() (int16, error) {
+
+	val, err := v.readReg16Bit(ALGO_PART_TO_PART_RANGE_OFFSET_MM)
+
+	if err != nil {
+		return 0, err
+	}
+
+	// sign extend the 13 bit value then remove the 2 fractional bits
+	return int16(val<<3) >> 5, nil
+}
+
+// writeXtalk writes the crosstalk compensation plane offset in 7.9 fixed point
+// kcps per SPAD with the plane gradients cleared
+func (v *VL53L1X) writeXtalk(kcps uint16) error {
+	return v.SetXtalkPlane(XtalkPlane{OffsetKCPS: kcps})
+}
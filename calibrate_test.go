@@ -0,0 +1,114 @@
+package vl53l1x_test
+
+import (
+	"testing"
+
+	"github.com/swdee/go-vl53l1x"
+	"github.com/swdee/go-vl53l1x/sim"
+)
+
+func TestCalibrateOffsetOutOfRange(t *testing.T) {
+
+	v, bus := newSensor(t)
+
+	sc := sim.DefaultScene()
+	sc.DistanceMM = 100
+	bus.SetScene(sc)
+
+	// a target 1.4m further than measured needs an offset beyond 1023mm
+	if _, err := v.CalibrateOffset(1500); err == nil {
+		t.Fatal("expected error for offset out of range")
+	}
+
+	cal, err := v.GetCalibration()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cal.OffsetMM != 0 {
+		t.Errorf("offset = %dmm, want 0 after rejected calibration", cal.OffsetMM)
+	}
+}
+
+func TestCalibrateOffsetRestoresRanging(t *testing.T) {
+
+	v, bus := newSensor(t)
+
+	sc := sim.DefaultScene()
+	sc.DistanceMM = 140
+	bus.SetScene(sc)
+
+	if err := v.StartContinuous(25); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := v.CalibrateOffset(140); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := v.Read(true); err != nil {
+		t.Fatalf("Read after calibration: %v", err)
+	}
+}
+
+func TestCalibrateXtalkIntegerResults(t *testing.T) {
+
+	// the target under-ranges as it would through cover glass
+	sc := sim.DefaultScene()
+	sc.DistanceMM = 500
+	sc.Reflectance = 0.17
+
+	var want uint16
+
+	for _, integer := range []bool{false, true} {
+
+		var opts []vl53l1x.Option
+
+		if integer {
+			opts = append(opts, vl53l1x.WithIntegerResults())
+		}
+
+		v, bus := newSensor(t, opts...)
+		bus.SetScene(sc)
+
+		xtalk, err := v.CalibrateXtalk(600)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !integer {
+			want = xtalk
+			continue
+		}
+
+		if xtalk == 0 || xtalk != want {
+			t.Errorf("crosstalk with integer results = %d, want %d", xtalk, want)
+		}
+	}
+}
+
+func TestSetXtalkPlaneRebasesAutoCorrect(t *testing.T) {
+
+	v, bus := newSensor(t, vl53l1x.WithXtalkAutoCorrect(vl53l1x.XtalkCorrectConfig{}))
+
+	if err := v.SetXtalkPlane(vl53l1x.XtalkPlane{OffsetKCPS: 2 * 512}); err != nil {
+		t.Fatal(err)
+	}
+
+	if base, current := v.XtalkCorrection(); base != 2*512 || current != 2*512 {
+		t.Fatalf("correction = %d/%d, want 1024/1024", base, current)
+	}
+
+	// resetting restores the plane set rather than that before it
+	if err := v.ResetXtalkCorrection(); err != nil {
+		t.Fatal(err)
+	}
+
+	b := readReg(t, bus, vl53l1x.ALGO_CROSSTALK_COMPENSATION_PLANE_OFFSET_KCPS, 2)
+
+	if got := uint16(b[0])<<8 | uint16(b[1]); got != 2*512 {
+		t.Errorf("plane offset = %d, want 1024", got)
+	}
+}
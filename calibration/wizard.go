@@ -0,0 +1,199 @@
+// Package calibration provides a step driven wizard that guides a user through
+// offset and crosstalk calibration of a VL53L1X sensor, prompting for target
+// placement and validating the results.  It has no user interface of its own
+// so can be driven from a CLI or GUI.
+package calibration
+
+import (
+	"fmt"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+// Calibrator is the sensor interface used by the Wizard, satisfied by
+// *vl53l1x.VL53L1X
+type Calibrator interface {
+	CalibrateOffset(targetMM uint16) (int16, error)
+	CalibrateXtalk(targetMM uint16) (uint16, error)
+	GetCalibration() (vl53l1x.CalibrationData, error)
+	SetCalibration(cal vl53l1x.CalibrationData) error
+}
+
+// Step identifies a stage of the calibration wizard
+type Step int
+
+const (
+	// PlaceOffsetTarget prompts the user to place the offset target
+	PlaceOffsetTarget Step = iota
+	// PlaceXtalkTarget prompts the user to place the crosstalk target
+	PlaceXtalkTarget
+	// Done indicates calibration has finished
+	Done
+)
+
+// String implement Stringer interface for Step
+func (s Step) String() string {
+	switch s {
+	case PlaceOffsetTarget:
+		return "place offset target"
+	case PlaceXtalkTarget:
+		return "place crosstalk target"
+	case Done:
+		return "done"
+	default:
+		return "unknown step"
+	}
+}
+
+// Config holds the wizard target distances and validation limits
+type Config struct {
+	// OffsetTargetMM is the distance of the offset calibration target
+	OffsetTargetMM uint16
+	// XtalkTargetMM is the distance of the crosstalk calibration target
+	XtalkTargetMM uint16
+	// MaxOffsetMM is the largest offset magnitude accepted as valid
+	MaxOffsetMM int16
+	// MaxXtalkKCPS is the largest crosstalk compensation accepted as valid in
+	// 7.9 fixed point kcps
+	MaxXtalkKCPS uint16
+	// SkipXtalk skips crosstalk calibration for sensors without cover glass
+	SkipXtalk bool
+}
+
+// DefaultConfig returns the wizard configuration recommended by ST
+func DefaultConfig() Config {
+	return Config{
+		OffsetTargetMM: 140,
+		XtalkTargetMM:  600,
+		MaxOffsetMM:    100,
+		MaxXtalkKCPS:   0xFFFF,
+	}
+}
+
+// Prompt describes the action the user must take for the current step
+type Prompt struct {
+	Step    Step
+	Message string
+}
+
+// Wizard walks through calibration one step at a time.  Call NextStep() to
+// get the prompt to show the user, then Feedback() once they have acted on it
+type Wizard struct {
+	sensor Calibrator
+	cfg    Config
+	step   Step
+	result vl53l1x.CalibrationData
+}
+
+// NewWizard returns a calibration wizard for the sensor
+func NewWizard(sensor Calibrator, cfg Config) *Wizard {
+	return &Wizard{
+		sensor: sensor,
+		cfg:    cfg,
+		step:   PlaceOffsetTarget,
+	}
+}
+
+// NextStep returns the prompt for the current step of the wizard
+func (w *Wizard) NextStep() Prompt {
+
+	switch w.step {
+	case PlaceOffsetTarget:
+		return Prompt{
+			Step: w.step,
+			Message: fmt.Sprintf("Place a white target %dmm from the sensor in the "+
+				"dark then confirm", w.cfg.OffsetTargetMM),
+		}
+	case PlaceXtalkTarget:
+		return Prompt{
+			Step: w.step,
+			Message: fmt.Sprintf("Place a grey 17%% target %dmm from the sensor "+
+				"behind the cover glass then confirm", w.cfg.XtalkTargetMM),
+		}
+	default:
+		return Prompt{Step: Done, Message: "Calibration complete"}
+	}
+}
+
+// Feedback reports whether the user completed the current prompt.  If
+// confirmed the step's calibration is run and validated, advancing the wizard
+// on success.  On error the sensor's previous calibration is restored and the
+// wizard stays on the same step so it can be retried.  Declining a step skips
+// it
+func (w *Wizard) Feedback(confirmed bool) error {
+
+	prev, err := w.sensor.GetCalibration()
+
+	if err != nil {
+		return fmt.Errorf("failed to read calibration: %w", err)
+	}
+
+	switch w.step {
+	case PlaceOffsetTarget:
+		if confirmed {
+			offset, err := w.sensor.CalibrateOffset(w.cfg.OffsetTargetMM)
+
+			if err != nil {
+				return w.restore(prev, fmt.Errorf("offset calibration failed: %w", err))
+			}
+
+			if offset > w.cfg.MaxOffsetMM || offset < -w.cfg.MaxOffsetMM {
+				return w.restore(prev, fmt.Errorf("offset of %dmm exceeds limit of %dmm, check target placement",
+					offset, w.cfg.MaxOffsetMM))
+			}
+		}
+
+		w.step = PlaceXtalkTarget
+
+		if w.cfg.SkipXtalk {
+			w.step = Done
+		}
+
+	case PlaceXtalkTarget:
+		if confirmed {
+			xtalk, err := w.sensor.CalibrateXtalk(w.cfg.XtalkTargetMM)
+
+			if err != nil {
+				return w.restore(prev, fmt.Errorf("crosstalk calibration failed: %w", err))
+			}
+
+			if xtalk > w.cfg.MaxXtalkKCPS {
+				return w.restore(prev, fmt.Errorf("crosstalk of %d exceeds limit of %d, check cover glass",
+					xtalk, w.cfg.MaxXtalkKCPS))
+			}
+		}
+
+		w.step = Done
+	}
+
+	// keep the complete calibration the sensor is now using, including the
+	// zone offsets and crosstalk gradients not set by the wizard
+	if w.result, err = w.sensor.GetCalibration(); err != nil {
+		return fmt.Errorf("failed to read calibration: %w", err)
+	}
+
+	return nil
+}
+
+// restore applies the calibration the sensor had before a failed step,
+// returning the step's error
+func (w *Wizard) restore(prev vl53l1x.CalibrationData, err error) error {
+
+	if rerr := w.sensor.SetCalibration(prev); rerr != nil {
+		return fmt.Errorf("%w, restoring previous calibration failed: %v", err, rerr)
+	}
+
+	return err
+}
+
+// Done returns true once all steps are complete
+func (w *Wizard) Done() bool {
+	return w.step == Done
+}
+
+// Result returns the sensor's calibration once the wizard has finished,
+// including zone offsets and crosstalk gradients so it can be reapplied with
+// SetCalibration()
+func (w *Wizard) Result() vl53l1x.CalibrationData {
+	return w.result
+}
@@ -0,0 +1,95 @@
+package calibration_test
+
+import (
+	"testing"
+
+	"github.com/swdee/go-vl53l1x"
+	"github.com/swdee/go-vl53l1x/calibration"
+)
+
+// fakeSensor is a Calibrator applying fixed calibration results
+type fakeSensor struct {
+	cal    vl53l1x.CalibrationData
+	offset int16
+	xtalk  uint16
+}
+
+func (f *fakeSensor) CalibrateOffset(uint16) (int16, error) {
+
+	// as the driver, offset calibration clears the zone offsets
+	f.cal.OffsetMM = f.offset
+	f.cal.Zones = vl53l1x.ZoneOffsets{}
+
+	return f.offset, nil
+}
+
+func (f *fakeSensor) CalibrateXtalk(uint16) (uint16, error) {
+
+	f.cal.XtalkKCPS = f.xtalk
+
+	return f.xtalk, nil
+}
+
+func (f *fakeSensor) GetCalibration() (vl53l1x.CalibrationData, error) {
+	return f.cal, nil
+}
+
+func (f *fakeSensor) SetCalibration(cal vl53l1x.CalibrationData) error {
+
+	f.cal = cal
+
+	return nil
+}
+
+func TestWizardRejectedOffsetRestored(t *testing.T) {
+
+	prev := vl53l1x.CalibrationData{
+		OffsetMM: 12,
+		Zones:    vl53l1x.ZoneOffsets{InnerMM: 3, OuterMM: -4},
+	}
+
+	sensor := &fakeSensor{cal: prev, offset: 500}
+	w := calibration.NewWizard(sensor, calibration.DefaultConfig())
+
+	if err := w.Feedback(true); err == nil {
+		t.Fatal("expected offset beyond MaxOffsetMM to be rejected")
+	}
+
+	if sensor.cal != prev {
+		t.Errorf("calibration = %+v, want previous %+v restored", sensor.cal, prev)
+	}
+
+	if got := w.NextStep().Step; got != calibration.PlaceOffsetTarget {
+		t.Errorf("step = %v, want %v", got, calibration.PlaceOffsetTarget)
+	}
+}
+
+func TestWizardResultIncludesZones(t *testing.T) {
+
+	zones := vl53l1x.ZoneOffsets{InnerMM: 3, OuterMM: -4}
+	sensor := &fakeSensor{
+		cal:   vl53l1x.CalibrationData{Zones: zones, XtalkXGradientKCPS: 7},
+		xtalk: 40,
+	}
+
+	w := calibration.NewWizard(sensor, calibration.DefaultConfig())
+
+	// skip offset calibration so the zone offsets are kept
+	if err := w.Feedback(false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Feedback(true); err != nil {
+		t.Fatal(err)
+	}
+
+	if !w.Done() {
+		t.Fatal("wizard not done")
+	}
+
+	want := vl53l1x.CalibrationData{Zones: zones, XtalkKCPS: 40, XtalkXGradientKCPS: 7}
+
+	if got := w.Result(); got != want {
+		t.Errorf("Result() = %+v, want %+v", got, want)
+	}
+}
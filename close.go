@@ -0,0 +1,60 @@
+package vl53l1x
+
+import "errors"
+
+// ErrClosed is returned by operations on the sensor after Close() has
+// released it
+var ErrClosed = errors.New("sensor closed")
+
+// Pin is a digital output such as a GPIO line, used to drive the sensors
+// XSHUT pin
+type Pin interface {
+	// Out sets the pin high or low
+	Out(high bool) error
+}
+
+// Close releases the sensor.  Continuous ranging is stopped if active or
+// paused, the sensor is powered down if an XSHUT pin was set with
+// WithShutdownPin(), the Events() channel is closed and the bus is closed if
+// it was opened by the driver.  Operations on the sensor after it is closed
+// return ErrClosed
+func (v *VL53L1X) Close() error {
+
+	if v.closed {
+		return nil
+	}
+
+	var errs []error
+
+	// a paused sensor still holds the calibration programmed while ranging
+	if v.ranging || v.paused {
+		if err := v.StopContinuous(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if v.xshut != nil {
+		if err := v.xshut.Out(false); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	v.closed = true
+	v.events.mu.Lock()
+
+	if v.events.ch != nil {
+		close(v.events.ch)
+		v.events.ch = nil
+	}
+
+	v.events.closed = true
+	v.events.mu.Unlock()
+
+	if v.ownsBus {
+		if err := v.bus.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
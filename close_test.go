@@ -0,0 +1,59 @@
+package vl53l1x_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+func TestCloseStopsPausedRanging(t *testing.T) {
+
+	v, bus := newSensor(t)
+
+	if err := v.StartContinuous(25); err != nil {
+		t.Fatal(err)
+	}
+
+	// the first measurement programs the manual calibration kept by Pause()
+	if _, err := v.Read(true); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := v.Pause(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := v.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := readReg(t, bus, vl53l1x.PHASECAL_CONFIG_OVERRIDE, 1)[0]; got != 0 {
+		t.Errorf("PHASECAL_CONFIG_OVERRIDE = 0x%02X after Close, want 0x00", got)
+	}
+
+	if v.Paused() {
+		t.Error("sensor still paused after Close")
+	}
+}
+
+func TestClosedSensor(t *testing.T) {
+
+	v, _ := newSensor(t)
+
+	if err := v.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := v.Read(false); !errors.Is(err, vl53l1x.ErrClosed) {
+		t.Errorf("Read after Close = %v, want ErrClosed", err)
+	}
+
+	if err := v.StartContinuous(25); !errors.Is(err, vl53l1x.ErrClosed) {
+		t.Errorf("StartContinuous after Close = %v, want ErrClosed", err)
+	}
+
+	if _, ok := <-v.Events(); ok {
+		t.Error("Events after Close returned an open channel")
+	}
+}
@@ -0,0 +1,48 @@
+//go:build linux
+
+// Command i2c-agent serves an I2C bus over TCP to remote.Dial() clients, so
+// applications using the sensor can be run and debugged on a workstation
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/swdee/go-i2c"
+	"github.com/swdee/go-vl53l1x"
+	"github.com/swdee/go-vl53l1x/remote"
+)
+
+func main() {
+
+	i2cbus := flag.String("b", "/dev/i2c-1", "Path to I2C bus to serve")
+	listen := flag.String("l", fmt.Sprintf(":%d", remote.DefaultPort), "Address to listen on")
+	flag.Parse()
+
+	logger := log.New(os.Stderr, "", log.LstdFlags)
+
+	agent := remote.NewAgent(func(addr uint8) (vl53l1x.Bus, error) {
+		return i2c.New(addr, *i2cbus)
+	}, logger)
+
+	l, err := net.Listen("tcp", *listen)
+
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger.Printf("Serving %s on %s", *i2cbus, l.Addr())
+
+	if err := agent.Serve(ctx, l); err != nil {
+		logger.Fatal(err)
+	}
+}
@@ -0,0 +1,102 @@
+// Command vl53l1x-calibrate walks through offset and crosstalk calibration of
+// a sensor with the calibration wizard, prompting on the terminal for the
+// targets to be placed, and saves the result to a FileStore that can be
+// loaded with WithStore() or the vl53l1xd calibration setting.
+//
+//	vl53l1x-calibrate -b /dev/i2c-1 -o /var/lib/vl53l1x/sensor.json
+//
+// Answer y to run a step once the target is in place, n to skip it or q to
+// quit without saving.  A failed step can be retried after moving the target.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/swdee/go-vl53l1x"
+	"github.com/swdee/go-vl53l1x/calibration"
+	"github.com/swdee/go-vl53l1x/sim"
+)
+
+func main() {
+
+	i2cbus := flag.String("b", "/dev/i2c-1", "Path to I2C bus to use")
+	addr := flag.Uint("a", uint(vl53l1x.Address), "I2C address of the sensor")
+	out := flag.String("o", "calibration.json", "Path of the file to save calibration to")
+	simulate := flag.Bool("sim", false, "Use a simulated sensor instead of the I2C bus")
+
+	cfg := calibration.DefaultConfig()
+	offsetMM := flag.Uint("offset", uint(cfg.OffsetTargetMM), "Offset target distance in mm")
+	xtalkMM := flag.Uint("xtalk", uint(cfg.XtalkTargetMM), "Crosstalk target distance in mm")
+	flag.BoolVar(&cfg.SkipXtalk, "skip-xtalk", false, "Skip crosstalk calibration for sensors without cover glass")
+	flag.Parse()
+
+	cfg.OffsetTargetMM = uint16(*offsetMM)
+	cfg.XtalkTargetMM = uint16(*xtalkMM)
+
+	sensor, err := open(*i2cbus, uint8(*addr), *simulate, cfg.OffsetTargetMM)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer sensor.Close()
+
+	w := calibration.NewWizard(sensor, cfg)
+	in := bufio.NewScanner(os.Stdin)
+
+	for !w.Done() {
+
+		fmt.Printf("%s [y/n/q]: ", w.NextStep().Message)
+
+		if !in.Scan() {
+			log.Fatal("calibration aborted")
+		}
+
+		var confirmed bool
+
+		switch strings.ToLower(strings.TrimSpace(in.Text())) {
+		case "y", "yes":
+			confirmed = true
+		case "n", "no":
+		case "q", "quit":
+			log.Fatal("calibration aborted")
+		default:
+			continue
+		}
+
+		if err := w.Feedback(confirmed); err != nil {
+			fmt.Printf("%v, try again\n", err)
+		}
+	}
+
+	cal := w.Result()
+
+	if err := vl53l1x.NewFileStore(*out).SaveCalibration(cal); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("Offset %dmm, zones %d/%dmm, crosstalk %d saved to %s\n", cal.OffsetMM,
+		cal.Zones.InnerMM, cal.Zones.OuterMM, cal.XtalkKCPS, *out)
+}
+
+// open returns the sensor at addr on the I2C bus at path, or a simulated
+// sensor with a target at targetMM
+func open(path string, addr uint8, simulate bool, targetMM uint16) (*vl53l1x.VL53L1X, error) {
+
+	if !simulate {
+		return vl53l1x.NewFromPath(path, addr)
+	}
+
+	bus := sim.New(addr)
+
+	scene := sim.DefaultScene()
+	scene.DistanceMM = float64(targetMM)
+	bus.SetScene(scene)
+
+	return vl53l1x.New(bus, vl53l1x.DefaultDistanceMode, vl53l1x.DefaultTimingBudget)
+}
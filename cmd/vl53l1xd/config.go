@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/swdee/go-vl53l1x/config"
+)
+
+// Duration is a time.Duration given in the file as a string such as "10s"
+type Duration time.Duration
+
+// UnmarshalJSON parses the duration string
+func (d *Duration) UnmarshalJSON(data []byte) error {
+
+	var s string
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid duration %s", data)
+	}
+
+	v, err := time.ParseDuration(s)
+
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(v)
+
+	return nil
+}
+
+// MarshalJSON formats the duration as a string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// SensorConfig is a sensor managed by the daemon.  Its name identifies it in
+// the HTTP endpoints, sink tags and logs, defaulting to sensor0, sensor1 and
+// so on
+type SensorConfig struct {
+	config.Config
+}
+
+// UnmarshalJSON decodes the sensor over the config package defaults,
+// rejecting unknown fields
+func (s *SensorConfig) UnmarshalJSON(data []byte) error {
+
+	type plain SensorConfig
+
+	p := plain{Config: config.Default()}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&p); err != nil {
+		return err
+	}
+
+	*s = SensorConfig(p)
+
+	return nil
+}
+
+// InfluxConfig configures the InfluxDB sink, see sink.InfluxConfig
+type InfluxConfig struct {
+	URL           string   `json:"url"`
+	Token         string   `json:"token,omitempty"`
+	Measurement   string   `json:"measurement,omitempty"`
+	BatchSize     int      `json:"batchSize,omitempty"`
+	FlushInterval Duration `json:"flushInterval,omitempty"`
+}
+
+// SinkConfig configures where readings are published
+type SinkConfig struct {
+	// Stdout writes each reading as a line of JSON to standard output
+	Stdout bool `json:"stdout,omitempty"`
+	// Influx writes readings to InfluxDB tagged with the sensor name
+	Influx *InfluxConfig `json:"influx,omitempty"`
+	// Interval limits the readings published for each sensor to one per
+	// interval, 0 publishes every measurement
+	Interval Duration `json:"interval,omitempty"`
+}
+
+// WatchdogConfig configures recovery of sensors that stop measuring
+type WatchdogConfig struct {
+	// Timeout is how long a read may wait for a measurement, defaults to 5s
+	Timeout Duration `json:"timeout,omitempty"`
+	// Failures is the number of consecutive failed reads, or hardware fail
+	// statuses, after which the sensor is initialized again, defaults to 3
+	Failures int `json:"failures,omitempty"`
+	// NACKThreshold is the number of consecutive transfers not acknowledged
+	// before the sensor is considered unplugged, see vl53l1x.HotplugConfig
+	NACKThreshold int `json:"nackThreshold,omitempty"`
+	// Backoff is the wait between recovery attempts, defaults to 1s
+	Backoff Duration `json:"backoff,omitempty"`
+}
+
+// Config is the daemon configuration file
+type Config struct {
+	// Listen is the address of the HTTP server, empty disables it
+	Listen   string         `json:"listen,omitempty"`
+	Sensors  []SensorConfig `json:"sensors"`
+	Sinks    SinkConfig     `json:"sinks"`
+	Watchdog WatchdogConfig `json:"watchdog"`
+	// ShutdownTimeout limits how long stopping ranging, closing the sensors
+	// and flushing the sinks may take on shutdown, defaults to 10s
+	ShutdownTimeout Duration `json:"shutdownTimeout,omitempty"`
+}
+
+// LoadConfig reads and validates the configuration file at path
+func LoadConfig(path string) (Config, error) {
+
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return Config{}, err
+	}
+
+	var cfg Config
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&cfg); err != nil {
+		return Config{}, fmt.Errorf("%s: failed to decode config: %w", path, err)
+	}
+
+	cfg.setDefaults()
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// setDefaults fills in the settings not given in the file
+func (c *Config) setDefaults() {
+
+	for i := range c.Sensors {
+		if c.Sensors[i].Name == "" {
+			c.Sensors[i].Name = fmt.Sprintf("sensor%d", i)
+		}
+	}
+
+	if c.Watchdog.Timeout <= 0 {
+		c.Watchdog.Timeout = Duration(5 * time.Second)
+	}
+
+	if c.Watchdog.Failures <= 0 {
+		c.Watchdog.Failures = 3
+	}
+
+	if c.Watchdog.Backoff <= 0 {
+		c.Watchdog.Backoff = Duration(time.Second)
+	}
+
+	if c.ShutdownTimeout <= 0 {
+		c.ShutdownTimeout = Duration(10 * time.Second)
+	}
+}
+
+// Validate checks the sensors are valid and uniquely named and addressed
+func (c Config) Validate() error {
+
+	if len(c.Sensors) == 0 {
+		return fmt.Errorf("no sensors configured")
+	}
+
+	names := make(map[string]bool)
+	addrs := make(map[string]bool)
+
+	for _, s := range c.Sensors {
+		if err := s.Validate(); err != nil {
+			return fmt.Errorf("sensor %s: %w", s.Name, err)
+		}
+
+		if s.PeriodMs == 0 {
+			return fmt.Errorf("sensor %s: periodMs must be given for continuous ranging", s.Name)
+		}
+
+		if names[s.Name] {
+			return fmt.Errorf("sensor name %s is used more than once", s.Name)
+		}
+
+		addr := fmt.Sprintf("%s %s", s.Bus, s.Address)
+
+		if addrs[addr] {
+			return fmt.Errorf("sensor %s: address %s on %s is used more than once",
+				s.Name, s.Address, s.Bus)
+		}
+
+		names[s.Name], addrs[addr] = true, true
+	}
+
+	if c.Sinks.Influx != nil && c.Sinks.Influx.URL == "" {
+		return fmt.Errorf("influx sink url must be given")
+	}
+
+	return nil
+}
@@ -0,0 +1,739 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+	"github.com/swdee/go-vl53l1x/sim"
+	"github.com/swdee/go-vl53l1x/sink"
+	"github.com/swdee/go-vl53l1x/wsserver"
+)
+
+// message is the JSON line written to stdout for each reading
+type message struct {
+	Time        time.Time `json:"time"`
+	Sensor      string    `json:"sensor"`
+	RangeMM     uint16    `json:"rangeMM"`
+	Status      string    `json:"status"`
+	SignalMCPS  float32   `json:"signalMCPS"`
+	AmbientMCPS float32   `json:"ambientMCPS"`
+	// Labels are the labels of the sensor
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// SensorStatus is the state of a sensor reported on /status
+type SensorStatus struct {
+	Name      string `json:"name"`
+	Bus       string `json:"bus"`
+	Address   string `json:"address"`
+	Connected bool   `json:"connected"`
+	// LastReading is when the last measurement was published
+	LastReading time.Time `json:"lastReading"`
+	RangeMM     uint16    `json:"rangeMM"`
+	RangeStatus string    `json:"rangeStatus"`
+	// Recoveries is the number of times the watchdog initialized the sensor
+	// again
+	Recoveries int    `json:"recoveries"`
+	LastError  string `json:"lastError,omitempty"`
+}
+
+// daemon runs the configured sensors and serves their readings
+type daemon struct {
+	// path is the configuration file read again on reload
+	path     string
+	log      *log.Logger
+	simulate bool
+
+	// stdout serializes the JSON lines of all sensors
+	stdoutMu sync.Mutex
+	stdout   *json.Encoder
+
+	// reloads passes reload requests from the HTTP server to run(), which
+	// closes stopping when it no longer serves them
+	reloads  chan chan reloadResult
+	stopping chan struct{}
+	wg       sync.WaitGroup
+
+	// mu guards the configuration and workers, which change on reload
+	mu      sync.Mutex
+	cfg     Config
+	workers []*worker
+}
+
+// newDaemon returns a daemon for the configuration read from path, when
+// simulate is set every sensor is simulated rather than opened on its bus
+func newDaemon(cfg Config, path string, logger *log.Logger, simulate bool) *daemon {
+
+	d := &daemon{
+		path:     path,
+		cfg:      cfg,
+		log:      logger,
+		simulate: simulate,
+		stdout:   json.NewEncoder(os.Stdout),
+		reloads:  make(chan chan reloadResult),
+		stopping: make(chan struct{}),
+	}
+
+	for _, sc := range cfg.Sensors {
+		d.workers = append(d.workers, d.newWorker(sc))
+	}
+
+	return d
+}
+
+// newWorker returns a worker for the sensor
+func (d *daemon) newWorker(sc SensorConfig) *worker {
+	return &worker{
+		d:      d,
+		name:   sc.Name,
+		cfg:    sc,
+		log:    log.New(d.log.Writer(), sc.Name+": ", d.log.Flags()),
+		ws:     wsserver.New(),
+		reload: make(chan reloadRequest),
+		opened: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// config returns the current configuration
+func (d *daemon) config() Config {
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.cfg
+}
+
+// sensors returns the current workers
+func (d *daemon) sensors() []*worker {
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]*worker(nil), d.workers...)
+}
+
+// sensor returns the worker of the named sensor or nil
+func (d *daemon) sensor(name string) *worker {
+
+	for _, w := range d.sensors() {
+		if w.name == name {
+			return w
+		}
+	}
+
+	return nil
+}
+
+// run ranges every sensor and serves HTTP until the context is cancelled,
+// then shuts down gracefully.  systemd is notified once every sensor has
+// been opened, pinged while the sensors are being serviced and notified
+// again when stopping
+func (d *daemon) run(ctx context.Context) error {
+
+	var srv *http.Server
+	cfg := d.config()
+
+	if cfg.Listen != "" {
+		l, err := net.Listen("tcp", cfg.Listen)
+
+		if err != nil {
+			return err
+		}
+
+		srv = &http.Server{Handler: d.handler()}
+		d.log.Printf("Serving HTTP on %s", l.Addr())
+
+		go func() {
+			if err := srv.Serve(l); !errors.Is(err, http.ErrServerClosed) {
+				d.log.Printf("HTTP server failed: %v", err)
+			}
+		}()
+	}
+
+	// reloads requested before every sensor is opened wait for serve()
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	workers := d.sensors()
+
+	for _, w := range workers {
+		d.start(ctx, w)
+	}
+
+	for _, w := range workers {
+		select {
+		case <-w.opened:
+		case <-ctx.Done():
+		}
+	}
+
+	if ctx.Err() == nil {
+		d.sdNotify(d.readyState())
+		d.serve(ctx, hup)
+	}
+
+	d.log.Printf("Shutting down")
+	d.sdNotify("STOPPING=1")
+	close(d.stopping)
+
+	done := make(chan struct{})
+
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	timeout := time.Duration(d.config().ShutdownTimeout)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if srv != nil {
+		srv.Shutdown(shutdownCtx)
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-shutdownCtx.Done():
+		return fmt.Errorf("sensors not closed within %s", timeout)
+	}
+}
+
+// start runs the worker until the context is cancelled or the worker is
+// stopped
+func (d *daemon) start(ctx context.Context, w *worker) {
+
+	ctx, w.cancel = context.WithCancel(ctx)
+	d.wg.Add(1)
+
+	go func() {
+		defer d.wg.Done()
+		defer close(w.done)
+		w.run(ctx)
+	}()
+}
+
+// serve sends WATCHDOG=1 to systemd while every worker keeps servicing its
+// sensor and reloads the configuration on SIGHUP or a POST to /reload, until
+// the context is cancelled.  A worker blocked on its bus stops the pings so
+// systemd restarts the daemon
+func (d *daemon) serve(ctx context.Context, hup <-chan os.Signal) {
+
+	var tick <-chan time.Time
+
+	if interval := watchdogInterval(); interval > 0 {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		tick = t.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-tick:
+			if d.alive() {
+				d.sdNotify("WATCHDOG=1")
+			}
+
+		case <-hup:
+			d.log.Printf("Reloading %s on SIGHUP", d.path)
+			d.reload(ctx)
+
+		case reply := <-d.reloads:
+			d.log.Printf("Reloading %s on request", d.path)
+			reply <- d.reload(ctx)
+		}
+	}
+}
+
+// alive reports whether every worker has serviced its sensor recently
+func (d *daemon) alive() bool {
+
+	stalled := d.stallTimeout()
+	alive := true
+
+	for _, w := range d.sensors() {
+		if since := time.Since(time.Unix(0, w.beat.Load())); since > stalled {
+			d.log.Printf("Sensor %s stalled for %s, withholding watchdog", w.name,
+				since.Round(time.Second))
+			alive = false
+		}
+	}
+
+	return alive
+}
+
+// stallTimeout is how long a worker may go without servicing its sensor, it
+// beats at least once per read timeout or recovery backoff
+func (d *daemon) stallTimeout() time.Duration {
+
+	wd := d.config().Watchdog
+
+	return 2 * time.Duration(wd.Timeout+wd.Backoff)
+}
+
+// readyState is the state sent to systemd when ready
+func (d *daemon) readyState() string {
+	return fmt.Sprintf("READY=1\nSTATUS=Ranging %d sensors", len(d.sensors()))
+}
+
+// sdNotify sends the state to systemd, logging any failure
+func (d *daemon) sdNotify(state string) {
+
+	if err := notify(state); err != nil {
+		d.log.Printf("Failed to notify systemd: %v", err)
+	}
+}
+
+// handler returns the HTTP endpoints: /status with the state of every
+// sensor, /debug/vars with their driver internals and the WebSocket viewer
+// of each sensor under /sensors/<name>/
+func (d *daemon) handler() http.Handler {
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", d.handleStatus)
+	mux.HandleFunc("/reload", d.handleReload)
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.HandleFunc("/sensors/", d.handleSensor)
+
+	return mux
+}
+
+// handleSensor serves the WebSocket viewer of the sensor named in the path,
+// looked up on each request as sensors are added and removed on reload
+func (d *daemon) handleSensor(rw http.ResponseWriter, r *http.Request) {
+
+	name, _, found := strings.Cut(strings.TrimPrefix(r.URL.Path, "/sensors/"), "/")
+	w := d.sensor(name)
+
+	if w == nil {
+		http.NotFound(rw, r)
+		return
+	}
+
+	if !found {
+		http.Redirect(rw, r, r.URL.Path+"/", http.StatusMovedPermanently)
+		return
+	}
+
+	http.StripPrefix("/sensors/"+name, w.ws).ServeHTTP(rw, r)
+}
+
+// handleStatus serves the state of every sensor as JSON
+func (d *daemon) handleStatus(rw http.ResponseWriter, r *http.Request) {
+
+	workers := d.sensors()
+	status := make([]SensorStatus, 0, len(workers))
+
+	for _, w := range workers {
+		status = append(status, w.Status())
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(rw).Encode(status)
+}
+
+// debugState returns the driver internals of every open sensor for
+// /debug/vars
+func (d *daemon) debugState() any {
+
+	state := make(map[string]vl53l1x.DebugState)
+
+	for _, w := range d.sensors() {
+		if sensor := w.current(); sensor != nil {
+			state[w.name] = sensor.DebugState()
+		}
+	}
+
+	return state
+}
+
+// newSink returns the sinks configured for the sensor and a function
+// flushing and closing them
+func (d *daemon) newSink(name string, sensor *vl53l1x.VL53L1X) (sink.Sink, func() error, error) {
+
+	cfg := d.config().Sinks
+
+	var sinks sink.Multi
+	closers := []func() error{}
+
+	if cfg.Stdout {
+		sinks = append(sinks, sink.Func(func(data vl53l1x.RangingData) error {
+
+			d.stdoutMu.Lock()
+			defer d.stdoutMu.Unlock()
+
+			return d.stdout.Encode(message{
+				Time:        time.Now(),
+				Sensor:      name,
+				RangeMM:     data.RangeMM,
+				Status:      data.RangeStatus.String(),
+				SignalMCPS:  data.PeakSignalCountRateMCPS,
+				AmbientMCPS: data.AmbientCountRateMCPS,
+				Labels:      sensor.Labels(),
+			})
+		}))
+	}
+
+	if ic := cfg.Influx; ic != nil {
+		tags := sink.SensorTags(sensor)
+		tags["sensor"] = name
+
+		influx, err := sink.NewInflux(sink.InfluxConfig{
+			URL:           ic.URL,
+			Token:         ic.Token,
+			Measurement:   ic.Measurement,
+			Tags:          tags,
+			BatchSize:     ic.BatchSize,
+			FlushInterval: time.Duration(ic.FlushInterval),
+		})
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		sinks = append(sinks, influx)
+		closers = append(closers, influx.Close)
+	}
+
+	closeAll := func() error {
+
+		var errs []error
+
+		for _, c := range closers {
+			errs = append(errs, c())
+		}
+
+		return errors.Join(errs...)
+	}
+
+	if cfg.Interval > 0 {
+		return sink.NewRateLimited(sinks, time.Duration(cfg.Interval)), closeAll, nil
+	}
+
+	return sinks, closeAll, nil
+}
+
+// worker ranges a single sensor, publishing its readings and recovering it
+// when it stops measuring
+type worker struct {
+	d *daemon
+	// name is the sensor name, which does not change on reload
+	name string
+	log  *log.Logger
+	ws   *wsserver.Server
+
+	// reload passes configuration changes to the worker goroutine
+	reload chan reloadRequest
+	cancel context.CancelFunc
+	// done is closed when the worker has stopped and closed the sensor
+	done chan struct{}
+
+	// sensor, monitor and out are only set while the sensor is open
+	sensor    *vl53l1x.VL53L1X
+	monitor   *vl53l1x.HotplugMonitor
+	out       sink.Sink
+	closeSink func() error
+
+	// opened is closed after the first attempt to open the sensor
+	opened chan struct{}
+	// beat is the time in unix nanoseconds the worker last serviced the
+	// sensor
+	beat atomic.Int64
+
+	// mu guards the configuration, which only the worker goroutine changes,
+	// and the status
+	mu     sync.Mutex
+	cfg    SensorConfig
+	status SensorStatus
+}
+
+// Status returns the state of the sensor
+func (w *worker) Status() SensorStatus {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	s := w.status
+	s.Name, s.Bus, s.Address = w.cfg.Name, w.cfg.Bus, w.cfg.Address.String()
+
+	return s
+}
+
+// current returns the sensor when it is open
+func (w *worker) current() *vl53l1x.VL53L1X {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.sensor
+}
+
+// run opens and ranges the sensor until the context is cancelled, then
+// stops ranging and closes it
+func (w *worker) run(ctx context.Context) {
+
+	defer w.close()
+
+	failures := 0
+	var opened sync.Once
+
+	for ctx.Err() == nil {
+		w.beat.Store(time.Now().UnixNano())
+
+		select {
+		case req := <-w.reload:
+			req.result <- w.reconfigure(req)
+			failures = 0
+			continue
+		default:
+		}
+
+		wd := w.d.config().Watchdog
+
+		if w.sensor == nil {
+			err := w.open()
+			opened.Do(func() { close(w.opened) })
+
+			if err != nil {
+				w.failed(fmt.Errorf("failed to open sensor: %w", err))
+				sleep(ctx, time.Duration(wd.Backoff))
+			}
+
+			continue
+		}
+
+		if !w.monitor.Connected() {
+			w.monitor.Check(ctx)
+
+			if !w.monitor.Connected() {
+				sleep(ctx, time.Duration(wd.Backoff))
+				continue
+			}
+
+			w.restore(wd)
+		}
+
+		data, err := w.sensor.ReadContext(ctx)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		w.monitor.Check(ctx)
+
+		if !w.monitor.Connected() {
+			w.failed(fmt.Errorf("sensor disconnected"))
+			failures = 0
+			continue
+		}
+
+		if err == nil && data.RangeStatus == vl53l1x.HardwareFail {
+			err = fmt.Errorf("hardware fail status")
+		}
+
+		// with a threshold set no measurement is reported while the target
+		// stays outside it
+		if errors.Is(err, vl53l1x.ErrTimeout) && w.cfg.Threshold != nil {
+			continue
+		}
+
+		if err != nil {
+			w.failed(err)
+
+			if failures++; failures >= wd.Failures {
+				failures = 0
+				w.recover(ctx)
+			}
+
+			continue
+		}
+
+		failures = 0
+		w.publish(data)
+	}
+}
+
+// open opens and configures the sensor, starting continuous ranging
+func (w *worker) open() error {
+
+	// the driver prefixes its log lines with the sensor name and settings
+	// changed on reload discard the measurement in progress
+	opts := []vl53l1x.Option{
+		vl53l1x.WithLogger(w.d.log),
+		vl53l1x.WithHoldOff(vl53l1x.HoldOffAuto),
+	}
+
+	var sensor *vl53l1x.VL53L1X
+	var err error
+
+	if w.d.simulate {
+		bus := sim.New(uint8(w.cfg.Address))
+		opts = append(w.cfg.Options(), opts...)
+
+		if sensor, err = vl53l1x.New(bus, w.cfg.Mode, w.cfg.TimingBudget, opts...); err == nil {
+			if err = w.cfg.Apply(sensor); err != nil {
+				sensor.Close()
+			}
+		}
+	} else {
+		sensor, err = w.cfg.Open(opts...)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	wd := w.d.config().Watchdog
+	sensor.SetTimeout(time.Duration(wd.Timeout))
+
+	monitor, err := vl53l1x.NewHotplugMonitor(sensor, vl53l1x.HotplugConfig{
+		NACKThreshold: wd.NACKThreshold,
+	})
+
+	if err != nil {
+		sensor.Close()
+		return err
+	}
+
+	out, closeSink, err := w.d.newSink(w.cfg.Name, sensor)
+
+	if err != nil {
+		sensor.Close()
+		return err
+	}
+
+	w.mu.Lock()
+	w.sensor, w.monitor, w.out, w.closeSink = sensor, monitor, out, closeSink
+	w.status.Connected = true
+	w.mu.Unlock()
+
+	w.log.Printf("Ranging every %dms with %dms timing budget in %s mode",
+		w.cfg.PeriodMs, w.cfg.TimingBudget, w.cfg.Mode)
+
+	return nil
+}
+
+// recover initializes the sensor again and restarts ranging after repeated
+// failures, retrying until it succeeds or the context is cancelled
+func (w *worker) recover(ctx context.Context) {
+
+	w.log.Printf("Watchdog initializing sensor again")
+
+	for ctx.Err() == nil {
+		w.beat.Store(time.Now().UnixNano())
+
+		wd := w.d.config().Watchdog
+		err := w.sensor.Init()
+
+		if err == nil {
+			err = w.cfg.Apply(w.sensor)
+		}
+
+		if err == nil {
+			w.sensor.SetTimeout(time.Duration(wd.Timeout))
+
+			w.mu.Lock()
+			w.status.Recoveries++
+			w.mu.Unlock()
+
+			return
+		}
+
+		w.failed(fmt.Errorf("watchdog recovery failed: %w", err))
+		sleep(ctx, time.Duration(wd.Backoff))
+	}
+}
+
+// publish writes the reading to the sinks and WebSocket clients
+func (w *worker) publish(data vl53l1x.RangingData) {
+
+	w.ws.Publish(data)
+
+	if err := w.out.Write(data); err != nil {
+		w.log.Printf("Sink write failed: %v", err)
+	}
+
+	w.mu.Lock()
+	w.status.Connected = true
+	w.status.LastReading = time.Now()
+	w.status.RangeMM = data.RangeMM
+	w.status.RangeStatus = data.RangeStatus.String()
+	w.status.LastError = ""
+	w.mu.Unlock()
+}
+
+// failed logs the error and records it in the status
+func (w *worker) failed(err error) {
+
+	w.log.Print(err)
+
+	w.mu.Lock()
+	w.status.Connected = w.monitor != nil && w.monitor.Connected()
+	w.status.LastError = err.Error()
+	w.mu.Unlock()
+}
+
+// close closes the sensor, which stops ranging and powers it down if it has
+// a shutdown pin, and flushes the sinks
+func (w *worker) close() {
+
+	if w.sensor == nil {
+		return
+	}
+
+	if err := w.sensor.Close(); err != nil {
+		w.log.Printf("Failed to close sensor: %v", err)
+	}
+
+	if err := w.closeSink(); err != nil {
+		w.log.Printf("Failed to flush sinks: %v", err)
+	}
+
+	w.mu.Lock()
+	w.sensor, w.monitor = nil, nil
+	w.status.Connected = false
+	w.mu.Unlock()
+}
+
+// restore reapplies the settings the hotplug monitor does not restore on
+// reconnect
+func (w *worker) restore(wd WatchdogConfig) {
+
+	// initialization restored the default timeout
+	w.sensor.SetTimeout(time.Duration(wd.Timeout))
+
+	if t := w.cfg.Threshold; t != nil {
+		if err := w.sensor.SetDistanceThreshold(t.LowMM, t.HighMM, t.Window, t.OnNoTarget); err != nil {
+			w.failed(fmt.Errorf("failed to restore threshold: %w", err))
+		}
+	}
+}
+
+// sleep waits for d or until the context is cancelled
+func sleep(ctx context.Context, d time.Duration) {
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
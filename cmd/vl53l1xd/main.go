@@ -0,0 +1,73 @@
+// Command vl53l1xd is a long running service that ranges the sensors
+// described in a configuration file, publishes their readings to the
+// configured sinks, serves them over HTTP and initializes sensors again when
+// they stop measuring or are unplugged.
+//
+// An example configuration:
+//
+//	{
+//	  "listen": ":8080",
+//	  "sensors": [
+//	    {"name": "door", "bus": "/dev/i2c-1", "address": "0x29",
+//	     "mode": "long", "timingBudget": 50, "periodMs": 55}
+//	  ],
+//	  "sinks": {
+//	    "stdout": true,
+//	    "influx": {"url": "http://localhost:8086/api/v2/write?org=home&bucket=sensors"},
+//	    "interval": "1s"
+//	  },
+//	  "watchdog": {"timeout": "5s", "failures": 3}
+//	}
+//
+// The HTTP server provides /status with the state of every sensor,
+// /debug/vars with their driver internals and a live view of each sensor at
+// /sensors/<name>/.
+//
+// Run as a systemd service of Type=notify it reports readiness once every
+// sensor has been opened and, with WatchdogSec set, pings the watchdog while
+// every sensor is being serviced.  On SIGTERM or SIGINT ranging is stopped,
+// the sensors closed and the sinks flushed within shutdownTimeout.
+//
+// The configuration file is applied again on SIGHUP or a POST to /reload
+// without restarting, logging the changed settings.
+package main
+
+import (
+	"context"
+	"expvar"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+
+	path := flag.String("c", "/etc/vl53l1xd.json", "Path to configuration file")
+	simulate := flag.Bool("sim", false, "Use simulated sensors instead of the I2C buses")
+	flag.Parse()
+
+	logger := log.New(os.Stderr, "", log.LstdFlags)
+
+	cfg, err := LoadConfig(*path)
+
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	d := newDaemon(cfg, *path, logger, *simulate)
+	expvar.Publish("vl53l1xd", expvar.Func(d.debugState))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	// a second signal during shutdown exits immediately
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	if err := d.run(ctx); err != nil {
+		logger.Fatal(err)
+	}
+}
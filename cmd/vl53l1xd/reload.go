@@ -0,0 +1,364 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+// Change is a setting that differs between the running and reloaded
+// configuration
+type Change struct {
+	// Sensor is the name of the sensor the setting belongs to, empty for
+	// daemon settings
+	Sensor  string `json:"sensor,omitempty"`
+	Setting string `json:"setting"`
+	From    string `json:"from,omitempty"`
+	To      string `json:"to,omitempty"`
+}
+
+// String describes the change, eg: "door mode short -> long"
+func (c Change) String() string {
+
+	name := c.Setting
+
+	if c.Sensor != "" {
+		name = c.Sensor + " " + name
+	}
+
+	return fmt.Sprintf("%s %s -> %s", name, c.From, c.To)
+}
+
+// reloadResult is the outcome of a reload served on /reload
+type reloadResult struct {
+	// Changes are the settings that differ from the running configuration
+	Changes []Change `json:"changes"`
+	// Errors are the changes that could not be applied, or why the file was
+	// not loaded
+	Errors []string `json:"errors,omitempty"`
+}
+
+// reloadRequest passes a sensors reloaded configuration to its worker
+type reloadRequest struct {
+	cfg SensorConfig
+	// reset is set when the sinks or watchdog settings changed
+	reset  bool
+	result chan error
+}
+
+// diffConfig returns the settings that differ from old to cfg
+func diffConfig(old, cfg Config) []Change {
+
+	var changes []Change
+
+	add := func(sensor, setting, from, to string) {
+		if from != to {
+			changes = append(changes, Change{Sensor: sensor, Setting: setting, From: from, To: to})
+		}
+	}
+
+	add("", "listen", old.Listen, cfg.Listen)
+	add("", "sinks.stdout", strconv.FormatBool(old.Sinks.Stdout), strconv.FormatBool(cfg.Sinks.Stdout))
+	add("", "sinks.influx", formatInflux(old.Sinks.Influx), formatInflux(cfg.Sinks.Influx))
+	add("", "sinks.interval", formatDuration(old.Sinks.Interval), formatDuration(cfg.Sinks.Interval))
+	add("", "watchdog.timeout", formatDuration(old.Watchdog.Timeout), formatDuration(cfg.Watchdog.Timeout))
+	add("", "watchdog.failures", strconv.Itoa(old.Watchdog.Failures), strconv.Itoa(cfg.Watchdog.Failures))
+	add("", "watchdog.nackThreshold", strconv.Itoa(old.Watchdog.NACKThreshold),
+		strconv.Itoa(cfg.Watchdog.NACKThreshold))
+	add("", "watchdog.backoff", formatDuration(old.Watchdog.Backoff), formatDuration(cfg.Watchdog.Backoff))
+	add("", "shutdownTimeout", formatDuration(old.ShutdownTimeout), formatDuration(cfg.ShutdownTimeout))
+
+	prev := make(map[string]SensorConfig)
+
+	for _, s := range old.Sensors {
+		prev[s.Name] = s
+	}
+
+	for _, s := range cfg.Sensors {
+		o, ok := prev[s.Name]
+		delete(prev, s.Name)
+
+		if !ok {
+			add(s.Name, "sensor", "none", "added")
+			continue
+		}
+
+		add(s.Name, "bus", o.Bus, s.Bus)
+		add(s.Name, "address", o.Address.String(), s.Address.String())
+		add(s.Name, "mode", o.Mode.String(), s.Mode.String())
+		add(s.Name, "timingBudget", strconv.Itoa(int(o.TimingBudget)), strconv.Itoa(int(s.TimingBudget)))
+		add(s.Name, "periodMs", strconv.Itoa(int(o.PeriodMs)), strconv.Itoa(int(s.PeriodMs)))
+		add(s.Name, "roi", formatJSON(o.ROI), formatJSON(s.ROI))
+		add(s.Name, "threshold", formatJSON(o.Threshold), formatJSON(s.Threshold))
+		add(s.Name, "busSpeed", strconv.Itoa(o.BusSpeed), strconv.Itoa(s.BusSpeed))
+		add(s.Name, "calibration", o.Calibration, s.Calibration)
+		add(s.Name, "labels", formatJSON(o.Labels), formatJSON(s.Labels))
+	}
+
+	for _, s := range old.Sensors {
+		if _, ok := prev[s.Name]; ok {
+			add(s.Name, "sensor", "present", "removed")
+		}
+	}
+
+	return changes
+}
+
+// formatDuration formats a configured duration
+func formatDuration(d Duration) string {
+	return time.Duration(d).String()
+}
+
+// formatJSON formats an optional setting as compact JSON, none when unset
+func formatJSON(v any) string {
+
+	if rv := reflect.ValueOf(v); rv.IsNil() || (rv.Kind() == reflect.Map && rv.Len() == 0) {
+		return "none"
+	}
+
+	data, err := json.Marshal(v)
+
+	if err != nil {
+		return err.Error()
+	}
+
+	return string(data)
+}
+
+// formatInflux formats the InfluxDB sink without its token
+func formatInflux(ic *InfluxConfig) string {
+
+	if ic == nil {
+		return "none"
+	}
+
+	c := *ic
+
+	if c.Token != "" {
+		c.Token = "redacted"
+	}
+
+	return formatJSON(&c)
+}
+
+// reload reads the configuration file again and applies the changes without
+// interrupting the sensors they do not affect.  Added sensors are started,
+// removed sensors closed and changed sensors reconfigured while ranging, or
+// opened again when their bus, address, bus speed or calibration changed.
+// The listen address needs a restart
+func (d *daemon) reload(ctx context.Context) reloadResult {
+
+	d.sdNotify("RELOADING=1")
+	defer func() { d.sdNotify(d.readyState()) }()
+
+	var res reloadResult
+
+	fail := func(err error) {
+		d.log.Printf("Reload: %v", err)
+		res.Errors = append(res.Errors, err.Error())
+	}
+
+	cfg, err := LoadConfig(d.path)
+
+	if err != nil {
+		fail(fmt.Errorf("keeping the running configuration: %w", err))
+		return res
+	}
+
+	old := d.config()
+	res.Changes = diffConfig(old, cfg)
+
+	for _, c := range res.Changes {
+		d.log.Printf("Reload: %s", c)
+	}
+
+	if len(res.Changes) == 0 {
+		d.log.Printf("Reload: no changes")
+		return res
+	}
+
+	if cfg.Listen != old.Listen {
+		fail(fmt.Errorf("listen address change needs a restart, serving on %s", old.Listen))
+		cfg.Listen = old.Listen
+	}
+
+	d.mu.Lock()
+	d.cfg = cfg
+	d.mu.Unlock()
+
+	reset := !reflect.DeepEqual(old.Sinks, cfg.Sinks) || old.Watchdog != cfg.Watchdog
+	running := d.sensors()
+	workers := make([]*worker, 0, len(cfg.Sensors))
+
+	for _, sc := range cfg.Sensors {
+		w := d.sensor(sc.Name)
+
+		switch {
+		case w == nil:
+			w = d.newWorker(sc)
+			d.start(ctx, w)
+
+		case reset || !reflect.DeepEqual(w.config(), sc):
+			err := w.apply(ctx, reloadRequest{cfg: sc, reset: reset}, d.stallTimeout())
+
+			if err != nil {
+				fail(fmt.Errorf("sensor %s: %w", sc.Name, err))
+			}
+		}
+
+		workers = append(workers, w)
+	}
+
+	d.mu.Lock()
+	d.workers = workers
+	d.mu.Unlock()
+
+	for _, w := range running {
+		if !slices.Contains(workers, w) {
+			w.stop()
+		}
+	}
+
+	return res
+}
+
+// handleReload reloads the configuration file on a POST and serves the
+// changes as JSON
+func (d *daemon) handleReload(rw http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodPost {
+		rw.Header().Set("Allow", http.MethodPost)
+		http.Error(rw, "reload with POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	reply := make(chan reloadResult, 1)
+
+	select {
+	case d.reloads <- reply:
+	case <-d.stopping:
+		http.Error(rw, "shutting down", http.StatusServiceUnavailable)
+		return
+	case <-r.Context().Done():
+		return
+	}
+
+	res := <-reply
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	if len(res.Errors) > 0 {
+		rw.WriteHeader(http.StatusUnprocessableEntity)
+	}
+
+	json.NewEncoder(rw).Encode(res)
+}
+
+// config returns the sensors configuration
+func (w *worker) config() SensorConfig {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.cfg
+}
+
+// apply passes the reload request to the worker goroutine and waits for the
+// result, giving up when the worker does not service its sensor within the
+// timeout
+func (w *worker) apply(ctx context.Context, req reloadRequest, timeout time.Duration) error {
+
+	req.result = make(chan error, 1)
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case w.reload <- req:
+	case <-w.done:
+		return fmt.Errorf("sensor stopped")
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return fmt.Errorf("sensor not serviced within %s, reload again to retry", timeout)
+	}
+
+	return <-req.result
+}
+
+// reconfigure applies a reloaded configuration from the worker goroutine.
+// Ranging settings are written while ranging, while settings the sensor was
+// opened with, or a sensor that is unplugged, have the sensor opened again
+func (w *worker) reconfigure(req reloadRequest) error {
+
+	old := w.cfg
+
+	w.mu.Lock()
+	w.cfg = req.cfg
+	w.mu.Unlock()
+
+	switch {
+	case w.sensor == nil:
+		// the next attempt opens the sensor with the new configuration
+		return nil
+
+	case req.cfg.NeedsReopen(old.Config) || !w.monitor.Connected():
+		w.log.Printf("Opening sensor again with the new configuration")
+		w.close()
+		return w.open()
+	}
+
+	if err := req.cfg.Reconfigure(w.sensor, old.Config); err != nil {
+		return err
+	}
+
+	// the InfluxDB sink is tagged with the labels
+	if req.reset || !maps.Equal(req.cfg.Labels, old.Labels) {
+		out, closeSink, err := w.d.newSink(w.name, w.sensor)
+
+		if err != nil {
+			return fmt.Errorf("failed to create sinks: %w", err)
+		}
+
+		if err := w.closeSink(); err != nil {
+			w.log.Printf("Failed to flush sinks: %v", err)
+		}
+
+		w.mu.Lock()
+		w.out, w.closeSink = out, closeSink
+		w.mu.Unlock()
+	}
+
+	wd := w.d.config().Watchdog
+	w.sensor.SetTimeout(time.Duration(wd.Timeout))
+
+	// a new monitor snapshots the settings to restore on reconnect
+	monitor, err := vl53l1x.NewHotplugMonitor(w.sensor, vl53l1x.HotplugConfig{
+		NACKThreshold: wd.NACKThreshold,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	w.monitor = monitor
+	w.mu.Unlock()
+
+	return nil
+}
+
+// stop stops the worker and waits for it to close the sensor
+func (w *worker) stop() {
+
+	w.cancel()
+	<-w.done
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// notify sends the state, such as READY=1, to the service manager over the
+// socket systemd passes in NOTIFY_SOCKET, as sd_notify(3) does.  Nothing is
+// sent when the daemon was not started by systemd
+func notify(state string) error {
+
+	path := os.Getenv("NOTIFY_SOCKET")
+
+	if path == "" {
+		return nil
+	}
+
+	// abstract namespace sockets are given with a leading @
+	if path[0] == '@' {
+		path = "\x00" + path[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: path, Net: "unixgram"})
+
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(state))
+
+	return err
+}
+
+// watchdogInterval returns how often to send WATCHDOG=1, half the timeout
+// systemd passes in WATCHDOG_USEC as sd_watchdog_enabled(3) recommends, or 0
+// when the service has no watchdog
+func watchdogInterval() time.Duration {
+
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+
+	if err != nil || usec <= 0 {
+		return 0
+	}
+
+	return time.Duration(usec) * time.Microsecond / 2
+}
@@ -0,0 +1,98 @@
+package vl53l1x
+
+// Config holds the sensor settings that can be persisted and reapplied
+type Config struct {
+	DistanceMode DistanceMode `json:"distanceMode"`
+	// TimingBudget in milliseconds
+	TimingBudget uint32 `json:"timingBudget"`
+	// ROIWidth and ROIHeight of the region of interest, leave as zero to not
+	// change the ROI when applied
+	ROIWidth  uint8 `json:"roiWidth,omitempty"`
+	ROIHeight uint8 `json:"roiHeight,omitempty"`
+	// ROICenter SPAD number, leave as zero to not change the center when
+	// applied
+	ROICenter uint8 `json:"roiCenter,omitempty"`
+}
+
+// GetConfig returns the sensors current settings as read back from the sensor
+func (v *VL53L1X) GetConfig() (Config, error) {
+
+	mode, err := v.GetDistanceModeFromDevice()
+
+	if err != nil {
+		return Config{}, err
+	}
+
+	budget, err := v.GetMeasurementTimingBudget()
+
+	if err != nil {
+		return Config{}, err
+	}
+
+	width, height, err := v.GetROISize()
+
+	if err != nil {
+		return Config{}, err
+	}
+
+	center, err := v.GetROICenter()
+
+	if err != nil {
+		return Config{}, err
+	}
+
+	return Config{
+		DistanceMode: mode,
+		TimingBudget: budget,
+		ROIWidth:     width,
+		ROIHeight:    height,
+		ROICenter:    center,
+	}, nil
+}
+
+// ApplyConfig writes the settings to the sensor.  A zero TimingBudget keeps
+// the current budget.  The distance mode is only written when it differs from
+// that read back from the sensor
+func (v *VL53L1X) ApplyConfig(cfg Config) error {
+
+	budget := cfg.TimingBudget
+
+	if budget == 0 {
+		budget = v.timingBudget
+	}
+
+	// validate mode and budget together so moving between modes is not
+	// rejected by the budget of the previous mode
+	budget, err := v.checkModeBudget(cfg.DistanceMode, budget)
+
+	if err != nil {
+		return err
+	}
+
+	// a mode that can not be read back from the sensor is written again
+	mode, err := v.GetDistanceModeFromDevice()
+
+	if err == nil && mode == cfg.DistanceMode {
+		err = v.setMeasurementTimingBudget(budget)
+	} else {
+		err = v.setDistanceMode(cfg.DistanceMode, budget)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if cfg.ROIWidth > 0 && cfg.ROIHeight > 0 {
+		if err := v.SetROISize(cfg.ROIWidth, cfg.ROIHeight); err != nil {
+			return err
+		}
+	}
+
+	if cfg.ROICenter > 0 {
+		if err := v.SetROICenter(cfg.ROICenter); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
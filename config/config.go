@@ -0,0 +1,375 @@
+// Package config loads a declarative description of a sensor deployment from
+// a JSON file, covering the bus and address to open, the ranging settings and
+// where calibration is stored, so the same file can be shared by the command
+// line tools, examples and applications.
+//
+// An example file:
+//
+//	{
+//	  "name": "front-left",
+//	  "labels": {"room": "garage"},
+//	  "bus": "/dev/i2c-1",
+//	  "address": "0x29",
+//	  "mode": "long",
+//	  "timingBudget": 50,
+//	  "periodMs": 55,
+//	  "roi": {"width": 8, "height": 8, "center": 199},
+//	  "threshold": {"window": "below", "lowMM": 300},
+//	  "calibration": "/var/lib/vl53l1x/sensor.json"
+//	}
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"strconv"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+// DefaultBus is the I2C bus used when the file does not give one
+const DefaultBus = "/dev/i2c-0"
+
+// Address is a 7-bit I2C address given in the file as a number or a string
+// such as "0x29"
+type Address uint8
+
+// String returns the address in hex
+func (a Address) String() string {
+	return fmt.Sprintf("0x%02X", uint8(a))
+}
+
+// UnmarshalJSON accepts the address as a number or a string
+func (a *Address) UnmarshalJSON(data []byte) error {
+
+	var s string
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		s = string(data)
+	}
+
+	n, err := strconv.ParseUint(s, 0, 8)
+
+	if err != nil {
+		return fmt.Errorf("invalid address %s", data)
+	}
+
+	*a = Address(n)
+
+	return nil
+}
+
+// ROI is the region of interest, a zero center keeps the current center
+type ROI struct {
+	Width  uint8 `json:"width"`
+	Height uint8 `json:"height"`
+	Center uint8 `json:"center,omitempty"`
+}
+
+// Threshold is a distance threshold, only measurements meeting the window
+// are reported, see vl53l1x.SetDistanceThreshold()
+type Threshold struct {
+	// Window is below, above, outside or inside
+	Window vl53l1x.ThresholdWindow `json:"window"`
+	LowMM  uint16                  `json:"lowMM,omitempty"`
+	HighMM uint16                  `json:"highMM,omitempty"`
+	// OnNoTarget also reports measurements without a target
+	OnNoTarget bool `json:"onNoTarget,omitempty"`
+}
+
+// Config describes a sensor deployment
+type Config struct {
+	// Name and Labels identify the sensor in events, metrics and logs, see
+	// vl53l1x.SetName() and vl53l1x.SetLabels()
+	Name   string            `json:"name,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+	// Bus is the I2C bus device path
+	Bus string `json:"bus"`
+	// Address of the sensor
+	Address Address `json:"address"`
+	// Mode is the distance mode by name or number
+	Mode vl53l1x.DistanceMode `json:"mode"`
+	// TimingBudget in milliseconds
+	TimingBudget uint32 `json:"timingBudget"`
+	// PeriodMs is the inter-measurement period continuous ranging is started
+	// with by Apply(), when 0 ranging is not started
+	PeriodMs uint32 `json:"periodMs,omitempty"`
+	// ROI when set replaces the default full field of view
+	ROI *ROI `json:"roi,omitempty"`
+	// Threshold when set reports only measurements meeting it
+	Threshold *Threshold `json:"threshold,omitempty"`
+	// BusSpeed is the I2C clock rate in Hz, see vl53l1x.SetBusSpeed()
+	BusSpeed int `json:"busSpeed,omitempty"`
+	// Calibration is the path of a vl53l1x.FileStore holding the sensors
+	// calibration, which is loaded during initialization
+	Calibration string `json:"calibration,omitempty"`
+}
+
+// Default returns the configuration used for settings not given in a file
+func Default() Config {
+	return Config{
+		Bus:          DefaultBus,
+		Address:      Address(vl53l1x.Address),
+		Mode:         vl53l1x.DefaultDistanceMode,
+		TimingBudget: vl53l1x.DefaultTimingBudget,
+	}
+}
+
+// Load reads a configuration from r over the defaults and validates it
+func Load(r io.Reader) (Config, error) {
+
+	data, err := io.ReadAll(r)
+
+	if err != nil {
+		return Config{}, err
+	}
+
+	cfg := Default()
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&cfg); err != nil {
+		return Config{}, fmt.Errorf("failed to decode config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// LoadFile reads the configuration file at path
+func LoadFile(path string) (Config, error) {
+
+	f, err := os.Open(path)
+
+	if err != nil {
+		return Config{}, err
+	}
+
+	defer f.Close()
+
+	cfg, err := Load(f)
+
+	if err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// Validate checks the settings are in range
+func (c Config) Validate() error {
+
+	if c.Bus == "" {
+		return fmt.Errorf("bus must be given")
+	}
+
+	if err := vl53l1x.ValidateAddress(uint8(c.Address)); err != nil {
+		return err
+	}
+
+	if c.TimingBudget == 0 {
+		return fmt.Errorf("timing budget must be given")
+	}
+
+	if c.PeriodMs > 0 && c.PeriodMs < c.TimingBudget {
+		return fmt.Errorf("period %dms is shorter than timing budget %dms",
+			c.PeriodMs, c.TimingBudget)
+	}
+
+	if c.ROI != nil && (c.ROI.Width < 4 || c.ROI.Height < 4 ||
+		c.ROI.Width > 16 || c.ROI.Height > 16) {
+		return fmt.Errorf("ROI %dx%d must be between 4x4 and 16x16", c.ROI.Width, c.ROI.Height)
+	}
+
+	if t := c.Threshold; t != nil {
+		if t.Window > vl53l1x.ThresholdInside {
+			return fmt.Errorf("invalid threshold window %d", t.Window)
+		}
+
+		if t.LowMM > t.HighMM {
+			return fmt.Errorf("threshold low %dmm is above high %dmm", t.LowMM, t.HighMM)
+		}
+	}
+
+	return nil
+}
+
+// Options returns the driver options for the settings applied when the
+// sensor is created
+func (c Config) Options() []vl53l1x.Option {
+
+	opts := []vl53l1x.Option{
+		vl53l1x.WithDistanceMode(c.Mode),
+		vl53l1x.WithTimingBudget(c.TimingBudget),
+	}
+
+	if c.BusSpeed > 0 {
+		opts = append(opts, vl53l1x.WithBusSpeed(c.BusSpeed))
+	}
+
+	if c.Name != "" {
+		opts = append(opts, vl53l1x.WithName(c.Name))
+	}
+
+	if len(c.Labels) > 0 {
+		opts = append(opts, vl53l1x.WithLabels(c.Labels))
+	}
+
+	if c.Calibration != "" {
+		opts = append(opts, vl53l1x.WithStore(vl53l1x.NewFileStore(c.Calibration)))
+	}
+
+	return opts
+}
+
+// Open opens the sensor on the bus and address and applies the settings.
+// Further driver options can be given
+func (c Config) Open(opts ...vl53l1x.Option) (*vl53l1x.VL53L1X, error) {
+
+	sensor, err := vl53l1x.NewFromPath(c.Bus, uint8(c.Address), append(c.Options(), opts...)...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.Apply(sensor); err != nil {
+		sensor.Close()
+		return nil, err
+	}
+
+	return sensor, nil
+}
+
+// Apply writes the ranging settings, ROI and threshold to an initialized
+// sensor and, if PeriodMs is set, starts continuous ranging
+func (c Config) Apply(sensor *vl53l1x.VL53L1X) error {
+
+	if err := sensor.ApplyConfig(c.sensorConfig()); err != nil {
+		return err
+	}
+
+	if c.Threshold != nil {
+		if err := c.applyThreshold(sensor); err != nil {
+			return err
+		}
+	}
+
+	if c.PeriodMs > 0 {
+		return sensor.StartContinuous(c.PeriodMs)
+	}
+
+	return nil
+}
+
+// NeedsReopen reports whether moving from the old configuration requires
+// the sensor to be opened again, as the bus, address, bus speed or
+// calibration it was opened with changed
+func (c Config) NeedsReopen(old Config) bool {
+	return c.Bus != old.Bus || c.Address != old.Address ||
+		c.BusSpeed != old.BusSpeed || c.Calibration != old.Calibration
+}
+
+// Reconfigure writes the settings changed from the old configuration to a
+// sensor configured with it, without stopping ranging.  Settings written
+// while ranging take effect from the next measurement period, with the
+// measurement in progress discarded when the sensor is opened with
+// vl53l1x.WithHoldOff(vl53l1x.HoldOffAuto).  Only a change of period
+// restarts ranging.  Changes that need the sensor opened again, see
+// NeedsReopen(), return an error
+func (c Config) Reconfigure(sensor *vl53l1x.VL53L1X, old Config) error {
+
+	if c.NeedsReopen(old) {
+		return fmt.Errorf("bus, address, bus speed and calibration need the sensor opened again")
+	}
+
+	if c.Name != old.Name {
+		sensor.SetName(c.Name)
+	}
+
+	if !maps.Equal(c.Labels, old.Labels) {
+		sensor.SetLabels(c.Labels)
+	}
+
+	if c.Mode != old.Mode || c.TimingBudget != old.TimingBudget || !equalROI(c.ROI, old.ROI) {
+		cfg := c.sensorConfig()
+
+		// removing the ROI restores the full field of view
+		if c.ROI == nil && old.ROI != nil {
+			cfg.ROIWidth, cfg.ROIHeight, cfg.ROICenter = 16, 16, 199
+		}
+
+		if err := sensor.ApplyConfig(cfg); err != nil {
+			return err
+		}
+	}
+
+	if !equalThreshold(c.Threshold, old.Threshold) {
+		var err error
+
+		if c.Threshold != nil {
+			err = c.applyThreshold(sensor)
+		} else {
+			err = sensor.ClearDistanceThreshold()
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.PeriodMs == old.PeriodMs {
+		return nil
+	}
+
+	if old.PeriodMs > 0 {
+		if err := sensor.StopContinuous(); err != nil {
+			return err
+		}
+	}
+
+	if c.PeriodMs > 0 {
+		return sensor.StartContinuous(c.PeriodMs)
+	}
+
+	return nil
+}
+
+// sensorConfig returns the driver settings of the configuration
+func (c Config) sensorConfig() vl53l1x.Config {
+
+	cfg := vl53l1x.Config{
+		DistanceMode: c.Mode,
+		TimingBudget: c.TimingBudget,
+	}
+
+	if c.ROI != nil {
+		cfg.ROIWidth, cfg.ROIHeight, cfg.ROICenter = c.ROI.Width, c.ROI.Height, c.ROI.Center
+	}
+
+	return cfg
+}
+
+// applyThreshold writes the distance threshold to the sensor
+func (c Config) applyThreshold(sensor *vl53l1x.VL53L1X) error {
+
+	t := c.Threshold
+
+	return sensor.SetDistanceThreshold(t.LowMM, t.HighMM, t.Window, t.OnNoTarget)
+}
+
+// equalROI reports whether both ROIs are unset or the same
+func equalROI(a, b *ROI) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+// equalThreshold reports whether both thresholds are unset or the same
+func equalThreshold(a, b *Threshold) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+// Environment variables that override settings
+const (
+	EnvBus          = "VL53L1X_BUS"
+	EnvAddress      = "VL53L1X_ADDRESS"
+	EnvMode         = "VL53L1X_MODE"
+	EnvTimingBudget = "VL53L1X_TIMING_BUDGET"
+	EnvPeriodMs     = "VL53L1X_PERIOD_MS"
+	EnvCalibration  = "VL53L1X_CALIBRATION"
+)
+
+// OverlayEnv returns the configuration with the settings given in VL53L1X_*
+// environment variables replacing those of the file or defaults, for
+// containerized deployments where the file is shared by a fleet and devices
+// differ by bus or address
+func (c Config) OverlayEnv() (Config, error) {
+	return c.overlay(os.LookupEnv)
+}
+
+// LoadFileEnv reads the configuration file at path, or the defaults if path is
+// empty, and overlays the environment variables
+func LoadFileEnv(path string) (Config, error) {
+
+	cfg := Default()
+
+	if path != "" {
+		var err error
+
+		if cfg, err = LoadFile(path); err != nil {
+			return Config{}, err
+		}
+	}
+
+	return cfg.OverlayEnv()
+}
+
+// overlay applies the variables returned by lookup and validates the result
+func (c Config) overlay(lookup func(string) (string, bool)) (Config, error) {
+
+	if val, ok := lookup(EnvBus); ok {
+		c.Bus = val
+	}
+
+	if val, ok := lookup(EnvAddress); ok {
+		n, err := strconv.ParseUint(val, 0, 8)
+
+		if err != nil {
+			return Config{}, fmt.Errorf("%s: invalid address %q", EnvAddress, val)
+		}
+
+		c.Address = Address(n)
+	}
+
+	if val, ok := lookup(EnvMode); ok {
+		mode, err := vl53l1x.ParseDistanceMode(val)
+
+		if err != nil {
+			return Config{}, fmt.Errorf("%s: %w", EnvMode, err)
+		}
+
+		c.Mode = mode
+	}
+
+	if val, ok := lookup(EnvTimingBudget); ok {
+		n, err := strconv.ParseUint(val, 10, 32)
+
+		if err != nil {
+			return Config{}, fmt.Errorf("%s: invalid timing budget %q", EnvTimingBudget, val)
+		}
+
+		c.TimingBudget = uint32(n)
+	}
+
+	if val, ok := lookup(EnvPeriodMs); ok {
+		n, err := strconv.ParseUint(val, 10, 32)
+
+		if err != nil {
+			return Config{}, fmt.Errorf("%s: invalid period %q", EnvPeriodMs, val)
+		}
+
+		c.PeriodMs = uint32(n)
+	}
+
+	if val, ok := lookup(EnvCalibration); ok {
+		c.Calibration = val
+	}
+
+	if err := c.Validate(); err != nil {
+		return Config{}, err
+	}
+
+	return c, nil
+}
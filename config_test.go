@@ -0,0 +1,58 @@
+package vl53l1x_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+func TestApplyConfigKeepsDeviceMode(t *testing.T) {
+
+	v, _ := newSensor(t)
+	events := v.Events()
+
+	cfg := vl53l1x.Config{DistanceMode: vl53l1x.Short, TimingBudget: 50}
+
+	if err := v.ApplyConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	// the sensor already ranges in Short mode so only the budget is written
+	for len(events) > 0 {
+		if e, ok := (<-events).(vl53l1x.ConfigChangedEvent); ok && e.Setting == "distance mode" {
+			t.Error("distance mode written again")
+		}
+	}
+
+	if got, err := v.GetMeasurementTimingBudget(); err != nil || got != 50 {
+		t.Errorf("timing budget = %d, %v, want 50", got, err)
+	}
+}
+
+func TestDistanceModeUnmarshalJSON(t *testing.T) {
+
+	for _, tc := range []struct {
+		in   string
+		want vl53l1x.DistanceMode
+		ok   bool
+	}{
+		{`"long"`, vl53l1x.Long, true},
+		{`1`, vl53l1x.Medium, true},
+		{`3`, 0, false},
+		{`-1`, 0, false},
+		{`"far"`, 0, false},
+	} {
+		var m vl53l1x.DistanceMode
+		err := json.Unmarshal([]byte(tc.in), &m)
+
+		if (err == nil) != tc.ok {
+			t.Errorf("%s: err = %v", tc.in, err)
+			continue
+		}
+
+		if tc.ok && m != tc.want {
+			t.Errorf("%s: mode = %s, want %s", tc.in, m, tc.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package vl53l1x
+
+import "strings"
+
+// Validity is a set of flags describing inconsistencies found between the
+// fields of a measurement result.  Inconsistent results are often a sign of a
+// corrupted I2C block read
+type Validity uint8
+
+const (
+	// ZeroSignal is set when a valid range is reported with no signal rate
+	ZeroSignal Validity = 1 << iota
+	// ZeroSigma is set when a valid range is reported with no sigma estimate
+	ZeroSigma
+	// ZeroPhase is set when a non-zero valid range is reported with no phase
+	ZeroPhase
+	// StreamDiscontinuity is set when the stream count did not advance by one
+	// since the previous measurement, indicating a stale or dropped frame
+	StreamDiscontinuity
+	// BusFloat is set when every byte of the result block read 0xFF, which
+	// happens when the sensor does not drive the bus
+	BusFloat
+)
+
+// validityNames maps each Validity flag to its description
+var validityNames = []struct {
+	flag Validity
+	name string
+}{
+	{ZeroSignal, "zero signal"},
+	{ZeroSigma, "zero sigma"},
+	{ZeroPhase, "zero phase"},
+	{StreamDiscontinuity, "stream discontinuity"},
+	{BusFloat, "bus float"},
+}
+
+// Consistent returns true when no inconsistencies were flagged
+func (f Validity) Consistent() bool {
+	return f == 0
+}
+
+// String implement Stringer interface for Validity
+func (f Validity) String() string {
+
+	if f == 0 {
+		return "consistent"
+	}
+
+	names := []string{}
+
+	for _, vn := range validityNames {
+		if f&vn.flag != 0 {
+			names = append(names, vn.name)
+		}
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// validateResults cross checks the result buffer fields against each other and
+// the previous measurements stream count
+func (v *VL53L1X) validateResults(status RangeStatus) Validity {
+
+	var f Validity
+
+	floating := true
+
+	for _, b := range v.rbuf[:resultBufferSize] {
+		if b != 0xFF {
+			floating = false
+			break
+		}
+	}
+
+	if floating {
+		f |= BusFloat
+	}
+
+	if status == RangeValid {
+		if v.results.peakSignalCountRateMCPS_SD0 == 0 {
+			f |= ZeroSignal
+		}
+
+		if v.results.sigmaSD0 == 0 {
+			f |= ZeroSigma
+		}
+
+		if v.results.phaseSD0 == 0 && v.results.finalCrosstalkCorrectedRangeMM_SD0 != 0 {
+			f |= ZeroPhase
+		}
+	}
+
+	// stream count runs 0 to 255 then wraps back to 128
+	count := v.results.streamCount
+
+	if v.haveStreamCount {
+		expected := v.lastStreamCount + 1
+
+		if v.lastStreamCount == 255 {
+			expected = 128
+		}
+
+		if count != expected {
+			f |= StreamDiscontinuity
+		}
+	}
+
+	v.lastStreamCount = count
+	v.haveStreamCount = true
+
+	return f
+}
@@ -0,0 +1,182 @@
+package vl53l1x
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Coordinator time-multiplexes measurements across sensors with overlapping
+// fields of view so the light emitted by one does not corrupt the readings of
+// another.  Sensors are arranged in groups, the sensors of a group range at
+// the same time and the groups take turns.  Sensors that can not see each
+// other can share a group to raise the overall measurement rate
+type Coordinator struct {
+	groups [][]*VL53L1X
+}
+
+// SyncResult is a measurement taken by a sensor under a Coordinator
+type SyncResult struct {
+	Sensor *VL53L1X
+	// Group is the index of the group the sensor belongs to
+	Group int
+	Data  RangingData
+	Err   error
+}
+
+// RoundRobin returns groups of one sensor each, so every sensor ranges on
+// its own in turn
+func RoundRobin(sensors ...*VL53L1X) [][]*VL53L1X {
+
+	groups := make([][]*VL53L1X, len(sensors))
+
+	for i, s := range sensors {
+		groups[i] = []*VL53L1X{s}
+	}
+
+	return groups
+}
+
+// NewCoordinator returns a Coordinator that ranges the groups of sensors in
+// turn, use RoundRobin() to give each sensor its own turn
+func NewCoordinator(groups ...[]*VL53L1X) (*Coordinator, error) {
+
+	seen := make(map[*VL53L1X]bool)
+
+	for i, g := range groups {
+		if len(g) == 0 {
+			return nil, fmt.Errorf("sensor group %d is empty", i)
+		}
+
+		for _, s := range g {
+			if seen[s] {
+				return nil, fmt.Errorf("sensor 0x%02X is in more than one group", s.Address())
+			}
+
+			seen[s] = true
+		}
+	}
+
+	if len(seen) == 0 {
+		return nil, fmt.Errorf("no sensors to coordinate")
+	}
+
+	return &Coordinator{groups: groups}, nil
+}
+
+// Cycle takes one single shot measurement from every sensor, the sensors of
+// each group together and each group after the previous has finished.  The
+// results are returned in group order with any error of each sensor in its
+// result.  Continuous ranging must be stopped
+func (c *Coordinator) Cycle(ctx context.Context) ([]SyncResult, error) {
+
+	var results []SyncResult
+
+	for gi, g := range c.groups {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		res := make([]SyncResult, len(g))
+		var wg sync.WaitGroup
+
+		for i, s := range g {
+			res[i] = SyncResult{Sensor: s, Group: gi}
+
+			if s.ranging {
+				res[i].Err = fmt.Errorf("stop continuous ranging before coordinating measurements")
+				continue
+			}
+
+			wg.Add(1)
+
+			go func(r *SyncResult) {
+				defer wg.Done()
+				r.Data, r.Err = r.Sensor.readSingle(ctx)
+			}(&res[i])
+		}
+
+		wg.Wait()
+		results = append(results, res...)
+	}
+
+	return results, nil
+}
+
+// Run repeats Cycle() calling fn with each result until the context is
+// cancelled
+func (c *Coordinator) Run(ctx context.Context, fn func(SyncResult)) error {
+
+	for {
+		results, err := c.Cycle(ctx)
+
+		for _, r := range results {
+			fn(r)
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// StartStaggered starts continuous ranging on every sensor with the
+// inter-measurement period given, starting each group a slot of the period
+// divided by the number of groups after the previous so their measurements do
+// not overlap.  The timing budget of every sensor must fit in its slot.  The
+// sensors oscillators drift apart over time, call StartStaggered() again to
+// realign them
+func (c *Coordinator) StartStaggered(periodMs uint32) error {
+
+	slot := periodMs / uint32(len(c.groups))
+
+	for _, g := range c.groups {
+		for _, s := range g {
+			if s.timingBudget >= slot {
+				return fmt.Errorf("sensor 0x%02X timing budget %dms does not fit in %dms slot",
+					s.Address(), s.timingBudget, slot)
+			}
+		}
+	}
+
+	if err := c.Stop(); err != nil {
+		return err
+	}
+
+	for gi, g := range c.groups {
+		if gi > 0 {
+			time.Sleep(time.Duration(slot) * time.Millisecond)
+		}
+
+		for _, s := range g {
+			if err := s.StartContinuous(periodMs); err != nil {
+				return fmt.Errorf("sensor 0x%02X failed to start: %w", s.Address(), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Stop stops continuous ranging on every sensor that is ranging
+func (c *Coordinator) Stop() error {
+
+	for _, g := range c.groups {
+		for _, s := range g {
+			if !s.ranging {
+				continue
+			}
+
+			if err := s.StopContinuous(); err != nil {
+				return fmt.Errorf("sensor 0x%02X failed to stop: %w", s.Address(), err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package vl53l1x
+
+import "time"
+
+// CycleTiming breaks down where the time of a measurement cycle went, for
+// tuning the timing budget and period against the host's read loop
+type CycleTiming struct {
+	// First is set for the first measurement after continuous ranging or a
+	// single shot measurement was started, whose Wait is the first read
+	// latency including the sensors startup
+	First bool
+	// Wait is the time from the interrupt being cleared, or ranging started,
+	// to the measurement being seen ready, to within the 1ms data ready
+	// polling interval.  The sensor measures while the host processes the
+	// previous measurement, so Wait overlaps Host when the read cleared the
+	// interrupt.  It is 0 for non-blocking reads
+	Wait time.Duration
+	// Read is the time taken reading the results over the bus and clearing
+	// the interrupt
+	Read time.Duration
+	// Host is the time the caller spent between the previous read returning
+	// and this read starting, 0 for the first measurement
+	Host time.Duration
+}
+
+// cycleTimer holds the times measurement cycles are timed from
+type cycleTimer struct {
+	// armed is when the measurement being waited for was started, and first
+	// is set when it was started with ranging rather than by clearing the
+	// interrupt
+	armed time.Time
+	first bool
+	// returned is when the previous read returned
+	returned time.Time
+}
+
+// armCycle records the start of the next measurement, first when ranging was
+// just started
+func (v *VL53L1X) armCycle(first bool) {
+
+	if v.cycle == nil {
+		return
+	}
+
+	v.cycle.armed = time.Now()
+	v.cycle.first = first
+}
+
+// cycleTiming returns the timing of a measurement read between the times
+// called, ready and now, before the interrupt clear re-arms the timer
+func (v *VL53L1X) cycleTiming(blocking bool, called, ready time.Time) CycleTiming {
+
+	c := v.cycle
+
+	t := CycleTiming{First: c.first}
+
+	if blocking && !c.armed.IsZero() {
+		t.Wait = ready.Sub(c.armed)
+	}
+
+	if !c.first && !c.returned.IsZero() {
+		t.Host = called.Sub(c.returned)
+	}
+
+	return t
+}
+
+// endCycle records when a read returned to time the host's processing
+func (v *VL53L1X) endCycle() {
+
+	if v.cycle != nil {
+		v.cycle.returned = time.Now()
+	}
+}
@@ -0,0 +1,80 @@
+package vl53l1x
+
+import "time"
+
+// DebugState is a snapshot of the driver internals for inspecting a live
+// sensor process, safe to take from another goroutine while ranging such as
+// an HTTP debug handler
+type DebugState struct {
+	Stats Stats `json:"stats"`
+	// LastBusError is the text of Stats.LastBusError, empty if the last
+	// transfer succeeded
+	LastBusError string `json:"lastBusError,omitempty"`
+	// LastMeasurement is when the last measurement was read, with its
+	// status and range
+	LastMeasurement time.Time   `json:"lastMeasurement"`
+	LastStatus      RangeStatus `json:"lastStatus"`
+	LastRangeMM     uint16      `json:"lastRangeMM"`
+	// DistanceMode, TimingBudget, PeriodMs and Ranging are the settings the
+	// last measurement was taken with, PeriodMs is 0 when ranging back to back
+	DistanceMode DistanceMode `json:"distanceMode"`
+	TimingBudget uint32       `json:"timingBudget"`
+	PeriodMs     uint32       `json:"periodMs"`
+	Ranging      bool         `json:"ranging"`
+	// Name and Labels identify the sensor, see SetName() and SetLabels()
+	Name   string            `json:"name,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// debugSample is the state recorded with each measurement for DebugState()
+type debugSample struct {
+	time         time.Time
+	status       RangeStatus
+	rangeMM      uint16
+	distanceMode DistanceMode
+	timingBudget uint32
+	periodMs     uint32
+	ranging      bool
+}
+
+// DebugState returns the counters of Stats() with the last measurement and
+// the settings it was taken with
+func (v *VL53L1X) DebugState() DebugState {
+
+	s := DebugState{Stats: v.Stats()}
+
+	if s.Stats.LastBusError != nil {
+		s.LastBusError = s.Stats.LastBusError.Error()
+	}
+
+	v.stats.mu.Lock()
+	d := v.stats.last
+	v.stats.mu.Unlock()
+
+	s.LastMeasurement = d.time
+	s.LastStatus = d.status
+	s.LastRangeMM = d.rangeMM
+	s.DistanceMode = d.distanceMode
+	s.TimingBudget = d.timingBudget
+	s.PeriodMs = d.periodMs
+	s.Ranging = d.ranging
+	s.Name, s.Labels = v.Name(), v.Labels()
+
+	return s
+}
+
+// recordDebug records the measurement and current settings for DebugState()
+func (v *VL53L1X) recordDebug(rData RangingData) {
+
+	v.stats.mu.Lock()
+	v.stats.last = debugSample{
+		time:         time.Now(),
+		status:       rData.RangeStatus,
+		rangeMM:      rData.RangeMM,
+		distanceMode: v.distanceMode,
+		timingBudget: v.timingBudget,
+		periodMs:     v.periodMs,
+		ranging:      v.ranging,
+	}
+	v.stats.mu.Unlock()
+}
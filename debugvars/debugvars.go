@@ -0,0 +1,20 @@
+// Package debugvars publishes the driver internals of sensors through expvar,
+// so a live sensor process can be inspected at the /debug/vars endpoint of
+// net/http's default mux alongside the runtime's own variables.  It is kept
+// out of the vl53l1x package as importing expvar registers that endpoint.
+package debugvars
+
+import (
+	"expvar"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+// Publish publishes the sensors DebugState() under name, taking a fresh
+// snapshot on every request.  As with expvar.Publish() it panics if name is
+// already published
+func Publish(name string, sensor *vl53l1x.VL53L1X) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return sensor.DebugState()
+	}))
+}
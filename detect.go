@@ -0,0 +1,103 @@
+package vl53l1x
+
+import (
+	"fmt"
+	"os"
+)
+
+// modelIDs are the values of IDENTIFICATION_MODEL_ID reported by devices in
+// the VL53L1X family that are register compatible with this driver
+var modelIDs = map[uint16]string{
+	ModelID: "VL53L1X",
+	0xEBAA:  "VL53L4CD",
+}
+
+// DetectSensors probes the I2C bus at the given device path, eg: /dev/i2c-1,
+// and returns the addresses of devices that report a VL53L1X family model ID.
+// The 7-bit addresses 0x08 to 0x77 are probed unless addrs are given.  Each
+// address is first probed with a read only transfer and only devices that
+// respond have the model ID register pointer written to them.  Other devices
+// may treat that as a register write, so pass addrs to limit the scan on
+// shared buses.  Addresses claimed by a kernel driver are skipped.
+// ErrI2CUnsupported is returned on platforms without Linux i2c-dev
+func DetectSensors(busPath string, addrs ...uint8) ([]uint8, error) {
+
+	if !haveI2C {
+		return nil, ErrI2CUnsupported
+	}
+
+	if _, err := os.Stat(busPath); err != nil {
+		return nil, fmt.Errorf("I2C bus unavailable: %w", err)
+	}
+
+	return detectSensors(func(addr uint8) (Bus, error) {
+		return openI2C(addr, busPath)
+	}, addrs)
+}
+
+// detectSensors probes addrs, or 0x08 to 0x77 if empty, with buses opened by
+// open and returns the addresses of VL53L1X family devices
+func detectSensors(open func(addr uint8) (Bus, error), addrs []uint8) ([]uint8, error) {
+
+	if len(addrs) == 0 {
+		for addr := uint8(0x08); addr <= 0x77; addr++ {
+			addrs = append(addrs, addr)
+		}
+	}
+
+	found := []uint8{}
+
+	for _, addr := range addrs {
+
+		bus, err := open(addr)
+
+		if err != nil {
+			if bus != nil {
+				bus.Close()
+			}
+			continue
+		}
+
+		if probe(bus) {
+			if ok, _ := isSensor(bus); ok {
+				found = append(found, addr)
+			}
+		}
+
+		bus.Close()
+	}
+
+	return found, nil
+}
+
+// probe reads a byte from the device without writing to it, reporting
+// whether a device acknowledged the address
+func probe(bus Bus) bool {
+
+	var buf [1]byte
+	_, err := bus.ReadBytes(buf[:])
+
+	return err == nil
+}
+
+// isSensor reads the model ID over the bus and reports whether it belongs to
+// a VL53L1X family device
+func isSensor(bus Bus) (bool, error) {
+
+	v := &VL53L1X{bus: bus}
+	model, err := v.readReg16Bit(IDENTIFICATION_MODEL_ID)
+
+	if err != nil {
+		return false, err
+	}
+
+	return knownModel(model), nil
+}
+
+// knownModel reports whether model is the IDENTIFICATION_MODEL_ID of a
+// VL53L1X family device
+func knownModel(model uint16) bool {
+
+	_, ok := modelIDs[model]
+	return ok
+}
@@ -0,0 +1,87 @@
+package vl53l1x
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// probeBus is a Bus for a device that may be absent, recording the writes
+// made to it
+type probeBus struct {
+	addr    uint8
+	present bool
+	model   uint16
+	writes  map[uint8]int
+}
+
+func (b *probeBus) GetAddr() uint8 { return b.addr }
+func (b *probeBus) GetDev() string { return "probe" }
+func (b *probeBus) Close() error   { return nil }
+
+func (b *probeBus) ReadBytes(buf []byte) (int, error) {
+
+	if !b.present {
+		return 0, errors.New("nack")
+	}
+
+	if len(buf) == 2 {
+		buf[0], buf[1] = byte(b.model>>8), byte(b.model)
+	}
+
+	return len(buf), nil
+}
+
+func (b *probeBus) WriteBytes(buf []byte) (int, error) {
+
+	b.writes[b.addr]++
+
+	if !b.present {
+		return 0, errors.New("nack")
+	}
+
+	return len(buf), nil
+}
+
+func TestDetectSensorsProbesReadOnly(t *testing.T) {
+
+	writes := map[uint8]int{}
+	models := map[uint8]uint16{0x29: ModelID, 0x30: 0xEBAA, 0x50: 0x1234}
+
+	open := func(addr uint8) (Bus, error) {
+		model, ok := models[addr]
+		return &probeBus{addr: addr, present: ok, model: model, writes: writes}, nil
+	}
+
+	found, err := detectSensors(open, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []uint8{0x29, 0x30}; !reflect.DeepEqual(found, want) {
+		t.Errorf("found = %#v, want %#v", found, want)
+	}
+
+	for addr := range writes {
+		if _, ok := models[addr]; !ok {
+			t.Errorf("absent address 0x%02X was written to", addr)
+		}
+	}
+
+	clear(writes)
+
+	found, err = detectSensors(open, []uint8{0x30})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []uint8{0x30}; !reflect.DeepEqual(found, want) {
+		t.Errorf("found = %#v, want %#v", found, want)
+	}
+
+	if writes[0x29] != 0 || writes[0x50] != 0 {
+		t.Errorf("addresses outside the given range were written to: %v", writes)
+	}
+}
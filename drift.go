@@ -0,0 +1,208 @@
+package vl53l1x
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// DriftConfig configures a DriftMonitor
+type DriftConfig struct {
+	// Zone is the ROI that sees a static reference such as a fixed bracket
+	// edge at the border of the field of view
+	Zone SPADRect
+	// ReferenceMM is the expected distance to the reference, when 0 it is
+	// learnt from the first check
+	ReferenceMM uint16
+	// ToleranceMM is how far the long term mean may drift from the reference
+	// before a DriftEvent is emitted, defaults to 10mm
+	ToleranceMM uint16
+	// Interval between checks, defaults to 1 minute
+	Interval time.Duration
+	// Samples is the number of measurements taken on each check, defaults
+	// to 10
+	Samples int
+	// Smoothing is the weight between 0 and 1 given to each new check in the
+	// long term mean, defaults to 0.1
+	Smoothing float64
+}
+
+// DriftStatus holds the long term statistics of the reference distance
+type DriftStatus struct {
+	ReferenceMM uint16
+	// MeanMM is the long term mean of the measured distance
+	MeanMM float64
+	// MinMM and MaxMM are the extremes of the checks made
+	MinMM, MaxMM uint16
+	// Checks is the number of checks made
+	Checks int
+	// Drifted is true while the mean is beyond tolerance, signalling that
+	// offset recalibration or cleaning of the cover glass is needed
+	Drifted bool
+	// LastCheck is when the last check was made
+	LastCheck time.Time
+}
+
+// DriftMM returns the drift of the long term mean from the reference
+func (s DriftStatus) DriftMM() float64 {
+	return s.MeanMM - float64(s.ReferenceMM)
+}
+
+// DriftMonitor tracks the distance to a static reference in the field of view
+// over time and emits a DriftEvent when it drifts beyond tolerance
+type DriftMonitor struct {
+	v      *VL53L1X
+	cfg    DriftConfig
+	status DriftStatus
+}
+
+// NewDriftMonitor returns a DriftMonitor for the sensor
+func NewDriftMonitor(v *VL53L1X, cfg DriftConfig) (*DriftMonitor, error) {
+
+	if !cfg.Zone.valid() || cfg.Zone.Width < 4 || cfg.Zone.Height < 4 {
+		return nil, fmt.Errorf("reference zone must be at least 4x4 and fit in SPAD array")
+	}
+
+	if cfg.ToleranceMM == 0 {
+		cfg.ToleranceMM = 10
+	}
+
+	if cfg.Interval == 0 {
+		cfg.Interval = time.Minute
+	}
+
+	if cfg.Samples <= 0 {
+		cfg.Samples = 10
+	}
+
+	if cfg.Smoothing <= 0 || cfg.Smoothing > 1 {
+		cfg.Smoothing = 0.1
+	}
+
+	return &DriftMonitor{
+		v:      v,
+		cfg:    cfg,
+		status: DriftStatus{ReferenceMM: cfg.ReferenceMM},
+	}, nil
+}
+
+// Status returns the long term statistics
+func (m *DriftMonitor) Status() DriftStatus {
+	return m.status
+}
+
+// Due returns true when the check interval has passed since the last check,
+// for applications that interleave checks with their own measurements
+func (m *DriftMonitor) Due(now time.Time) bool {
+	return now.Sub(m.status.LastCheck) >= m.cfg.Interval
+}
+
+// Check measures the reference zone and updates the statistics.  Continuous
+// ranging is stopped for the measurements and restarted afterwards, and the
+// ROI is restored
+func (m *DriftMonitor) Check(ctx context.Context) (DriftStatus, error) {
+
+	v := m.v
+
+	resume, err := v.suspendRanging()
+
+	if err != nil {
+		return m.status, err
+	}
+
+	width, height, err := v.GetROISize()
+
+	if err != nil {
+		return m.status, err
+	}
+
+	center, err := v.GetROICenter()
+
+	if err != nil {
+		return m.status, err
+	}
+
+	res, err := v.MeasureZone(ctx, m.cfg.Zone, m.cfg.Samples)
+	v.restoreROI(width, height, center)
+
+	if rerr := resume(); err == nil {
+		err = rerr
+	}
+
+	if err != nil {
+		return m.status, err
+	}
+
+	if res.Valid == 0 {
+		return m.status, fmt.Errorf("no valid range to drift reference")
+	}
+
+	m.update(res.RangeMM, time.Now())
+
+	return m.status, nil
+}
+
+// Run checks the reference every interval until the context is cancelled.
+// As the sensor must not be used concurrently it is only for sensors that are
+// otherwise idle, applications that range use Due() and Check() between
+// their own reads instead
+func (m *DriftMonitor) Run(ctx context.Context) error {
+
+	tick := time.NewTicker(m.cfg.Interval)
+	defer tick.Stop()
+
+	for {
+		if _, err := m.Check(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			m.v.emitError("drift check", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-tick.C:
+		}
+	}
+}
+
+// update adds a check to the statistics and emits a DriftEvent when the
+// drift crosses the tolerance
+func (m *DriftMonitor) update(mm uint16, now time.Time) {
+
+	s := &m.status
+
+	if s.ReferenceMM == 0 {
+		s.ReferenceMM = mm
+	}
+
+	if s.Checks == 0 {
+		s.MeanMM = float64(mm)
+		s.MinMM, s.MaxMM = mm, mm
+	} else {
+		s.MeanMM += m.cfg.Smoothing * (float64(mm) - s.MeanMM)
+		s.MinMM = min(s.MinMM, mm)
+		s.MaxMM = max(s.MaxMM, mm)
+	}
+
+	s.Checks++
+	s.LastCheck = now
+
+	drifted := math.Abs(s.DriftMM()) > float64(m.cfg.ToleranceMM)
+
+	if drifted == s.Drifted {
+		return
+	}
+
+	s.Drifted = drifted
+
+	m.v.emit(DriftEvent{
+		Time:        now,
+		ReferenceMM: s.ReferenceMM,
+		MeanMM:      s.MeanMM,
+		Drifted:     drifted,
+	})
+}
@@ -0,0 +1,107 @@
+package vl53l1x
+
+import "fmt"
+
+// SetTargetSignalRate sets the target signal rate in 9.7 fixed point MCPS
+// that dynamic SPAD selection (DSS) aims for when choosing how many SPADs to
+// enable.  The default is TargetRate (20 MCPS).  A lower target may suit
+// scenes with cover glass or unusually reflective targets
+func (v *VL53L1X) SetTargetSignalRate(rate uint16) error {
+
+	if rate == 0 {
+		return fmt.Errorf("target signal rate must be greater than zero")
+	}
+
+	if err := v.writeReg16Bit(DSS_CONFIG_TARGET_TOTAL_RATE_MCPS, rate); err != nil {
+		return err
+	}
+
+	v.targetRate = rate
+	v.configChanged("target signal rate")
+
+	return nil
+}
+
+// GetTargetSignalRate returns the DSS target signal rate in 9.7 fixed point
+// MCPS
+func (v *VL53L1X) GetTargetSignalRate() uint16 {
+	return v.targetRate
+}
+
+// DSSMode selects how dynamic SPAD selection chooses the SPADs enabled, as
+// programmed into bits 0-1 of DSS_CONFIG_ROI_MODE_CONTROL
+type DSSMode uint8
+
+const (
+	// DSSDisabled disables dynamic SPAD selection
+	DSSDisabled DSSMode = 0
+	// DSSTargetRate has the firmware select SPADs to meet the target signal
+	// rate
+	DSSTargetRate DSSMode = 1
+	// DSSRequestedEffectiveSPADs enables the number of SPADs requested in
+	// DSS_CONFIG_MANUAL_EFFECTIVE_SPADS_SELECT.  This is the default, with
+	// the driver calculating the request from the target signal rate after
+	// each continuous measurement as the low power auto mode of ST's API
+	// does.  Single shot measurements use DSSTargetRate
+	DSSRequestedEffectiveSPADs DSSMode = 2
+	// DSSBlockSelect enables a fixed block of SPADs
+	DSSBlockSelect DSSMode = 3
+)
+
+// DefaultApertureAttenuation is the DSS_CONFIG_APERTURE_ATTENUATION value set
+// during initialization
+const DefaultApertureAttenuation uint8 = 0x38
+
+// SetDSSMode sets the dynamic SPAD selection mode.  Note the driver updates the
+// requested SPAD count after every measurement which only has effect in
+// DSSRequestedEffectiveSPADs mode
+func (v *VL53L1X) SetDSSMode(mode DSSMode) error {
+
+	if mode > DSSBlockSelect {
+		return fmt.Errorf("invalid DSS mode %d", mode)
+	}
+
+	val, err := v.readReg(DSS_CONFIG_ROI_MODE_CONTROL)
+
+	if err != nil {
+		return err
+	}
+
+	if err := v.writeReg(DSS_CONFIG_ROI_MODE_CONTROL, val&^0x03|uint8(mode)); err != nil {
+		return err
+	}
+
+	v.configChanged("dss mode")
+	return nil
+}
+
+// GetDSSMode returns the dynamic SPAD selection mode
+func (v *VL53L1X) GetDSSMode() (DSSMode, error) {
+
+	val, err := v.readReg(DSS_CONFIG_ROI_MODE_CONTROL)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return DSSMode(val & 0x03), nil
+}
+
+// SetApertureAttenuation sets the attenuation factor DSS applies to account
+// for the optical aperture when calculating the signal rate per SPAD.  The
+// default is DefaultApertureAttenuation, optical integrators may tune it for
+// cover glass or apertures that differ from ST's reference design
+func (v *VL53L1X) SetApertureAttenuation(val uint8) error {
+
+	if err := v.writeReg(DSS_CONFIG_APERTURE_ATTENUATION, val); err != nil {
+		return err
+	}
+
+	v.configChanged("aperture attenuation")
+	return nil
+}
+
+// GetApertureAttenuation returns the DSS aperture attenuation factor
+func (v *VL53L1X) GetApertureAttenuation() (uint8, error) {
+	return v.readReg(DSS_CONFIG_APERTURE_ATTENUATION)
+}
@@ -0,0 +1,160 @@
+package vl53l1x
+
+import (
+	"sync"
+	"time"
+)
+
+// eventBufferSize is the number of events buffered on the Events() channel
+// before new events are dropped
+const eventBufferSize = 64
+
+// Event is emitted on the Events() channel.  Use a type switch to determine
+// which of MeasurementEvent, ThresholdEvent, ErrorEvent, RecoveryEvent,
+// ConfigChangedEvent, DriftEvent, SmudgeEvent or DisconnectedEvent it is
+type Event interface {
+	// EventTime returns when the event occurred
+	EventTime() time.Time
+}
+
+// MeasurementEvent is emitted for each measurement read from the sensor
+type MeasurementEvent struct {
+	Time time.Time
+	Data RangingData
+	// Name and Labels identify the sensor, see SetName() and SetLabels().
+	// Labels is shared between events and must not be modified
+	Name   string
+	Labels map[string]string
+}
+
+// ThresholdEvent is emitted when a measurement meets a configured threshold
+type ThresholdEvent struct {
+	Time time.Time
+	Data RangingData
+	// Name and Labels identify the sensor as for MeasurementEvent
+	Name   string
+	Labels map[string]string
+}
+
+// ErrorEvent is emitted when an operation on the sensor fails
+type ErrorEvent struct {
+	Time time.Time
+	// Op is the name of the operation that failed
+	Op  string
+	Err error
+}
+
+// RecoveryEvent is emitted when the sensor recovers from a failure
+type RecoveryEvent struct {
+	Time   time.Time
+	Reason string
+}
+
+// ConfigChangedEvent is emitted when a sensor setting is changed
+type ConfigChangedEvent struct {
+	Time time.Time
+	// Setting is the name of the setting changed
+	Setting string
+}
+
+// DriftEvent is emitted by a DriftMonitor when the distance measured to its
+// reference moves beyond tolerance, or returns within it
+type DriftEvent struct {
+	Time time.Time
+	// ReferenceMM is the expected distance and MeanMM the long term mean of
+	// the distance measured
+	ReferenceMM uint16
+	MeanMM      float64
+	// Drifted is true when beyond tolerance
+	Drifted bool
+}
+
+// EventTime returns when the event occurred
+func (e MeasurementEvent) EventTime() time.Time { return e.Time }
+
+// EventTime returns when the event occurred
+func (e ThresholdEvent) EventTime() time.Time { return e.Time }
+
+// EventTime returns when the event occurred
+func (e ErrorEvent) EventTime() time.Time { return e.Time }
+
+// EventTime returns when the event occurred
+func (e RecoveryEvent) EventTime() time.Time { return e.Time }
+
+// EventTime returns when the event occurred
+func (e ConfigChangedEvent) EventTime() time.Time { return e.Time }
+
+// EventTime returns when the event occurred
+func (e DriftEvent) EventTime() time.Time { return e.Time }
+
+// eventBus holds the lazily created events channel, closed is set once the
+// sensor is closed so no new channel is created
+type eventBus struct {
+	mu     sync.Mutex
+	ch     chan Event
+	closed bool
+}
+
+// Events returns a channel on which all events from the sensor are emitted.
+// Events are only generated once this has been called.  If the channel is not
+// drained and its buffer fills, new events are dropped rather than blocking
+// the sensor.  The channel returned once the sensor is closed is closed
+func (v *VL53L1X) Events() <-chan Event {
+
+	v.events.mu.Lock()
+	defer v.events.mu.Unlock()
+
+	if v.events.closed {
+		ch := make(chan Event)
+		close(ch)
+		return ch
+	}
+
+	if v.events.ch == nil {
+		v.events.ch = make(chan Event, eventBufferSize)
+	}
+
+	return v.events.ch
+}
+
+// listening returns true once Events() has been called, so events emitted
+// with every measurement are only built when they will be sent
+func (v *VL53L1X) listening() bool {
+
+	v.events.mu.Lock()
+	defer v.events.mu.Unlock()
+
+	return v.events.ch != nil
+}
+
+// emit sends the event if Events() has been called
+func (v *VL53L1X) emit(e Event) {
+
+	v.events.mu.Lock()
+	defer v.events.mu.Unlock()
+
+	if v.events.ch == nil {
+		return
+	}
+
+	select {
+	case v.events.ch <- e:
+	default:
+		v.log.Printf("Event buffer full, dropping %T", e)
+	}
+}
+
+// emitError emits an ErrorEvent for a failed operation and returns the error
+func (v *VL53L1X) emitError(op string, err error) error {
+
+	if err != nil {
+		v.emit(ErrorEvent{Time: time.Now(), Op: op, Err: err})
+	}
+
+	return err
+}
+
+// emitConfigChanged emits a ConfigChangedEvent for the setting
+func (v *VL53L1X) emitConfigChanged(setting string) {
+	v.emit(ConfigChangedEvent{Time: time.Now(), Setting: setting})
+}
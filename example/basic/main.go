@@ -1,34 +1,35 @@
+// Command basic reads distance using continuous ranging and a region of
+// interest
 package main
 
 import (
 	"flag"
 	"fmt"
-	"github.com/swdee/go-i2c"
-	"github.com/swdee/go-vl53l1x"
 	"log"
 	"time"
+
+	"github.com/swdee/go-vl53l1x"
+	"github.com/swdee/go-vl53l1x/example/internal/device"
 )
 
 func main() {
 
 	i2cbus := flag.String("b", "/dev/i2c-0", "Path to I2C bus to use")
+	count := flag.Int("n", 10, "Number of measurements to read")
+	mode := vl53l1x.Short
+	flag.Var(&mode, "m", "Distance mode short, medium or long")
 	flag.Parse()
 
-	// Open I2C bus (adjust bus number and default address as needed)
-	i2c, err := i2c.New(vl53l1x.Address, *i2cbus)
+	// open sensor running in the distance mode, Short by default, with timing
+	// budget 50ms, or the simulator if there is no I2C bus
+	sensor, err := device.Open(*i2cbus, vl53l1x.Address, nil,
+		vl53l1x.WithDistanceMode(mode), vl53l1x.WithTimingBudget(50))
 
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	defer i2c.Close()
-
-	// create new sensor instance running in Short mode with timing budget 50ms
-	sensor, err := vl53l1x.New(i2c, vl53l1x.Short, 50)
-
-	if err != nil {
-		log.Fatal(err)
-	}
+	defer sensor.Close()
 
 	// define a region of interest.  This is not necessary so can be commented
 	// out if not required.
@@ -41,7 +42,7 @@ func main() {
 	}
 
 	// Read a measurement
-	for i := 0; i < 10; i++ {
+	for i := 0; i < *count; i++ {
 
 		data, err := sensor.Read(true)
 
@@ -60,7 +61,7 @@ func main() {
 		log.Fatalf("Stop continuous failed: %v", err)
 	}
 
-	// I2C connection is closed by earlier defer statement
+	// sensor is closed by earlier defer statement
 }
 
 // setROI sets the region of interest
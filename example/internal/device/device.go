@@ -0,0 +1,45 @@
+// Package device opens the sensor used by the examples, falling back to the
+// simulator when no I2C bus is available so every example can be run on a
+// development machine
+package device
+
+import (
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+	"github.com/swdee/go-vl53l1x/sim"
+)
+
+// Scene is a function returning the simulated scene at the time elapsed since
+// the sensor was opened, it is only used when running on the simulator
+type Scene func(elapsed time.Duration) sim.Scene
+
+// Open returns the sensor at addr on the I2C bus at path.  If the bus does not
+// exist or the platform has no I2C support a simulated sensor measuring the
+// scene is returned instead
+func Open(path string, addr uint8, scene Scene, opts ...vl53l1x.Option) (*vl53l1x.VL53L1X, error) {
+
+	if _, err := os.Stat(path); err == nil {
+
+		sensor, err := vl53l1x.NewFromPath(path, addr, opts...)
+
+		if !errors.Is(err, vl53l1x.ErrI2CUnsupported) {
+			return sensor, err
+		}
+	}
+
+	log.Printf("No I2C bus at %s, using simulated sensor at 0x%02X", path, addr)
+
+	s := sim.New(addr)
+
+	if scene != nil {
+		s.SetSceneFunc(scene)
+	}
+
+	opts = append([]vl53l1x.Option{vl53l1x.WithBusOpener(s.Open)}, opts...)
+
+	return vl53l1x.New(s, vl53l1x.DefaultDistanceMode, vl53l1x.DefaultTimingBudget, opts...)
+}
@@ -0,0 +1,87 @@
+// Command multisensor ranges several sensors on the same bus that have already
+// been assigned unique addresses, eg: with SetAddress() while holding the
+// other sensors in reset via their XSHUT pins
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+	"github.com/swdee/go-vl53l1x/example/internal/device"
+	"github.com/swdee/go-vl53l1x/sim"
+)
+
+func main() {
+
+	i2cbus := flag.String("b", "/dev/i2c-0", "Path to I2C bus to use")
+	addrList := flag.String("a", "0x29,0x30", "Comma separated sensor addresses")
+	count := flag.Int("n", 10, "Number of measurements to read from each sensor")
+	flag.Parse()
+
+	var sensors []*vl53l1x.VL53L1X
+
+	for i, s := range strings.Split(*addrList, ",") {
+
+		addr, err := strconv.ParseUint(strings.TrimSpace(s), 0, 8)
+
+		if err != nil {
+			log.Fatalf("Invalid address %q: %v", s, err)
+		}
+
+		// give each simulated sensor its own target distance
+		distance := 300 + 400*float64(i)
+		scene := func(time.Duration) sim.Scene {
+			sc := sim.DefaultScene()
+			sc.DistanceMM = distance
+			return sc
+		}
+
+		sensor, err := device.Open(*i2cbus, uint8(addr), scene,
+			vl53l1x.WithTimingBudget(50))
+
+		if err != nil {
+			log.Fatalf("Sensor 0x%02X: %v", addr, err)
+		}
+
+		defer sensor.Close()
+
+		sensors = append(sensors, sensor)
+	}
+
+	// start all sensors so they range in parallel
+	for _, sensor := range sensors {
+		if err := sensor.StartContinuous(55); err != nil {
+			log.Fatalf("Sensor 0x%02X start continuous failed: %v", sensor.Address(), err)
+		}
+	}
+
+	for i := 0; i < *count; i++ {
+
+		line := []string{}
+
+		for _, sensor := range sensors {
+
+			data, err := sensor.Read(true)
+
+			if err != nil {
+				line = append(line, fmt.Sprintf("0x%02X: error %v", sensor.Address(), err))
+				continue
+			}
+
+			line = append(line, fmt.Sprintf("0x%02X: %4d mm", sensor.Address(), data.RangeMM))
+		}
+
+		fmt.Println(strings.Join(line, "  "))
+	}
+
+	for _, sensor := range sensors {
+		if err := sensor.StopContinuous(); err != nil {
+			log.Printf("Sensor 0x%02X stop continuous failed: %v", sensor.Address(), err)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Command peoplecount counts people passing under a ceiling mounted sensor by
+// alternating the ROI between the two halves of the SPAD array and following
+// the order in which the halves are blocked
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+	"github.com/swdee/go-vl53l1x/app/occupancy"
+	"github.com/swdee/go-vl53l1x/example/internal/device"
+	"github.com/swdee/go-vl53l1x/sim"
+)
+
+// zone bits making up the state of the doorway
+const (
+	left  = 1
+	right = 2
+)
+
+func main() {
+
+	i2cbus := flag.String("b", "/dev/i2c-0", "Path to I2C bus to use")
+	threshold := flag.Int("t", 1400, "Distance in mm below which a zone is occupied")
+	count := flag.Int("n", 200, "Number of zone measurements to take")
+	flag.Parse()
+
+	// simulated floor at 2m with a person passing under the sensor for 1.5
+	// seconds out of every 4
+	scene := func(elapsed time.Duration) sim.Scene {
+		sc := sim.DefaultScene()
+		sc.DistanceMM = 2000
+
+		if elapsed%(4*time.Second) > 2*time.Second+500*time.Millisecond {
+			sc.DistanceMM = 900
+		}
+
+		return sc
+	}
+
+	sensor, err := device.Open(*i2cbus, vl53l1x.Address, scene,
+		vl53l1x.WithDistanceMode(vl53l1x.Long), vl53l1x.WithTimingBudget(33))
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer sensor.Close()
+
+	zones := []vl53l1x.SPADRect{
+		{Col: 0, Row: 0, Width: 8, Height: 16},
+		{Col: 8, Row: 0, Width: 8, Height: 16},
+	}
+
+	agg := occupancy.New()
+	state := 0
+	path := []int{}
+
+	for i := 0; i < *count; i++ {
+
+		z := i % 2
+		res, err := sensor.MeasureZone(context.Background(), zones[z], 1)
+
+		if err != nil {
+			log.Printf("Zone %d: %v", z, err)
+			continue
+		}
+
+		bit := left << z
+		next := state &^ bit
+
+		if res.Valid > 0 && int(res.RangeMM) < *threshold {
+			next |= bit
+		}
+
+		if next == state {
+			continue
+		}
+
+		state = next
+		path = append(path, state)
+
+		if state != 0 {
+			continue
+		}
+
+		// doorway is clear again, a full pass blocks one side, then both,
+		// then only the other side
+		if passed(path, left, right) {
+			agg.Enter(time.Now())
+			log.Printf("Enter, occupancy %d", agg.Stats().Occupancy)
+		} else if passed(path, right, left) {
+			agg.Exit(time.Now())
+			log.Printf("Exit, occupancy %d", agg.Stats().Occupancy)
+		}
+
+		path = path[:0]
+	}
+
+	if err := agg.WriteJSON(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// passed reports whether the path of doorway states ending in 0 crossed from
+// the first zone to the second
+func passed(path []int, first, second int) bool {
+
+	if len(path) < 4 {
+		return false
+	}
+
+	return path[0] == first && path[len(path)-2] == second &&
+		contains(path, left|right)
+}
+
+// contains reports whether the path includes the state
+func contains(path []int, state int) bool {
+
+	for _, s := range path {
+		if s == state {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,92 @@
+// Command publish writes rate limited readings as JSON lines to stdout for
+// publishing to a message broker.  For MQTT pipe the output to a client with
+// a topic per sensor name, eg:
+//
+//	publish -name front-left | mosquitto_pub -l -t sensors/vl53l1x/front-left
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+	"github.com/swdee/go-vl53l1x/example/internal/device"
+	"github.com/swdee/go-vl53l1x/sink"
+)
+
+// message is the JSON published for each reading
+type message struct {
+	Time       time.Time `json:"time"`
+	Name       string    `json:"name,omitempty"`
+	Address    uint8     `json:"address"`
+	RangeMM    uint16    `json:"rangeMM"`
+	Status     string    `json:"status"`
+	SignalMCPS float32   `json:"signalMCPS"`
+}
+
+func main() {
+
+	i2cbus := flag.String("b", "/dev/i2c-0", "Path to I2C bus to use")
+	interval := flag.Duration("i", time.Second, "Minimum interval between published readings")
+	count := flag.Int("n", 50, "Number of measurements to read")
+	name := flag.String("name", "", "Name identifying the sensor in the readings")
+	flag.Parse()
+
+	sensor, err := device.Open(*i2cbus, vl53l1x.Address, nil,
+		vl53l1x.WithTimingBudget(50), vl53l1x.WithName(*name))
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer sensor.Close()
+
+	enc := json.NewEncoder(os.Stdout)
+
+	out := sink.NewRateLimited(sink.Func(func(data vl53l1x.RangingData) error {
+		return enc.Encode(message{
+			Time:       time.Now(),
+			Name:       sensor.Name(),
+			Address:    sensor.Address(),
+			RangeMM:    data.RangeMM,
+			Status:     data.RangeStatus.String(),
+			SignalMCPS: data.PeakSignalCountRateMCPS,
+		})
+	}), *interval)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := sensor.Events()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		sink.Pipe(ctx, events, out, func(err error) {
+			log.Printf("Publish error: %v", err)
+		})
+	}()
+
+	if err := sensor.StartContinuous(55); err != nil {
+		log.Fatalf("Start continuous failed: %v", err)
+	}
+
+	// readings are published from the sensors event channel
+	for i := 0; i < *count; i++ {
+		if _, err := sensor.Read(true); err != nil {
+			log.Printf("Read error: %v", err)
+		}
+	}
+
+	if err := sensor.StopContinuous(); err != nil {
+		log.Printf("Stop continuous failed: %v", err)
+	}
+
+	cancel()
+	wg.Wait()
+}
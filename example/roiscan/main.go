@@ -0,0 +1,62 @@
+// Command roiscan measures each zone of a grid laid over the SPAD array to
+// build a coarse depth map of the scene
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/swdee/go-vl53l1x"
+	"github.com/swdee/go-vl53l1x/example/internal/device"
+)
+
+func main() {
+
+	i2cbus := flag.String("b", "/dev/i2c-0", "Path to I2C bus to use")
+	cols := flag.Uint("cols", 4, "Number of grid columns (1-4)")
+	rows := flag.Uint("rows", 4, "Number of grid rows (1-4)")
+	samples := flag.Int("s", 3, "Samples averaged per zone")
+	count := flag.Int("n", 1, "Number of scans")
+	flag.Parse()
+
+	sensor, err := device.Open(*i2cbus, vl53l1x.Address, nil,
+		vl53l1x.WithTimingBudget(33))
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer sensor.Close()
+
+	zones, err := vl53l1x.ZoneGrid(uint8(*cols), uint8(*rows))
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for i := 0; i < *count; i++ {
+
+		for z, zone := range zones {
+
+			res, err := sensor.MeasureZone(context.Background(), zone, *samples)
+
+			switch {
+			case err != nil:
+				log.Printf("Zone %v: %v", zone, err)
+				fmt.Print("   err")
+			case res.Valid == 0:
+				fmt.Print("     -")
+			default:
+				fmt.Printf("%6d", res.RangeMM)
+			}
+
+			if (z+1)%int(*cols) == 0 {
+				fmt.Println()
+			}
+		}
+
+		fmt.Println()
+	}
+}
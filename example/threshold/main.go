@@ -0,0 +1,78 @@
+// Command threshold reports when a target enters or leaves a distance window,
+// using hysteresis so a target sitting on the boundary does not chatter
+package main
+
+import (
+	"flag"
+	"log"
+	"math"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+	"github.com/swdee/go-vl53l1x/example/internal/device"
+	"github.com/swdee/go-vl53l1x/sim"
+)
+
+func main() {
+
+	i2cbus := flag.String("b", "/dev/i2c-0", "Path to I2C bus to use")
+	low := flag.Int("low", 300, "Near edge of the window in mm")
+	high := flag.Int("high", 700, "Far edge of the window in mm")
+	hyst := flag.Int("hyst", 20, "Hysteresis in mm")
+	count := flag.Int("n", 100, "Number of measurements to read")
+	flag.Parse()
+
+	// simulated target swinging between 100mm and 1100mm every 4 seconds
+	scene := func(elapsed time.Duration) sim.Scene {
+		sc := sim.DefaultScene()
+		sc.DistanceMM = 600 + 500*math.Sin(2*math.Pi*elapsed.Seconds()/4)
+		return sc
+	}
+
+	sensor, err := device.Open(*i2cbus, vl53l1x.Address, scene,
+		vl53l1x.WithDistanceMode(vl53l1x.Short), vl53l1x.WithTimingBudget(33))
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer sensor.Close()
+
+	if err := sensor.StartContinuous(40); err != nil {
+		log.Fatalf("Start continuous failed: %v", err)
+	}
+
+	inside := false
+
+	for i := 0; i < *count; i++ {
+
+		data, err := sensor.Read(true)
+
+		if err != nil {
+			log.Printf("Read error: %v", err)
+			continue
+		}
+
+		if !data.RangeStatus.IsValid() {
+			continue
+		}
+
+		mm := int(data.RangeMM)
+
+		// widen the window by the hysteresis once inside so the target must
+		// move clearly out before leaving
+		if inside {
+			if mm < *low-*hyst || mm > *high+*hyst {
+				inside = false
+				log.Printf("Target left window at %d mm", mm)
+			}
+		} else if mm >= *low && mm <= *high {
+			inside = true
+			log.Printf("Target entered window at %d mm", mm)
+		}
+	}
+
+	if err := sensor.StopContinuous(); err != nil {
+		log.Fatalf("Stop continuous failed: %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package vl53l1x
+
+import (
+	"fmt"
+	"math"
+)
+
+// fovTable is the approximate field of view in degrees for square ROI sizes
+// as given in UM2555
+var fovTable = []struct {
+	size uint8
+	deg  float64
+}{
+	{4, 15},
+	{8, 20},
+	{16, 27},
+}
+
+// SPADFieldOfView returns the approximate field of view in degrees covered by
+// the given number of SPADs along one axis of the ROI.  Values between those
+// published by ST are linearly interpolated
+func SPADFieldOfView(spads uint8) float64 {
+
+	if spads <= fovTable[0].size {
+		return fovTable[0].deg
+	}
+
+	for i := 1; i < len(fovTable); i++ {
+		lo, hi := fovTable[i-1], fovTable[i]
+
+		if spads <= hi.size {
+			return lo.deg + (hi.deg-lo.deg)*float64(spads-lo.size)/float64(hi.size-lo.size)
+		}
+	}
+
+	return fovTable[len(fovTable)-1].deg
+}
+
+// ROIFieldOfView returns the approximate horizontal and vertical field of
+// view in degrees for a ROI of the given width and height
+func ROIFieldOfView(width, height uint8) (horizontal, vertical float64) {
+	return SPADFieldOfView(width), SPADFieldOfView(height)
+}
+
+// FootprintDiameter returns the diameter of the area seen by the sensor at the
+// given distance for a field of view in degrees.  The returned diameter is in
+// the same unit as the distance
+func FootprintDiameter(fovDeg, distance float64) float64 {
+	return 2 * distance * math.Tan(fovDeg*math.Pi/360)
+}
+
+// ROISizeForTarget returns the largest square ROI size whose footprint at the
+// given distance fits within a target of the given size, so the sensor only
+// sees the target.  The target size and distance must be in the same unit
+func ROISizeForTarget(targetSize, distance float64) (uint8, error) {
+
+	if targetSize <= 0 || distance <= 0 {
+		return 0, fmt.Errorf("target size and distance must be positive")
+	}
+
+	for size := uint8(16); size >= 4; size-- {
+		if FootprintDiameter(SPADFieldOfView(size), distance) <= targetSize {
+			return size, nil
+		}
+	}
+
+	return 0, fmt.Errorf("target is smaller than the minimum 4x4 ROI footprint of %.1f",
+		FootprintDiameter(SPADFieldOfView(4), distance))
+}
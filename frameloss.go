@@ -0,0 +1,72 @@
+package vl53l1x
+
+import "fmt"
+
+// FrameLossError is returned by the read methods when more continuous
+// measurements were lost since the previous read than the tolerance set with
+// WithFrameLossTolerance().  The measurement is carried so it can still be
+// used
+type FrameLossError struct {
+	// Lost is the number of measurements lost before this one
+	Lost int
+	Data RangingData
+}
+
+// Error returns the error message
+func (e *FrameLossError) Error() string {
+	return fmt.Sprintf("%d measurements lost before this one", e.Lost)
+}
+
+// frameLoss tracks the stream count between reads to count the measurements
+// the sensor completed that were never read
+type frameLoss struct {
+	// tolerance is the number of measurements that may be lost between two
+	// reads before a FrameLossError is returned, negative to only count them
+	tolerance int
+	last      uint8
+	have      bool
+}
+
+// reset forgets the last stream count, as it restarts with ranging
+func (f *frameLoss) reset() {
+	if f != nil {
+		f.have = false
+	}
+}
+
+// countLostFrames returns the number of measurements lost since the previous
+// read, adding them to the LostFrames counter
+func (v *VL53L1X) countLostFrames() int {
+
+	f := v.loss
+
+	// single shot measurements are only taken when requested
+	if f == nil || v.singleShot {
+		return 0
+	}
+
+	count := v.results.streamCount
+	lost := 0
+
+	if f.have {
+		lost = max(streamDelta(f.last, count)-1, 0)
+	}
+
+	f.last = count
+	f.have = true
+
+	v.stats.lostFrames.Add(uint64(lost))
+
+	return lost
+}
+
+// checkFrameLoss returns a FrameLossError when more measurements were lost
+// before rData than the tolerance
+func (v *VL53L1X) checkFrameLoss(rData RangingData) error {
+
+	if v.loss == nil || v.loss.tolerance < 0 || rData.LostFrames <= v.loss.tolerance {
+		return nil
+	}
+
+	return &FrameLossError{Lost: rData.LostFrames, Data: rData}
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package fsexport
+
+import "fmt"
+
+// mkfifo is unsupported as named pipes are not available on this platform
+func mkfifo(path string) error {
+	return fmt.Errorf("named pipes are not supported on this platform")
+}
+
+// writeFIFO is a no-op as named pipes are not available on this platform
+func writeFIFO(path, line string) {}
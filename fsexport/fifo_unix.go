@@ -0,0 +1,36 @@
+//go:build unix
+
+package fsexport
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// mkfifo creates the named pipe if it does not exist
+func mkfifo(path string) error {
+
+	err := syscall.Mkfifo(path, 0644)
+
+	if errors.Is(err, os.ErrExist) {
+		return nil
+	}
+
+	return err
+}
+
+// writeFIFO writes the line to the named pipe without blocking, dropping it if
+// no process has the pipe open for reading
+func writeFIFO(path, line string) {
+
+	f, err := os.OpenFile(path, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+
+	if err != nil {
+		return
+	}
+
+	defer f.Close()
+
+	f.WriteString(line)
+}
@@ -0,0 +1,137 @@
+// Package fsexport exposes sensor readings through files in the style of the
+// Linux industrial I/O (IIO) sysfs interface and an optional named pipe, so
+// scripts and non-Go programs on the same host can consume them.
+package fsexport
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+// Reader is the sensor interface used by the Exporter, satisfied by
+// *vl53l1x.VL53L1X
+type Reader interface {
+	ReadContext(ctx context.Context) (vl53l1x.RangingData, error)
+}
+
+// Config holds the export settings
+type Config struct {
+	// Dir is the directory the attribute files are written to, it is
+	// created if it does not exist
+	Dir string
+	// FIFOPath is an optional named pipe that each reading is written to as a
+	// line of space separated values: timestamp (unix ns), distance (mm),
+	// status, signal rate (MCPS) and ambient rate (MCPS).  The pipe is
+	// created if it does not exist and readings are skipped when nothing is
+	// reading from it
+	FIFOPath string
+	// Interval between exported readings, 0 exports every measurement
+	Interval time.Duration
+}
+
+// Exporter writes sensor readings to the file system
+type Exporter struct {
+	sensor Reader
+	cfg    Config
+	last   time.Time
+}
+
+// New returns an Exporter for the sensor
+func New(sensor Reader, cfg Config) (*Exporter, error) {
+
+	if cfg.Dir == "" {
+		return nil, fmt.Errorf("export directory must be set")
+	}
+
+	if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
+		return nil, err
+	}
+
+	if cfg.FIFOPath != "" {
+		if err := mkfifo(cfg.FIFOPath); err != nil {
+			return nil, fmt.Errorf("failed to create fifo: %w", err)
+		}
+	}
+
+	// IIO style scale converting the raw distance in mm to meters
+	if err := writeAttr(cfg.Dir, "in_distance_scale", "0.001"); err != nil {
+		return nil, err
+	}
+
+	return &Exporter{sensor: sensor, cfg: cfg}, nil
+}
+
+// Run reads measurements from the sensor, which must already be ranging, and
+// exports them until the context is cancelled
+func (e *Exporter) Run(ctx context.Context) error {
+
+	for {
+		data, err := e.sensor.ReadContext(ctx)
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+
+		if e.cfg.Interval > 0 && now.Sub(e.last) < e.cfg.Interval {
+			continue
+		}
+
+		e.last = now
+
+		if err := e.Export(now, data); err != nil {
+			return err
+		}
+	}
+}
+
+// Export writes a single reading to the attribute files and named pipe
+func (e *Exporter) Export(ts time.Time, data vl53l1x.RangingData) error {
+
+	attrs := []struct{ name, value string }{
+		{"in_distance_raw", strconv.Itoa(int(data.RangeMM))},
+		{"in_distance_status", data.RangeStatus.String()},
+		{"in_signal_rate", strconv.FormatFloat(float64(data.PeakSignalCountRateMCPS), 'f', 3, 32)},
+		{"in_ambient_rate", strconv.FormatFloat(float64(data.AmbientCountRateMCPS), 'f', 3, 32)},
+		{"timestamp", strconv.FormatInt(ts.UnixNano(), 10)},
+	}
+
+	for _, a := range attrs {
+		if err := writeAttr(e.cfg.Dir, a.name, a.value); err != nil {
+			return err
+		}
+	}
+
+	if e.cfg.FIFOPath != "" {
+		line := fmt.Sprintf("%d %d %d %.3f %.3f\n", ts.UnixNano(), data.RangeMM,
+			data.RangeStatus, data.PeakSignalCountRateMCPS, data.AmbientCountRateMCPS)
+		writeFIFO(e.cfg.FIFOPath, line)
+	}
+
+	return nil
+}
+
+// writeAttr replaces the attribute file contents atomically so readers never
+// see a partial value
+func writeAttr(dir, name, value string) error {
+
+	path := filepath.Join(dir, name)
+	tmp := path + ".tmp"
+
+	if err := os.WriteFile(tmp, []byte(value+"\n"), 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
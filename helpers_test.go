@@ -0,0 +1,49 @@
+package vl53l1x_test
+
+import (
+	"testing"
+
+	"github.com/swdee/go-vl53l1x"
+	"github.com/swdee/go-vl53l1x/sim"
+)
+
+// newSensor returns an initialized sensor ranging the simulator's default
+// scene in short mode with a 20ms timing budget
+func newSensor(t testing.TB, opts ...vl53l1x.Option) (*vl53l1x.VL53L1X, *sim.Sensor) {
+
+	t.Helper()
+
+	bus := sim.New(vl53l1x.Address)
+	v, err := vl53l1x.New(bus, vl53l1x.Short, 20, opts...)
+
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := v.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	t.Cleanup(func() { v.Close() })
+
+	return v, bus
+}
+
+// readReg reads n bytes of the registers starting at reg directly from the
+// bus, bypassing the driver
+func readReg(t testing.TB, bus vl53l1x.Bus, reg uint16, n int) []byte {
+
+	t.Helper()
+
+	if _, err := bus.WriteBytes([]byte{byte(reg >> 8), byte(reg)}); err != nil {
+		t.Fatalf("write register address 0x%04X: %v", reg, err)
+	}
+
+	buf := make([]byte, n)
+
+	if _, err := bus.ReadBytes(buf); err != nil {
+		t.Fatalf("read register 0x%04X: %v", reg, err)
+	}
+
+	return buf
+}
@@ -0,0 +1,46 @@
+package vl53l1x
+
+import "errors"
+
+// HoldOffAuto derives the number of measurements discarded after a
+// configuration change from the grouped parameter hold behaviour of the
+// sensor.  Settings written while ranging take effect from the next
+// measurement period, so the measurement already in progress uses the old
+// settings and is discarded.  Settings written while stopped apply to the
+// first measurement so none are discarded
+const HoldOffAuto = -1
+
+// ErrHoldOff is returned by a non-blocking Read() when the available
+// measurement was discarded as it was taken with settings from before a
+// configuration change, or is a startup measurement discarded with
+// WithStartupDiscard()
+var ErrHoldOff = errors.New("measurement discarded after configuration change")
+
+// configChanged arms the hold-off and emits a ConfigChangedEvent for the
+// setting
+func (v *VL53L1X) configChanged(setting string) {
+
+	switch {
+	case v.holdOffFrames == HoldOffAuto && v.ranging:
+		v.holdOff = 1
+	case v.holdOffFrames > 0:
+		v.holdOff = v.holdOffFrames
+	}
+
+	v.emitConfigChanged(setting)
+}
+
+// holdingOff reports whether the measurement just read must be discarded and
+// counts it off.  The stream count continuity check restarts after the
+// discarded measurements
+func (v *VL53L1X) holdingOff() bool {
+
+	if v.holdOff <= 0 {
+		return false
+	}
+
+	v.holdOff--
+	v.haveStreamCount = false
+	v.loss.reset()
+	return true
+}
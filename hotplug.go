@@ -0,0 +1,231 @@
+package vl53l1x
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DisconnectedEvent is emitted by a HotplugMonitor when the sensor stops
+// acknowledging transfers on the bus
+type DisconnectedEvent struct {
+	Time time.Time
+	// Err is the last bus error seen
+	Err error
+}
+
+// EventTime returns when the event occurred
+func (e DisconnectedEvent) EventTime() time.Time { return e.Time }
+
+// HotplugConfig configures a HotplugMonitor
+type HotplugConfig struct {
+	// NACKThreshold is the number of consecutive transfers not acknowledged
+	// before the sensor is considered disconnected, defaults to 3
+	NACKThreshold int
+	// Interval between checks made by Run(), defaults to 1 second
+	Interval time.Duration
+}
+
+// HotplugMonitor detects the sensor disappearing from the bus, such as when
+// the cable to it is unplugged, and when it reappears initializes it again
+// and restores its configuration, emitting a DisconnectedEvent and a
+// RecoveryEvent
+type HotplugMonitor struct {
+	v   *VL53L1X
+	cfg HotplugConfig
+
+	// config is restored after reconnecting when no Store is set
+	config Config
+
+	connected bool
+	// ranging and periodMs are the continuous ranging state at disconnect
+	ranging  bool
+	periodMs uint32
+}
+
+// NewHotplugMonitor returns a HotplugMonitor for the connected sensor and
+// takes a snapshot of its configuration to restore on reconnect.  When the
+// sensor has a Store the stored configuration is restored instead
+func NewHotplugMonitor(v *VL53L1X, cfg HotplugConfig) (*HotplugMonitor, error) {
+
+	if cfg.NACKThreshold <= 0 {
+		cfg.NACKThreshold = 3
+	}
+
+	if cfg.Interval == 0 {
+		cfg.Interval = time.Second
+	}
+
+	m := &HotplugMonitor{v: v, cfg: cfg, connected: true}
+
+	if err := m.Snapshot(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Snapshot takes a new snapshot of the sensors configuration to restore on
+// reconnect, call it after changing the distance mode, timing budget or ROI
+func (m *HotplugMonitor) Snapshot() error {
+
+	cfg, err := m.v.GetConfig()
+
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	m.config = cfg
+
+	return nil
+}
+
+// Connected returns false from when the sensor is detected as disconnected
+// until it has been initialized again
+func (m *HotplugMonitor) Connected() bool {
+	return m.connected
+}
+
+// Check detects a disconnected sensor from consecutive NACKs of the transfers
+// made since the last check, reading the model ID so a sensor that is idle is
+// detected too.  When disconnected it probes for a VL53L1X family sensor and
+// initializes it again if it has reappeared.  A sensor that was power cycled
+// returns at the default Address, so when it was moved to another address
+// with SetAddress() that is probed too and the address is assigned again.
+// Applications that range call Check() between their own reads, particularly
+// after a read fails
+func (m *HotplugMonitor) Check(ctx context.Context) error {
+
+	v := m.v
+
+	if m.connected {
+		// an idle sensor makes no transfers of its own, so probe it for the
+		// NACK run to grow when it has gone
+		if v.stats.nackRun.Load() < uint64(m.cfg.NACKThreshold) {
+			v.readReg16Bit(IDENTIFICATION_MODEL_ID)
+		}
+
+		if v.stats.nackRun.Load() < uint64(m.cfg.NACKThreshold) {
+			return nil
+		}
+
+		m.connected = false
+		m.ranging, m.periodMs = v.ranging, v.periodMs
+		v.ranging = false
+
+		v.log.Printf("Sensor 0x%02X disconnected", v.Address())
+		v.emit(DisconnectedEvent{Time: time.Now(), Err: v.LastBusError()})
+
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if !m.present() {
+		if !m.readdress() {
+			// still absent
+			return nil
+		}
+	}
+
+	if err := m.reconnect(); err != nil {
+		return fmt.Errorf("hotplug reconnect: %w", err)
+	}
+
+	m.connected = true
+
+	v.log.Printf("Sensor 0x%02X reconnected", v.Address())
+	v.emit(RecoveryEvent{Time: time.Now(), Reason: "sensor reconnected"})
+
+	return nil
+}
+
+// Run checks the sensor every interval until the context is cancelled,
+// emitting an ErrorEvent when a check fails.  As the sensor must not be used
+// concurrently it is only for sensors that are otherwise idle, applications
+// that range use Check() between their own reads instead
+func (m *HotplugMonitor) Run(ctx context.Context) error {
+
+	tick := time.NewTicker(m.cfg.Interval)
+	defer tick.Stop()
+
+	for {
+		if err := m.Check(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			m.v.emitError("hotplug check", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-tick.C:
+		}
+	}
+}
+
+// present reports whether the sensor answers at its configured address
+func (m *HotplugMonitor) present() bool {
+
+	model, err := m.v.readReg16Bit(IDENTIFICATION_MODEL_ID)
+
+	return err == nil && knownModel(model)
+}
+
+// readdress probes for the sensor at the default Address after a power
+// cycle and assigns it the configured address again, reporting whether it
+// then answers at that address
+func (m *HotplugMonitor) readdress() bool {
+
+	v := m.v
+	addr := v.bus.GetAddr()
+
+	if addr == Address {
+		return false
+	}
+
+	bus, err := v.openBus(Address, v.bus.GetDev())
+
+	if err != nil {
+		return false
+	}
+
+	if ok, _ := isSensor(bus); ok {
+		v.log.Printf("Sensor found at default address 0x%02X, moving to 0x%02X", Address, addr)
+		def := &VL53L1X{bus: bus}
+		def.writeReg(I2C_SLAVE_DEVICE_ADDRESS, addr)
+	}
+
+	bus.Close()
+
+	return m.present()
+}
+
+// reconnect initializes the reappeared sensor, restores its configuration
+// and resumes continuous ranging if it was ranging when disconnected
+func (m *HotplugMonitor) reconnect() error {
+
+	v := m.v
+
+	if err := v.Init(); err != nil {
+		return err
+	}
+
+	if v.store == nil {
+		if err := v.ApplyConfig(m.config); err != nil {
+			return fmt.Errorf("failed to restore config: %w", err)
+		}
+	}
+
+	if m.ranging {
+		if err := v.restartRanging(m.periodMs); err != nil {
+			return fmt.Errorf("failed to resume ranging: %w", err)
+		}
+	}
+
+	return nil
+}
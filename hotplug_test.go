@@ -0,0 +1,214 @@
+package vl53l1x_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+	"github.com/swdee/go-vl53l1x/sim"
+)
+
+// disconnect unplugs the simulated sensor and checks the monitor detects it
+func disconnect(t *testing.T, v *vl53l1x.VL53L1X, bus *sim.Sensor, m *vl53l1x.HotplugMonitor) {
+
+	t.Helper()
+
+	bus.SetConnected(false)
+
+	if _, err := v.Read(true); err == nil {
+		t.Fatal("read of unplugged sensor succeeded")
+	}
+
+	if err := m.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if m.Connected() {
+		t.Fatal("disconnect not detected")
+	}
+}
+
+func TestHotplugReassignsAddress(t *testing.T) {
+
+	bus := sim.New(vl53l1x.Address)
+	v, err := vl53l1x.New(bus, vl53l1x.Short, 20, vl53l1x.WithBusOpener(bus.Open))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer v.Close()
+
+	if _, err := v.SetAddress(0x30); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := vl53l1x.NewHotplugMonitor(v, vl53l1x.HotplugConfig{NACKThreshold: 1})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := v.StartContinuous(25); err != nil {
+		t.Fatal(err)
+	}
+
+	disconnect(t, v, bus, m)
+
+	// the power cycled sensor returns at the default address
+	bus.SetConnected(true)
+
+	if err := m.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if !m.Connected() {
+		t.Fatal("sensor at default address not reconnected")
+	}
+
+	if got := v.Address(); got != 0x30 {
+		t.Errorf("address = 0x%02X, want 0x30", got)
+	}
+
+	if _, err := v.Read(true); err != nil {
+		t.Fatalf("read after reconnect: %v", err)
+	}
+}
+
+// failingStore is a Store whose config can no longer be loaded once failed
+// is set
+type failingStore struct {
+	*vl53l1x.MemoryStore
+	failed atomic.Bool
+}
+
+func (s *failingStore) LoadConfig() (vl53l1x.Config, error) {
+
+	if s.failed.Load() {
+		return vl53l1x.Config{}, errors.New("store unavailable")
+	}
+
+	return s.MemoryStore.LoadConfig()
+}
+
+func TestHotplugRunEmitsCheckErrors(t *testing.T) {
+
+	store := &failingStore{MemoryStore: vl53l1x.NewMemoryStore()}
+	v, bus := newSensor(t, vl53l1x.WithStore(store))
+	events := v.Events()
+
+	m, err := vl53l1x.NewHotplugMonitor(v, vl53l1x.HotplugConfig{
+		NACKThreshold: 1,
+		Interval:      5 * time.Millisecond,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := v.StartContinuous(25); err != nil {
+		t.Fatal(err)
+	}
+
+	disconnect(t, v, bus, m)
+
+	// initializing the sensor again fails as the store can't be read
+	store.failed.Store(true)
+	bus.SetConnected(true)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx) }()
+
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	for {
+		select {
+		case e := <-events:
+			if ee, ok := e.(vl53l1x.ErrorEvent); ok && ee.Op == "hotplug check" {
+				return
+			}
+		case <-ctx.Done():
+			t.Fatal("no ErrorEvent emitted for failed check")
+		}
+	}
+}
+
+func TestHotplugRunDetectsIdleDisconnect(t *testing.T) {
+
+	v, bus := newSensor(t)
+	events := v.Events()
+
+	m, err := vl53l1x.NewHotplugMonitor(v, vl53l1x.HotplugConfig{Interval: 5 * time.Millisecond})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx) }()
+
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// the sensor is not ranging so only the monitor uses the bus
+	bus.SetConnected(false)
+
+	for {
+		select {
+		case e := <-events:
+			if _, ok := e.(vl53l1x.DisconnectedEvent); ok {
+				return
+			}
+		case <-ctx.Done():
+			t.Fatal("no DisconnectedEvent for idle sensor")
+		}
+	}
+}
+
+func TestHotplugReconnectsFamilyModel(t *testing.T) {
+
+	v, bus := newSensor(t)
+
+	m, err := vl53l1x.NewHotplugMonitor(v, vl53l1x.HotplugConfig{NACKThreshold: 1})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := v.StartContinuous(25); err != nil {
+		t.Fatal(err)
+	}
+
+	disconnect(t, v, bus, m)
+	bus.SetConnected(true)
+
+	// a VL53L4CD reports its own model ID
+	reg := vl53l1x.IDENTIFICATION_MODEL_ID
+	model := []byte{byte(reg >> 8), byte(reg), 0xEB, 0xAA}
+
+	if _, err := bus.WriteBytes(model); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if !m.Connected() {
+		t.Fatal("VL53L1X family sensor not reconnected")
+	}
+}
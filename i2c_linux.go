@@ -0,0 +1,13 @@
+//go:build linux && !baremetal
+
+package vl53l1x
+
+import "github.com/swdee/go-i2c"
+
+// openI2C opens a go-i2c connection to the device at the given address
+func openI2C(addr uint8, dev string) (Bus, error) {
+	return i2c.New(addr, dev)
+}
+
+// haveI2C reports whether openI2C can open I2C device paths
+const haveI2C = true
@@ -0,0 +1,12 @@
+//go:build !linux || baremetal
+
+package vl53l1x
+
+// openI2C is not supported as the go-i2c transport requires Linux i2c-dev,
+// a Bus such as the simulator must be passed to New() instead
+func openI2C(addr uint8, dev string) (Bus, error) {
+	return nil, ErrI2CUnsupported
+}
+
+// haveI2C reports whether openI2C can open I2C device paths
+const haveI2C = false
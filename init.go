@@ -1,6 +1,7 @@
 package vl53l1x
 
 import (
+	"context"
 	"fmt"
 	"time"
 )
@@ -9,20 +10,54 @@ import (
 // VL53L1X_StaticInit()
 func (v *VL53L1X) Init() error {
 
+	end := v.traceOp(SpanInit)
+	err := v.initSensor(v.uldInit)
+	end(err)
+
+	return err
+}
+
+// initSensor performs the initialization sequence of Init(), writing the
+// static configuration as ST's default configuration block when uldBlock is
+// set
+func (v *VL53L1X) initSensor(uldBlock bool) error {
+
 	v.SetTimeout(time.Millisecond * 500)
 
+	var stored *Config
+
+	if v.store != nil {
+		cfg, err := v.loadStoredConfig()
+
+		if err != nil {
+			return fmt.Errorf("Error loading stored config, %w", err)
+		}
+
+		stored = cfg
+	}
+
 	err := v.dataInit()
 
 	if err != nil {
 		return fmt.Errorf("Error on dataInit(), %w", err)
 	}
 
-	err = v.staticInit()
+	if uldBlock {
+		err = v.staticInitULD()
+	} else {
+		err = v.staticInit()
+	}
 
 	if err != nil {
 		return fmt.Errorf("Error on staticInit(), %w", err)
 	}
 
+	if v.store != nil {
+		if err := v.applyStore(stored); err != nil {
+			return fmt.Errorf("Error applying store, %w", err)
+		}
+	}
+
 	return v.warmSensor()
 }
 
@@ -35,7 +70,7 @@ func (v *VL53L1X) warmSensor() error {
 		return fmt.Errorf("Start continuous failed: %v", err)
 	}
 
-	_, err := v.Read(true)
+	_, err := v.readEvent(context.Background(), true, true)
 
 	if err != nil {
 		return fmt.Errorf("dummy read failed: %w", err)
@@ -58,7 +93,7 @@ func (v *VL53L1X) dataInit() error {
 		return err
 	}
 
-	if model != 0xEACC {
+	if !knownModel(model) {
 		return fmt.Errorf("unexpected model ID: 0x%X", model)
 	}
 
@@ -73,30 +108,28 @@ func (v *VL53L1X) dataInit() error {
 		return err
 	}
 
+	// the reset returns the interrupt to active low on every measurement
+	v.intActiveHigh = false
+	v.eventDetect = false
+
 	// give it some time to boot; otherwise the sensor NACKs during the readReg()
 	// call below
 	time.Sleep(1 * time.Millisecond)
 
 	// VL53L1_poll_for_boot_completion()
-	v.startTimeout()
+	err = v.waitFor(context.Background(), "boot completion", func() (bool, error) {
 
-	for {
 		sysStatus, err := v.readReg(FIRMWARE_SYSTEM_STATUS)
 
 		if err != nil {
-			return err
+			return false, err
 		}
 
-		if (sysStatus&0x01) != 0 && v.lastStatus == 0 {
-			break
-		}
+		return (sysStatus & 0x01) != 0, nil
+	})
 
-		if v.checkTimeoutExpired() {
-			v.didTimeout = true
-			return fmt.Errorf("timeout waiting for boot completion")
-		}
-
-		time.Sleep(1 * time.Millisecond)
+	if err != nil {
+		return err
 	}
 
 	// sensor uses 1V8 mode for I/O by default; switch to 2V8 mode
@@ -130,114 +163,85 @@ func (v *VL53L1X) dataInit() error {
 	return nil
 }
 
-// staticInit implements VL53L1X_StaticInit() begin from C++ API code
-func (v *VL53L1X) staticInit() error {
+// regValue is a register value written during initialization
+type regValue struct {
+	reg   uint16
+	size  int
+	value uint32
+}
+
+// staticConfig returns the configuration settings written by staticInit()
+func (v *VL53L1X) staticConfig() []regValue {
 
 	// Note that the API does not actually apply the configuration settings below
 	// when VL53L1_StaticInit() is called: it keeps a copy of the sensor's
 	// register contents in memory and doesn't actually write them until a
 	// measurement is started. Writing the configuration here means we don't have
 	// to keep it all in memory and avoids a lot of redundant writes later.
-
-	// Static initialization (configuration settings).
-	if err := v.writeReg16Bit(DSS_CONFIG_TARGET_TOTAL_RATE_MCPS, TargetRate); err != nil {
-		return err
-	}
-
-	if err := v.writeReg(GPIO_TIO_HV_STATUS, 0x02); err != nil {
-		return err
-	}
-
-	if err := v.writeReg(SIGMA_EST_EFFECTIVE_PULSE_WIDTH_NS, 8); err != nil {
-		return err
-	}
-
-	if err := v.writeReg(SIGMA_EST_EFFECTIVE_AMBIENT_WIDTH_NS, 16); err != nil {
-		return err
-	}
-
-	if err := v.writeReg(ALGO_CROSSTALK_COMP_VALID_HEIGHT_MM, 0x01); err != nil {
-		return err
-	}
-
-	if err := v.writeReg(ALGO_RANGE_IGNORE_VALID_HEIGHT_MM, 0xFF); err != nil {
-		return err
-	}
-
-	if err := v.writeReg(ALGO_RANGE_MIN_CLIP, 0); err != nil {
-		return err
-	}
-
-	if err := v.writeReg(ALGO_CONSISTENCY_CHECK_TOLERANCE, 2); err != nil {
-		return err
-	}
-
-	//  general config
-	if err := v.writeReg16Bit(SYSTEM_THRESH_RATE_HIGH, 0x0000); err != nil {
-		return err
-	}
-
-	if err := v.writeReg16Bit(SYSTEM_THRESH_RATE_LOW, 0x0000); err != nil {
-		return err
-	}
-
-	if err := v.writeReg(DSS_CONFIG_APERTURE_ATTENUATION, 0x38); err != nil {
-		return err
-	}
-
-	// timing config
-	// most of these settings will be determined later by distance and timing
-	// budget configuration
-	if err := v.writeReg16Bit(RANGE_CONFIG_SIGMA_THRESH, 360); err != nil {
-		return err
-	}
-
-	if err := v.writeReg16Bit(RANGE_CONFIG_MIN_COUNT_RATE_RTN_LIMIT_MCPS, 192); err != nil {
-		return err
-	}
-
-	// dynamic config
-	if err := v.writeReg(SYSTEM_GROUPED_PARAMETER_HOLD_0, 0x01); err != nil {
-		return err
-	}
-
-	if err := v.writeReg(SYSTEM_GROUPED_PARAMETER_HOLD_1, 0x01); err != nil {
-		return err
-	}
-
-	if err := v.writeReg(SD_CONFIG_QUANTIFIER, 2); err != nil {
-		return err
+	return []regValue{
+		// Static initialization (configuration settings).
+		{DSS_CONFIG_TARGET_TOTAL_RATE_MCPS, 2, uint32(v.targetRate)},
+		{GPIO_TIO_HV_STATUS, 1, 0x02},
+		{SIGMA_EST_EFFECTIVE_PULSE_WIDTH_NS, 1, 8},
+		{SIGMA_EST_EFFECTIVE_AMBIENT_WIDTH_NS, 1, 16},
+		{ALGO_CROSSTALK_COMP_VALID_HEIGHT_MM, 1, 0x01},
+		{ALGO_RANGE_IGNORE_VALID_HEIGHT_MM, 1, 0xFF},
+		{ALGO_RANGE_MIN_CLIP, 1, 0},
+		{ALGO_CONSISTENCY_CHECK_TOLERANCE, 1, uint32(v.consistencyTol)},
+
+		// general config
+		{SYSTEM_THRESH_RATE_HIGH, 2, 0x0000},
+		{SYSTEM_THRESH_RATE_LOW, 2, 0x0000},
+		{DSS_CONFIG_APERTURE_ATTENUATION, 1, uint32(DefaultApertureAttenuation)},
+
+		// timing config
+		// most of these settings will be determined later by distance and timing
+		// budget configuration
+		{RANGE_CONFIG_SIGMA_THRESH, 2, 360},
+		{RANGE_CONFIG_MIN_COUNT_RATE_RTN_LIMIT_MCPS, 2, 192},
+
+		// dynamic config
+		{SYSTEM_GROUPED_PARAMETER_HOLD_0, 1, 0x01},
+		{SYSTEM_GROUPED_PARAMETER_HOLD_1, 1, 0x01},
+		{SD_CONFIG_QUANTIFIER, 1, 2},
+
+		// from VL53L1_preset_mode_timed_ranging_*
+		{SYSTEM_GROUPED_PARAMETER_HOLD, 1, 0x00},
+		{SYSTEM_SEED_CONFIG, 1, 1},
+
+		// from VL53L1_config_low_power_auto_mode
+		{SYSTEM_SEQUENCE_CONFIG, 1, 0x8B},
+		// manual effective spads (200 << 8)
+		{DSS_CONFIG_MANUAL_EFFECTIVE_SPADS_SELECT, 2, 200 << 8},
+		{DSS_CONFIG_ROI_MODE_CONTROL, 1, uint32(DSSRequestedEffectiveSPADs)},
 	}
+}
 
-	// from VL53L1_preset_mode_timed_ranging_*
-	if err := v.writeReg(SYSTEM_GROUPED_PARAMETER_HOLD, 0x00); err != nil {
-		return err
-	}
+// staticInit implements VL53L1X_StaticInit() begin from C++ API code
+func (v *VL53L1X) staticInit() error {
 
-	if err := v.writeReg(SYSTEM_SEED_CONFIG, 1); err != nil {
-		return err
+	for _, rv := range v.staticConfig() {
+		if err := v.writeRegSized(rv.reg, rv.size, rv.value); err != nil {
+			return err
+		}
 	}
 
-	// from VL53L1_config_low_power_auto_mode
-	if err := v.writeReg(SYSTEM_SEQUENCE_CONFIG, 0x8B); err != nil {
-		return err
-	}
+	return v.finishStaticInit()
+}
 
-	// Write manual effective spads (200 << 8)
-	if err := v.writeReg16Bit(DSS_CONFIG_MANUAL_EFFECTIVE_SPADS_SELECT, 200<<8); err != nil {
-		return err
-	}
+// finishStaticInit applies the distance mode, timing budget and part-to-part
+// offset after the static configuration is written
+func (v *VL53L1X) finishStaticInit() error {
 
-	if err := v.writeReg(DSS_CONFIG_ROI_MODE_CONTROL, 2); err != nil {
-		return err
-	}
+	// apply the requested mode and budget together as the budget on the device
+	// after reset need not be legal for the mode
+	budget, err := v.checkModeBudget(v.distanceMode, v.timingBudget)
 
-	// Default to range with a 50 ms timing budget.
-	if err := v.SetDistanceMode(v.distanceMode); err != nil {
+	if err != nil {
 		return err
 	}
 
-	if err := v.SetMeasurementTimingBudget(v.timingBudget); err != nil {
+	if err := v.setDistanceMode(v.distanceMode, budget); err != nil {
 		return err
 	}
 
@@ -0,0 +1,82 @@
+package vl53l1x
+
+import (
+	"fmt"
+
+	"github.com/swdee/go-vl53l1x/uld"
+)
+
+// uldConfigStart is the first register of ST's default configuration block
+const uldConfigStart uint16 = 0x2D
+
+// uldConfigSize is the 91 bytes of the block, from 0x2D to SYSTEM_MODE_START
+// at 0x87 inclusive
+const uldConfigSize = int(SYSTEM_MODE_START-uldConfigStart) + 1
+
+// the block must end at SYSTEM_MODE_START, which it leaves at 0
+var _ [uldConfigSize]byte = uld.DefaultConfiguration
+
+// InitULD initializes the sensor as Init() does, but writes the static
+// configuration as ST's default configuration block of registers 0x2D to
+// 0x87 from VL53L1X_SensorInit() of the ULD, with the driver's settings and
+// the 2V8 I/O mode selected by dataInit() applied over it.  The block is
+// written in a single transaction, split when the bus has a maximum transfer
+// size, so every register in it has ST's validated value rather than its
+// reset value.  Use WithULDInit() to have New() and reconnects initialize
+// this way
+func (v *VL53L1X) InitULD() error {
+
+	end := v.traceOp(SpanInit)
+	err := v.initSensor(true)
+	end(err)
+
+	return err
+}
+
+// staticInitULD writes the static configuration as ST's default
+// configuration block
+func (v *VL53L1X) staticInitULD() error {
+
+	block := uld.DefaultConfiguration
+
+	// settings outside of the block are written after it
+	var rest []regValue
+
+	for _, rv := range v.staticConfig() {
+		off := int(rv.reg) - int(uldConfigStart)
+
+		if off < 0 || off+rv.size > len(block) {
+			rest = append(rest, rv)
+			continue
+		}
+
+		for i := rv.size - 1; i >= 0; i-- {
+			block[off+i] = byte(rv.value >> (8 * (rv.size - 1 - i)))
+		}
+	}
+
+	// the block selects 1V8 I/O at PAD_I2C_HV_EXTSUP_CONFIG, keep the 2V8
+	// mode dataInit() switched to
+	pad, err := v.readReg(PAD_I2C_HV_EXTSUP_CONFIG)
+
+	if err != nil {
+		return err
+	}
+
+	block[PAD_I2C_HV_EXTSUP_CONFIG-uldConfigStart] = pad
+
+	if err := v.writeRegBytes(uldConfigStart, block[:]); err != nil {
+		return fmt.Errorf("failed to write configuration block: %w", err)
+	}
+
+	// the block selects the interrupt polarity
+	v.intActiveHigh = block[GPIO_HV_MUX_CTRL-uldConfigStart]&gpioActiveLow == 0
+
+	for _, rv := range rest {
+		if err := v.writeRegSized(rv.reg, rv.size, rv.value); err != nil {
+			return err
+		}
+	}
+
+	return v.finishStaticInit()
+}
@@ -0,0 +1,16 @@
+package vl53l1x_test
+
+import (
+	"testing"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+func TestInitULDKeeps2V8(t *testing.T) {
+
+	_, bus := newSensor(t, vl53l1x.WithULDInit())
+
+	if pad := readReg(t, bus, vl53l1x.PAD_I2C_HV_EXTSUP_CONFIG, 1)[0]; pad&0x01 == 0 {
+		t.Errorf("PAD_I2C_HV_EXTSUP_CONFIG = 0x%02X, 2V8 I/O mode cleared by the configuration block", pad)
+	}
+}
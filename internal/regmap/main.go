@@ -0,0 +1,158 @@
+// Command regmap generates the register constants and register map table of
+// the vl53l1x package from registers.csv.  It is run by go generate in the
+// package directory.
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	input  = "registers.csv"
+	output = "register_map.go"
+)
+
+// register is a row of the register map
+type register struct {
+	group   string
+	name    string
+	stName  string
+	address uint64
+	width   int
+	access  string
+}
+
+// accessConsts maps the access column to the constants of the package
+var accessConsts = map[string]string{
+	"rw": "AccessReadWrite",
+	"ro": "AccessReadOnly",
+	"wo": "AccessWriteOnly",
+}
+
+func main() {
+
+	regs, err := load(input)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "// Code generated by go run ./internal/regmap; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package vl53l1x\n\n")
+	fmt.Fprintf(&buf, "// Register addresses, grouped as in ST's register map\n")
+	fmt.Fprintf(&buf, "const (\n")
+
+	group := ""
+
+	for _, r := range regs {
+		if r.group != group {
+			if group != "" {
+				fmt.Fprintf(&buf, "\n")
+			}
+
+			fmt.Fprintf(&buf, "\t// %s\n", r.group)
+			group = r.group
+		}
+
+		fmt.Fprintf(&buf, "\t%s uint16 = 0x%04X\n", r.name, r.address)
+	}
+
+	fmt.Fprintf(&buf, ")\n\n")
+	fmt.Fprintf(&buf, "// registerMap describes the registers declared above ordered by address\n")
+	fmt.Fprintf(&buf, "var registerMap = []RegisterInfo{\n")
+
+	for _, r := range regs {
+		fmt.Fprintf(&buf, "\t{%s, %q, %q, %d, %s},\n",
+			r.name, r.name, r.stName, r.width, accessConsts[r.access])
+	}
+
+	fmt.Fprintf(&buf, "}\n")
+
+	src, err := format.Source(buf.Bytes())
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// load reads and validates the register map, skipping comment lines and
+// the header
+func load(path string) ([]register, error) {
+
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+
+	rows, err := csv.NewReader(strings.NewReader(strings.Join(lines, "\n"))).ReadAll()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var regs []register
+	var last uint64
+
+	for i, row := range rows[1:] {
+		line := i + 2
+
+		if len(row) != 6 {
+			return nil, fmt.Errorf("row %d: want 6 columns, got %d", line, len(row))
+		}
+
+		addr, err := strconv.ParseUint(row[3], 0, 16)
+
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid address %q", line, row[3])
+		}
+
+		if i > 0 && addr <= last {
+			return nil, fmt.Errorf("row %d: address 0x%04X not in ascending order", line, addr)
+		}
+
+		last = addr
+
+		width, err := strconv.Atoi(row[4])
+
+		if err != nil || (width != 1 && width != 2 && width != 4) {
+			return nil, fmt.Errorf("row %d: invalid width %q", line, row[4])
+		}
+
+		if _, ok := accessConsts[row[5]]; !ok {
+			return nil, fmt.Errorf("row %d: invalid access %q", line, row[5])
+		}
+
+		regs = append(regs, register{
+			group:   row[0],
+			name:    row[1],
+			stName:  row[2],
+			address: addr,
+			width:   width,
+			access:  row[5],
+		})
+	}
+
+	return regs, nil
+}
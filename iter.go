@@ -0,0 +1,38 @@
+//go:build go1.23
+
+package vl53l1x
+
+import (
+	"context"
+	"errors"
+	"iter"
+)
+
+// Measurements returns an iterator over continuous ranging measurements read
+// with ReadContext(), for use with range over func
+//
+//	for data, err := range sensor.Measurements(ctx) {
+//		if errors.Is(err, vl53l1x.ErrTimeout) {
+//			continue
+//		}
+//		if err != nil {
+//			return err
+//		}
+//		// ...
+//	}
+//
+// Errors are yielded with the measurement so the loop can decide whether to
+// continue past a transient error such as a timeout or stop, and iteration
+// stops after yielding the error of a done context or ErrNotRanging.
+// Ranging must have been started with StartContinuous()
+func (v *VL53L1X) Measurements(ctx context.Context) iter.Seq2[RangingData, error] {
+	return func(yield func(RangingData, error) bool) {
+		for {
+			rData, err := v.ReadContext(ctx)
+
+			if !yield(rData, err) || ctx.Err() != nil || errors.Is(err, ErrNotRanging) {
+				return
+			}
+		}
+	}
+}
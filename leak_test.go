@@ -0,0 +1,145 @@
+package vl53l1x_test
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+	"github.com/swdee/go-vl53l1x/sim"
+)
+
+// goroutines returns the stacks of the running goroutines keyed by their
+// "goroutine N" header
+func goroutines() map[string]string {
+
+	buf := make([]byte, 1<<20)
+	buf = buf[:runtime.Stack(buf, true)]
+
+	stacks := map[string]string{}
+
+	for _, g := range bytes.Split(buf, []byte("\n\n")) {
+		id, _, _ := bytes.Cut(g, []byte(" ["))
+		stacks[string(id)] = string(g)
+	}
+
+	return stacks
+}
+
+// verifyNoLeaks fails the test if a goroutine started during it is still
+// running once its cleanups, including closing the sensors, have run.  It
+// must be called before the sensors are created so it is checked last
+func verifyNoLeaks(t *testing.T) {
+
+	before := goroutines()
+
+	t.Cleanup(func() {
+
+		var leaked []string
+
+		// allow goroutines that are exiting time to finish
+		for deadline := time.Now().Add(time.Second); ; {
+			leaked = leaked[:0]
+
+			for id, stack := range goroutines() {
+				if _, ok := before[id]; !ok && !strings.Contains(stack, "testing.tRunner") {
+					leaked = append(leaked, stack)
+				}
+			}
+
+			if len(leaked) == 0 || time.Now().After(deadline) {
+				break
+			}
+
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		for _, stack := range leaked {
+			t.Errorf("leaked goroutine:\n%s", stack)
+		}
+	})
+}
+
+func TestNoLeakContinuous(t *testing.T) {
+
+	verifyNoLeaks(t)
+	v, _ := newSensor(t)
+	events := v.Events()
+
+	if err := v.StartContinuous(25); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	n := 0
+
+	v.Measurements(ctx)(func(_ vl53l1x.RangingData, err error) bool {
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		n++
+		return n < 3
+	})
+
+	if err := v.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the events channel is closed by Close()
+	for range events {
+	}
+}
+
+func TestNoLeakHotplugRun(t *testing.T) {
+
+	verifyNoLeaks(t)
+	v, _ := newSensor(t)
+
+	m, err := vl53l1x.NewHotplugMonitor(v, vl53l1x.HotplugConfig{Interval: 10 * time.Millisecond})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := m.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNoLeakCoordinator(t *testing.T) {
+
+	verifyNoLeaks(t)
+	a, _ := newSensor(t)
+
+	b, err := vl53l1x.New(sim.New(0x30), vl53l1x.Short, 20)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { b.Close() })
+
+	c, err := vl53l1x.NewCoordinator([]*vl53l1x.VL53L1X{a, b})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// cancel part way through a cycle so the sensors are waited on
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Millisecond)
+	defer cancel()
+
+	if err := c.Run(ctx, func(vl53l1x.SyncResult) {}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,63 @@
+package vl53l1x
+
+import "math"
+
+const (
+	// maxRangeRefBudget is the timing budget in milliseconds the reference
+	// ranges in maxRangeTable were specified at
+	maxRangeRefBudget = 100
+	// maxRangeStrongAmbientMCPS is the ambient count rate taken as strong
+	// ambient light for the reference ranges
+	maxRangeStrongAmbientMCPS = 10
+	// maxRangeLimitMM is the furthest distance the sensor can range
+	maxRangeLimitMM = 4000
+)
+
+// maxRangeTable holds the typical maximum range in millimeters per distance
+// mode in the dark and under strong ambient light from the datasheet
+var maxRangeTable = map[DistanceMode]struct{ dark, ambient float64 }{
+	Short:  {1360, 1350},
+	Medium: {2900, 760},
+	Long:   {3600, 730},
+}
+
+// EstimateMaxRange returns an estimate of the maximum reliable range in
+// millimeters for a white target given the distance mode, timing budget in
+// milliseconds and ambient count rate in MCPS.  The estimate interpolates the
+// datasheet figures between dark and strong ambient conditions and scales
+// with the fourth root of the timing budget as range grows with the square
+// root of signal to noise ratio
+func EstimateMaxRange(mode DistanceMode, budget uint32, ambientMCPS float32) uint16 {
+
+	ref, ok := maxRangeTable[mode]
+
+	if !ok || budget == 0 {
+		return 0
+	}
+
+	ambient := float64(ambientMCPS) / maxRangeStrongAmbientMCPS
+
+	if ambient > 1 {
+		ambient = 1
+	} else if ambient < 0 {
+		ambient = 0
+	}
+
+	rangeMM := ref.dark - (ref.dark-ref.ambient)*ambient
+	rangeMM *= math.Pow(float64(budget)/maxRangeRefBudget, 0.25)
+
+	if rangeMM > maxRangeLimitMM {
+		rangeMM = maxRangeLimitMM
+	}
+
+	return uint16(rangeMM)
+}
+
+// PredictMaxRange returns an estimate of the maximum reliable range in
+// millimeters achievable right now using the ambient rate of the last
+// measurement and the current distance mode and timing budget.  Before any
+// measurement is taken the estimate assumes dark conditions
+func (v *VL53L1X) PredictMaxRange() uint16 {
+	ambient := v.countRateFixedToFloat(v.results.ambientCountRateMCPS_SD0)
+	return EstimateMaxRange(v.distanceMode, v.timingBudget, ambient)
+}
@@ -0,0 +1,127 @@
+package vl53l1x
+
+import "fmt"
+
+const (
+	// minTimingBudgetShort is the minimum timing budget in milliseconds
+	// supported in Short distance mode
+	minTimingBudgetShort uint32 = 20
+	// minTimingBudget is the minimum timing budget in milliseconds that works
+	// for all distance modes
+	minTimingBudget uint32 = 33
+	// maxTimingBudget is the largest timing budget in milliseconds whose range
+	// timeout fits the sensors 1.1s limit
+	maxTimingBudget uint32 = (2*1100000 + TimingGuard) / 1000
+)
+
+// ModeBudgetError is returned when a timing budget is not legal for the
+// distance mode it is used with
+type ModeBudgetError struct {
+	Mode      DistanceMode
+	Budget    uint32
+	MinBudget uint32
+	MaxBudget uint32
+}
+
+// Error implements the error interface
+func (e *ModeBudgetError) Error() string {
+
+	if e.Budget > e.MaxBudget {
+		return fmt.Sprintf("timing budget %dms exceeds maximum of %dms",
+			e.Budget, e.MaxBudget)
+	}
+
+	return fmt.Sprintf("timing budget %dms is below minimum of %dms for distance mode %s",
+		e.Budget, e.MinBudget, e.Mode)
+}
+
+// Capability describes the range of timing budgets in milliseconds that are
+// legal for a distance mode
+type Capability struct {
+	Mode      DistanceMode
+	MinBudget uint32
+	MaxBudget uint32
+}
+
+// Capabilities returns the legal timing budget range for each of the built
+// in distance modes
+func Capabilities() []Capability {
+
+	caps := make([]Capability, 0, len(defaultProfiles))
+
+	for _, mode := range []DistanceMode{Short, Medium, Long} {
+		caps = append(caps, profileCapability(mode, defaultProfiles[mode]))
+	}
+
+	return caps
+}
+
+// profileCapability returns the Capability of a distance mode using profile p
+func profileCapability(mode DistanceMode, p Profile) Capability {
+	return Capability{Mode: mode, MinBudget: p.minBudget(), MaxBudget: maxTimingBudget}
+}
+
+// checkModeBudget validates the timing budget against the distance mode.  When
+// auto adjust is enabled an illegal budget is clamped to the nearest legal
+// value and a warning logged, otherwise a *ModeBudgetError is returned
+func (v *VL53L1X) checkModeBudget(mode DistanceMode, budget uint32) (uint32, error) {
+
+	p, ok := v.modeProfile(mode)
+
+	if !ok {
+		return budget, fmt.Errorf("unrecognized distance mode")
+	}
+
+	c := profileCapability(mode, p)
+
+	if budget >= c.MinBudget && budget <= c.MaxBudget {
+		return budget, nil
+	}
+
+	if !v.autoAdjustBudget {
+		return budget, &ModeBudgetError{
+			Mode:      mode,
+			Budget:    budget,
+			MinBudget: c.MinBudget,
+			MaxBudget: c.MaxBudget,
+		}
+	}
+
+	adjusted := max(budget, c.MinBudget)
+	adjusted = min(adjusted, c.MaxBudget)
+
+	if v.log != nil {
+		v.log.Printf("Timing budget %dms not legal for distance mode %s, using %dms",
+			budget, mode, adjusted)
+	}
+
+	return adjusted, nil
+}
+
+// MinBudgetForMode returns the shortest legal timing budget in milliseconds
+// for the distance mode, taking any profile set with SetModeProfile() into
+// account
+func (v *VL53L1X) MinBudgetForMode(mode DistanceMode) (uint32, error) {
+
+	p, ok := v.modeProfile(mode)
+
+	if !ok {
+		return 0, fmt.Errorf("unrecognized distance mode")
+	}
+
+	return p.minBudget(), nil
+}
+
+// EffectiveMaxRate returns the fastest measurement rate in Hz of continuous
+// ranging in the distance mode, using its shortest legal timing budget and
+// the recommended 5ms margin between the budget and inter-measurement period
+func (v *VL53L1X) EffectiveMaxRate(mode DistanceMode) (float64, error) {
+
+	budget, err := v.MinBudgetForMode(mode)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return 1000 / float64(budget+rateGuardMs), nil
+}
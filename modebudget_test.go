@@ -0,0 +1,58 @@
+package vl53l1x_test
+
+import (
+	"testing"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+func TestTimingBudgetReadBack(t *testing.T) {
+
+	v, _ := newSensor(t)
+
+	for _, capability := range vl53l1x.Capabilities() {
+		mode := capability.Mode
+
+		// a budget legal in every mode so the mode can be changed
+		if err := v.SetMeasurementTimingBudget(50); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := v.SetDistanceMode(mode); err != nil {
+			t.Fatal(err)
+		}
+
+		var prev uint32
+
+		for budget := capability.MinBudget; budget <= capability.MaxBudget; budget++ {
+			if err := v.SetMeasurementTimingBudget(budget); err != nil {
+				t.Fatalf("%s %dms: %v", mode, budget, err)
+			}
+
+			got, err := v.GetMeasurementTimingBudget()
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// the timeout registers keep 8 significant bits, rounding the
+			// budget down by less than 1 part in 128 before the read back
+			// is truncated to whole milliseconds
+			if got > budget || budget-got > budget/128+1 {
+				t.Errorf("%s: set %dms, read back %dms", mode, budget, got)
+			}
+
+			// small budgets read back exactly so GetConfig() round trips
+			if budget <= 90 && got != budget {
+				t.Errorf("%s: set %dms, read back %dms", mode, budget, got)
+			}
+
+			if got < prev {
+				t.Errorf("%s: %dms read back %dms, below %dms read back for %dms",
+					mode, budget, got, prev, budget-1)
+			}
+
+			prev = got
+		}
+	}
+}
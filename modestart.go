@@ -0,0 +1,40 @@
+package vl53l1x
+
+import "fmt"
+
+// ModeStart is a value written to SYSTEM_MODE_START to start or stop ranging
+type ModeStart uint8
+
+const (
+	// ModeStartSingleShot takes one measurement
+	ModeStartSingleShot ModeStart = 0x10
+	// ModeStartBackToBack takes measurements continuously, starting each as
+	// soon as the previous completes
+	ModeStartBackToBack ModeStart = 0x20
+	// ModeStartTimed takes measurements continuously, started by the
+	// inter-measurement period timer
+	ModeStartTimed ModeStart = 0x40
+	// ModeStartAbort stops ranging
+	ModeStartAbort ModeStart = 0x80
+)
+
+// String implement Stringer interface for ModeStart
+func (m ModeStart) String() string {
+	switch m {
+	case ModeStartSingleShot:
+		return "single shot"
+	case ModeStartBackToBack:
+		return "back to back"
+	case ModeStartTimed:
+		return "timed"
+	case ModeStartAbort:
+		return "abort"
+	default:
+		return fmt.Sprintf("mode start 0x%02X", uint8(m))
+	}
+}
+
+// writeModeStart writes the mode to SYSTEM_MODE_START
+func (v *VL53L1X) writeModeStart(m ModeStart) error {
+	return v.writeReg(SYSTEM_MODE_START, uint8(m))
+}
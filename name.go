@@ -0,0 +1,89 @@
+package vl53l1x
+
+import (
+	"log"
+	"maps"
+	"sync"
+)
+
+// meta is the name and labels identifying a sensor in multi-sensor
+// deployments
+type meta struct {
+	mu     sync.Mutex
+	name   string
+	labels map[string]string
+	// baseLog is the logger set by the caller, which v.log prefixes with the
+	// name
+	baseLog *log.Logger
+}
+
+// Name returns the name set with WithName() or SetName(), empty if the
+// sensor is unnamed
+func (v *VL53L1X) Name() string {
+
+	v.meta.mu.Lock()
+	defer v.meta.mu.Unlock()
+
+	return v.meta.name
+}
+
+// SetName names the sensor, eg: "front-left".  The name is attached to the
+// MeasurementEvent and ThresholdEvent emitted, DebugState() and
+// sink.SensorTags(), and prefixes the driver's log lines.  As it changes the
+// logger call it from the goroutine reading the sensor
+func (v *VL53L1X) SetName(name string) {
+
+	v.meta.mu.Lock()
+	defer v.meta.mu.Unlock()
+
+	v.meta.name = name
+	v.applyName()
+}
+
+// Labels returns a copy of the labels set with WithLabels() or SetLabels()
+func (v *VL53L1X) Labels() map[string]string {
+
+	v.meta.mu.Lock()
+	defer v.meta.mu.Unlock()
+
+	return maps.Clone(v.meta.labels)
+}
+
+// SetLabels replaces the labels describing the sensor, such as its location,
+// which are attached wherever the name is.  The map is copied
+func (v *VL53L1X) SetLabels(labels map[string]string) {
+
+	v.meta.mu.Lock()
+	defer v.meta.mu.Unlock()
+
+	// events share the map so it is replaced rather than modified
+	v.meta.labels = maps.Clone(labels)
+}
+
+// identity returns the name and labels to attach to an event, the labels
+// must not be modified
+func (v *VL53L1X) identity() (string, map[string]string) {
+
+	v.meta.mu.Lock()
+	defer v.meta.mu.Unlock()
+
+	return v.meta.name, v.meta.labels
+}
+
+// applyName prefixes the log lines of the logger set by the caller with the
+// name, the caller must hold v.meta.mu
+func (v *VL53L1X) applyName() {
+
+	if v.meta.baseLog == nil {
+		v.meta.baseLog = v.log
+	}
+
+	base := v.meta.baseLog
+
+	if v.meta.name == "" {
+		v.log = base
+		return
+	}
+
+	v.log = log.New(base.Writer(), base.Prefix()+v.meta.name+": ", base.Flags())
+}
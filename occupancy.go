@@ -0,0 +1,197 @@
+package vl53l1x
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Occupancy is the state of a zone in an OccupancyGrid
+type Occupancy uint8
+
+const (
+	// Unknown is reported until a zone is measured or when its measurement
+	// failed for a reason other than no target being found
+	Unknown Occupancy = iota
+	// Free is reported when no target is closer than the near threshold
+	Free
+	// Near is reported when a target is closer than the near threshold
+	Near
+	// Blocked is reported when a target is closer than the blocked threshold
+	Blocked
+)
+
+// String implement Stringer interface for Occupancy
+func (o Occupancy) String() string {
+	switch o {
+	case Free:
+		return "free"
+	case Near:
+		return "near"
+	case Blocked:
+		return "blocked"
+	default:
+		return "unknown"
+	}
+}
+
+// OccupancyZone is a zone of the SPAD array with its thresholds
+type OccupancyZone struct {
+	Zone SPADRect
+	// NearMM and BlockedMM are the ranges below which the zone is Near and
+	// Blocked
+	NearMM    uint16
+	BlockedMM uint16
+}
+
+// OccupancyGrid is the occupancy of each configured zone
+type OccupancyGrid struct {
+	// Time the last zone was measured
+	Time time.Time
+	// Cells holds the occupancy of each zone in the order configured
+	Cells []Occupancy
+	// RangesMM holds the last valid range of each zone, 0 when none
+	RangesMM []uint16
+}
+
+// OccupancyMapper measures a set of zones in turn and maps them to an
+// occupancy vector for obstacle avoidance, ready to feed into motor control
+// or a costmap
+type OccupancyMapper struct {
+	v     *VL53L1X
+	zones []OccupancyZone
+	grid  OccupancyGrid
+	next  int
+}
+
+// NewOccupancyMapper returns an OccupancyMapper for the zones.  Continuous
+// ranging must be stopped while mapping
+func NewOccupancyMapper(v *VL53L1X, zones []OccupancyZone) (*OccupancyMapper, error) {
+
+	if len(zones) == 0 {
+		return nil, fmt.Errorf("no occupancy zones configured")
+	}
+
+	for i, z := range zones {
+		if z.BlockedMM > z.NearMM {
+			return nil, fmt.Errorf("zone %d blocked threshold is beyond near threshold", i)
+		}
+	}
+
+	return &OccupancyMapper{
+		v:     v,
+		zones: zones,
+		grid: OccupancyGrid{
+			Cells:    make([]Occupancy, len(zones)),
+			RangesMM: make([]uint16, len(zones)),
+		},
+	}, nil
+}
+
+// Step measures the next zone and returns the updated grid
+func (m *OccupancyMapper) Step(ctx context.Context) (OccupancyGrid, error) {
+
+	z := m.zones[m.next]
+	res, err := m.v.MeasureZone(ctx, z.Zone, 1)
+
+	if err != nil {
+		return OccupancyGrid{}, err
+	}
+
+	cell := m.classify(z, res)
+
+	m.grid.Cells[m.next] = cell
+	m.grid.Time = time.Now()
+
+	if res.Valid > 0 {
+		m.grid.RangesMM[m.next] = res.RangeMM
+	} else {
+		m.grid.RangesMM[m.next] = 0
+	}
+
+	m.next = (m.next + 1) % len(m.zones)
+
+	return m.Grid(), nil
+}
+
+// Scan measures every zone once and returns the grid
+func (m *OccupancyMapper) Scan(ctx context.Context) (OccupancyGrid, error) {
+
+	var grid OccupancyGrid
+	var err error
+
+	for range m.zones {
+		if grid, err = m.Step(ctx); err != nil {
+			return OccupancyGrid{}, err
+		}
+	}
+
+	return grid, nil
+}
+
+// Run measures the zones continuously calling fn with the updated grid after
+// each measurement, so the grid is emitted at the ranging rate.  It returns
+// when the context is cancelled or a measurement fails and restores the
+// previous ROI
+func (m *OccupancyMapper) Run(ctx context.Context, fn func(OccupancyGrid)) error {
+
+	width, height, err := m.v.GetROISize()
+
+	if err != nil {
+		return err
+	}
+
+	center, err := m.v.GetROICenter()
+
+	if err != nil {
+		return err
+	}
+
+	defer m.v.restoreROI(width, height, center)
+
+	for {
+		grid, err := m.Step(ctx)
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err != nil {
+			return err
+		}
+
+		fn(grid)
+	}
+}
+
+// Grid returns a copy of the current grid
+func (m *OccupancyMapper) Grid() OccupancyGrid {
+
+	return OccupancyGrid{
+		Time:     m.grid.Time,
+		Cells:    append([]Occupancy(nil), m.grid.Cells...),
+		RangesMM: append([]uint16(nil), m.grid.RangesMM...),
+	}
+}
+
+// classify returns the occupancy of the zone from its measurement
+func (m *OccupancyMapper) classify(z OccupancyZone, res ZoneResult) Occupancy {
+
+	if res.Valid == 0 {
+		// no target found within range
+		if res.LastStatus == SignalFail {
+			return Free
+		}
+
+		return Unknown
+	}
+
+	switch {
+	case res.RangeMM < z.BlockedMM:
+		return Blocked
+	case res.RangeMM < z.NearMM:
+		return Near
+	default:
+		return Free
+	}
+}
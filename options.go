@@ -0,0 +1,329 @@
+package vl53l1x
+
+import (
+	"log"
+	"maps"
+)
+
+// Option configures optional settings on a VL53L1X instance when passed to
+// New() or NewWithLog()
+type Option func(*VL53L1X)
+
+// WithMaxTransferSize limits the number of bytes read from the bus in a single
+// transfer.  Reads larger than this, such as the 17 byte result block, are
+// split into chunks with each chunk re-addressing the register it starts at.
+// This overrides any limit reported by a Bus implementing TransferSizer
+func WithMaxTransferSize(n int) Option {
+	return func(v *VL53L1X) {
+		if n < 0 {
+			n = 0
+		}
+
+		v.maxTransfer = n
+	}
+}
+
+// WithVerifyAddress enables a verification read of I2C_SLAVE_DEVICE_ADDRESS
+// after SetAddress() reopens the connection.  If verification fails the
+// sensor is rolled back to its previous address
+func WithVerifyAddress() Option {
+	return func(v *VL53L1X) {
+		v.verifyAddress = true
+	}
+}
+
+// WithBusOpener sets the function used to open a new connection when the
+// sensor address is changed with SetAddress().  This is needed for custom
+// Bus transports as the default opens a go-i2c connection
+func WithBusOpener(open func(addr uint8, dev string) (Bus, error)) Option {
+	return func(v *VL53L1X) {
+		v.openBus = open
+	}
+}
+
+// WithConsistencyCheck enables cross validation of each measurements result
+// fields with any inconsistencies reported in RangingData.Validity
+func WithConsistencyCheck() Option {
+	return func(v *VL53L1X) {
+		v.checkConsistency = true
+	}
+}
+
+// WithSD1Results extends each result block read to include the second sensing
+// period (SD1) fields which are reported in RangingData.SD1.  This adds 18
+// bytes to every measurement read from the bus
+func WithSD1Results() Option {
+	return func(v *VL53L1X) {
+		v.readSD1 = true
+	}
+}
+
+// WithStore sets a Store that calibration and configuration are loaded from
+// during Init().  Use SaveToStore() to persist the sensors current state
+func WithStore(s Store) Option {
+	return func(v *VL53L1X) {
+		v.store = s
+	}
+}
+
+// WithShutdownPin sets the pin connected to the sensors XSHUT input so
+// Close() can power down the sensor
+func WithShutdownPin(pin Pin) Option {
+	return func(v *VL53L1X) {
+		v.xshut = pin
+	}
+}
+
+// WithDistanceMode sets the distance mode the sensor is initialized with
+func WithDistanceMode(mode DistanceMode) Option {
+	return func(v *VL53L1X) {
+		v.distanceMode = mode
+	}
+}
+
+// WithTimingBudget sets the timing budget in milliseconds the sensor is
+// initialized with
+func WithTimingBudget(budget uint32) Option {
+	return func(v *VL53L1X) {
+		v.timingBudget = budget
+	}
+}
+
+// WithLogger sets the logger used for debugging
+func WithLogger(l *log.Logger) Option {
+	return func(v *VL53L1X) {
+		v.log = l
+	}
+}
+
+// WithTargetSignalRate sets the dynamic SPAD selection target signal rate in
+// 9.7 fixed point MCPS the sensor is initialized with, see
+// SetTargetSignalRate()
+func WithTargetSignalRate(rate uint16) Option {
+	return func(v *VL53L1X) {
+		v.targetRate = rate
+	}
+}
+
+// WithBudgetAutoAdjust clamps a timing budget that is not legal for the
+// distance mode to the nearest legal value and logs a warning, instead of
+// returning a *ModeBudgetError
+func WithBudgetAutoAdjust() Option {
+	return func(v *VL53L1X) {
+		v.autoAdjustBudget = true
+	}
+}
+
+// WithModeProfile registers a custom preset register table for the distance
+// mode before the sensor is initialized, see SetModeProfile()
+func WithModeProfile(mode DistanceMode, p Profile) Option {
+	return func(v *VL53L1X) {
+		if v.profiles == nil {
+			v.profiles = make(map[DistanceMode]Profile)
+		}
+
+		v.profiles[mode] = p
+	}
+}
+
+// WithHoldOff discards the first n measurements read after a configuration
+// change such as the distance mode or ROI, as these may have been taken with
+// the previous settings.  Pass HoldOffAuto to derive the count from whether
+// the sensor is ranging when the change is made
+func WithHoldOff(n int) Option {
+	return func(v *VL53L1X) {
+		if n < HoldOffAuto {
+			n = 0
+		}
+
+		v.holdOffFrames = n
+	}
+}
+
+// WithSmudgeDetection monitors the crosstalk seen when no target is in range
+// and emits a SmudgeEvent when its growth indicates the cover glass is
+// contaminated, so deployments know when to clean the window
+func WithSmudgeDetection(cfg SmudgeConfig) Option {
+	return func(v *VL53L1X) {
+		v.smudge = newSmudgeDetector(cfg)
+	}
+}
+
+// WithXtalkAutoCorrect enables dynamic crosstalk correction, which adjusts
+// the crosstalk compensation in bounded, rate limited steps as smudge
+// detection sees contamination accumulate.  Smudge detection is enabled with
+// defaults if WithSmudgeDetection() is not also given
+func WithXtalkAutoCorrect(cfg XtalkCorrectConfig) Option {
+	return func(v *VL53L1X) {
+		v.xtalk = newXtalkCorrector(cfg)
+
+		if v.smudge == nil {
+			v.smudge = newSmudgeDetector(SmudgeConfig{})
+		}
+	}
+}
+
+// WithBusSpeed sets the I2C clock rate in Hz during setup, see SetBusSpeed()
+func WithBusSpeed(hz int) Option {
+	return func(v *VL53L1X) {
+		v.busSpeed = hz
+	}
+}
+
+// WithIntegerResults skips the floating point conversion of rates and sigma
+// in RangingData, leaving them in RangingData.Raw, for microcontrollers
+// without a floating point unit.  Calibration, smudge detection and signal
+// based zone selection work from the raw rates so are unaffected
+func WithIntegerResults() Option {
+	return func(v *VL53L1X) {
+		v.integerResults = true
+	}
+}
+
+// WithWriteVerify reads back every write to a timing, ROI, threshold or
+// compensation register and fails the write with a WriteVerifyError when the
+// value does not match, to catch buses that silently drop writes.  Each
+// configuration write costs an extra register read
+func WithWriteVerify() Option {
+	return func(v *VL53L1X) {
+		v.writeVerify = true
+	}
+}
+
+// WithSnapshots keeps the raw result block and the timing and ROI registers
+// of the last n measurements for postmortem analysis of bad readings, see
+// DumpLastN().  Each measurement costs additional register reads
+func WithSnapshots(n int) Option {
+	return func(v *VL53L1X) {
+		if n > 0 {
+			v.snapshots = &snapshotRing{snaps: make([]Snapshot, n)}
+		}
+	}
+}
+
+// WithStrictStatus makes Read(), ReadContext(), ReadNoClear() and ReadSingle()
+// return a *MeasurementError carrying the measurement when its status is not
+// RangeValid, so an invalid measurement can not be mistaken for a distance by
+// a caller that forgets to check the status
+func WithStrictStatus() Option {
+	return func(v *VL53L1X) {
+		v.strictStatus = true
+	}
+}
+
+// WithStartupDiscard discards the first n measurements after each start of
+// ranging, by StartContinuous() or ReadSingle(), as ST notes the first
+// measurements after a start can be less accurate.  Single shot reads take n
+// extra measurements
+func WithStartupDiscard(n int) Option {
+	return func(v *VL53L1X) {
+		v.startupDiscard = max(n, 0)
+	}
+}
+
+// WithPeriodTrim measures the actual interval between continuous
+// measurements and trims SYSTEM_INTERMEASUREMENT_PERIOD to hold the requested
+// period, correcting the drift of the internal oscillator over long periods
+func WithPeriodTrim(cfg PeriodTrimConfig) Option {
+	return func(v *VL53L1X) {
+		v.trim = newPeriodTrimmer(cfg)
+	}
+}
+
+// WithTracer traces Init(), the calibration routines and each measurement
+// cycle with spans started by t
+func WithTracer(t Tracer) Option {
+	return func(v *VL53L1X) {
+		v.tracer = t
+	}
+}
+
+// WithRegisterLog logs every register read and write to the logger by
+// register name, for following a transaction log against ST's
+// documentation.  The values of the redact registers are hidden, such as
+// those holding part-specific calibration
+func WithRegisterLog(redact ...uint16) Option {
+	return func(v *VL53L1X) {
+		v.regLog = true
+		v.regRedact = make(map[uint16]bool, len(redact))
+
+		for _, reg := range redact {
+			v.regRedact[reg] = true
+		}
+	}
+}
+
+// WithULDInit makes Init() write the static configuration as ST's default
+// configuration block in a single transaction, see InitULD()
+func WithULDInit() Option {
+	return func(v *VL53L1X) {
+		v.uldInit = true
+	}
+}
+
+// WithConsistencyTolerance sets the phase consistency check tolerance the
+// sensor is initialized with, see SetConsistencyTolerance()
+func WithConsistencyTolerance(tol uint8) Option {
+	return func(v *VL53L1X) {
+		v.consistencyTol = tol
+	}
+}
+
+// WithStatusLED drives the LED according to the quality of the measurements
+// read, for headless installs where it is the only debugging aid
+func WithStatusLED(led LED, cfg StatusLEDConfig) Option {
+	return func(v *VL53L1X) {
+		v.statusLED = newStatusLED(led, cfg)
+	}
+}
+
+// WithName names the sensor, see SetName()
+func WithName(name string) Option {
+	return func(v *VL53L1X) {
+		v.meta.name = name
+	}
+}
+
+// WithLabels sets the labels describing the sensor, see SetLabels()
+func WithLabels(labels map[string]string) Option {
+	return func(v *VL53L1X) {
+		v.meta.labels = maps.Clone(labels)
+	}
+}
+
+// WithFrameLoss counts the continuous measurements completed by the sensor
+// but never read, as happens when the host reads slower than the period.  The
+// sensor holds only the latest result, so lost measurements can not be read
+// back, but they are reported in RangingData.LostFrames and Stats.LostFrames
+func WithFrameLoss() Option {
+	return func(v *VL53L1X) {
+		v.loss = &frameLoss{tolerance: -1}
+	}
+}
+
+// WithFrameLossTolerance counts lost measurements as WithFrameLoss() and has
+// the read methods return a *FrameLossError carrying the measurement when
+// more than n were lost since the previous read
+func WithFrameLossTolerance(n int) Option {
+	return func(v *VL53L1X) {
+		v.loss = &frameLoss{tolerance: max(n, 0)}
+	}
+}
+
+// WithAutoSingleShot makes a blocking Read(), ReadContext() or ReadNoClear()
+// take a single shot measurement as ReadSingle() does when continuous ranging
+// is not started, instead of returning ErrNotRanging
+func WithAutoSingleShot() Option {
+	return func(v *VL53L1X) {
+		v.autoSingleShot = true
+	}
+}
+
+// WithCycleTiming times each measurement cycle, reporting in
+// RangingData.Timing the wait for the measurement, the bus time reading it
+// and the host's time between reads
+func WithCycleTiming() Option {
+	return func(v *VL53L1X) {
+		v.cycle = &cycleTimer{}
+	}
+}
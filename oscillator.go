@@ -0,0 +1,71 @@
+package vl53l1x
+
+import "fmt"
+
+// Family bounds of the oscillator calibration values, generous windows around
+// the nominal values outside of which a part is suspect, such as from a
+// marginal supply or a counterfeit part
+const (
+	// FastOscMin and FastOscMax bound OSC_MEASURED_FAST_OSC_FREQUENCY in 4.12
+	// fixed point MHz, 10MHz to 12.5MHz
+	FastOscMin uint16 = 0xA000
+	FastOscMax uint16 = 0xC800
+	// OscCalibrateMin and OscCalibrateMax bound RESULT_OSC_CALIBRATE_VAL,
+	// the oscillator ticks per millisecond used to time the inter-measurement
+	// period
+	OscCalibrateMin uint16 = 0x0300
+	OscCalibrateMax uint16 = 0x0500
+)
+
+// OscillatorCheck is the result of validating the oscillator calibration
+// values read during Init()
+type OscillatorCheck struct {
+	// FastOscFrequency is the raw 4.12 fixed point value and FastOscMHz
+	// its frequency
+	FastOscFrequency uint16
+	FastOscMHz       float64
+	// OscCalibrateVal is the raw calibration of the inter-measurement timer
+	OscCalibrateVal uint16
+	// InFamily is false when either value is outside its family bounds and
+	// Problems describes why
+	InFamily bool
+	Problems []string
+}
+
+// FastOscFrequency returns OSC_MEASURED_FAST_OSC_FREQUENCY as read during
+// Init() in 4.12 fixed point MHz, which times the ranging macro periods
+func (v *VL53L1X) FastOscFrequency() uint16 {
+	return v.fastOscFrequency
+}
+
+// OscCalibrateVal returns RESULT_OSC_CALIBRATE_VAL as read during Init(),
+// which scales the inter-measurement period
+func (v *VL53L1X) OscCalibrateVal() uint16 {
+	return v.oscCalibrateVal
+}
+
+// CheckOscillator validates the oscillator calibration values against their
+// family bounds.  Values outside the bounds distort the timing budget and
+// inter-measurement period and indicate a marginal supply or counterfeit part
+func (v *VL53L1X) CheckOscillator() OscillatorCheck {
+
+	c := OscillatorCheck{
+		FastOscFrequency: v.fastOscFrequency,
+		FastOscMHz:       float64(v.fastOscFrequency) / 4096,
+		OscCalibrateVal:  v.oscCalibrateVal,
+	}
+
+	if c.FastOscFrequency < FastOscMin || c.FastOscFrequency > FastOscMax {
+		c.Problems = append(c.Problems, fmt.Sprintf("fast oscillator %.3fMHz outside %.1fMHz to %.1fMHz",
+			c.FastOscMHz, float64(FastOscMin)/4096, float64(FastOscMax)/4096))
+	}
+
+	if c.OscCalibrateVal < OscCalibrateMin || c.OscCalibrateVal > OscCalibrateMax {
+		c.Problems = append(c.Problems, fmt.Sprintf("oscillator calibration %d outside %d to %d",
+			c.OscCalibrateVal, OscCalibrateMin, OscCalibrateMax))
+	}
+
+	c.InFamily = len(c.Problems) == 0
+
+	return c
+}
@@ -0,0 +1,38 @@
+package vl53l1x
+
+import "fmt"
+
+// Pause halts continuous ranging while keeping the manual calibration
+// programmed after the first measurement, so Resume() does not pay the
+// accuracy penalty of the first measurement after StopContinuous()
+func (v *VL53L1X) Pause() error {
+
+	if !v.ranging {
+		return fmt.Errorf("continuous ranging is not active")
+	}
+
+	if err := v.writeModeStart(ModeStartAbort); err != nil {
+		return err
+	}
+
+	v.ranging = false
+	v.paused = true
+
+	return nil
+}
+
+// Resume restarts continuous ranging paused with Pause() with the same
+// inter-measurement period, or back to back
+func (v *VL53L1X) Resume() error {
+
+	if !v.paused {
+		return fmt.Errorf("ranging is not paused")
+	}
+
+	return v.restartRanging(v.periodMs)
+}
+
+// Paused returns true while ranging is paused with Pause()
+func (v *VL53L1X) Paused() bool {
+	return v.paused
+}
@@ -0,0 +1,138 @@
+package vl53l1x
+
+import (
+	"math"
+	"time"
+)
+
+// PeriodTrimConfig configures inter-measurement period drift correction
+type PeriodTrimConfig struct {
+	// Window is the number of measurement periods the actual period is
+	// averaged over before each correction, defaults to 100
+	Window int
+	// Tolerance is the fraction the actual period may differ from the
+	// requested period before it is trimmed, defaults to 0.005
+	Tolerance float64
+	// MaxTrim is the greatest fraction the period is trimmed by, defaults to
+	// 0.1
+	MaxTrim float64
+}
+
+// periodTrimmer measures the actual inter-measurement period and holds the
+// correction applied to SYSTEM_INTERMEASUREMENT_PERIOD
+type periodTrimmer struct {
+	cfg PeriodTrimConfig
+	// scale is the correction multiplied into the period register value,
+	// kept across restarts as it is a property of the oscillator
+	scale float64
+
+	active bool
+	start  time.Time
+	last   uint8
+	frames int
+}
+
+// newPeriodTrimmer returns a periodTrimmer for the configuration, zero values
+// are replaced by the defaults
+func newPeriodTrimmer(cfg PeriodTrimConfig) *periodTrimmer {
+
+	if cfg.Window <= 0 {
+		cfg.Window = 100
+	}
+
+	if cfg.Tolerance <= 0 {
+		cfg.Tolerance = 0.005
+	}
+
+	if cfg.MaxTrim <= 0 {
+		cfg.MaxTrim = 0.1
+	}
+
+	return &periodTrimmer{cfg: cfg, scale: 1}
+}
+
+// PeriodTrim returns the fraction the inter-measurement period register has
+// been trimmed by to hold the requested period, 0 when drift correction is
+// not enabled with WithPeriodTrim() or no correction was needed
+func (v *VL53L1X) PeriodTrim() float64 {
+
+	if v.trim == nil {
+		return 0
+	}
+
+	return v.trim.scale - 1
+}
+
+// periodRegister returns the SYSTEM_INTERMEASUREMENT_PERIOD value for the
+// period with any drift correction applied, restarting the measurement of
+// the actual period
+func (v *VL53L1X) periodRegister(periodMs uint32) uint32 {
+
+	val := periodMs * uint32(v.oscCalibrateVal)
+
+	if v.trim == nil {
+		return val
+	}
+
+	v.trim.active = false
+
+	return uint32(math.Round(float64(val) * v.trim.scale))
+}
+
+// trimPeriod adds the measurement just read at now to the actual period and
+// at the end of each window trims the period register when it differs from
+// the requested period by more than the tolerance
+func (v *VL53L1X) trimPeriod(now time.Time) error {
+
+	t := v.trim
+
+	// the period is limited by the timing budget when it is not longer
+	if t == nil || !v.ranging || v.singleShot || v.periodMs <= v.timingBudget {
+		return nil
+	}
+
+	count := v.results.streamCount
+
+	if !t.active {
+		t.active = true
+		t.start, t.last, t.frames = now, count, 0
+		return nil
+	}
+
+	t.frames += streamDelta(t.last, count)
+	t.last = count
+
+	if t.frames < t.cfg.Window {
+		return nil
+	}
+
+	actual := now.Sub(t.start).Seconds() / float64(t.frames)
+	target := float64(v.periodMs) / 1000
+	t.start, t.frames = now, 0
+
+	if math.Abs(actual-target)/target <= t.cfg.Tolerance {
+		return nil
+	}
+
+	t.scale = min(max(t.scale*target/actual, 1-t.cfg.MaxTrim), 1+t.cfg.MaxTrim)
+	val := uint32(math.Round(float64(v.periodMs) * float64(v.oscCalibrateVal) * t.scale))
+
+	v.log.Printf("Inter-measurement period %.2fms, trimming by %+.2f%%",
+		actual*1000, (t.scale-1)*100)
+
+	// the interval spanning the write is neither period, so measure afresh
+	t.active = false
+
+	return v.writeReg32Bit(SYSTEM_INTERMEASUREMENT_PERIOD, val)
+}
+
+// streamDelta returns the number of measurements between two stream counts,
+// which run 0 to 255 then wrap back to 128
+func streamDelta(prev, cur uint8) int {
+
+	if cur >= prev {
+		return int(cur - prev)
+	}
+
+	return int(255-prev) + int(cur-128) + 1
+}
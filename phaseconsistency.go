@@ -0,0 +1,41 @@
+package vl53l1x
+
+import "fmt"
+
+// maxConsistencyTolerance is the largest value of the 4 bit
+// ALGO_CONSISTENCY_CHECK_TOLERANCE field
+const maxConsistencyTolerance uint8 = 0x0F
+
+// SetConsistencyTolerance sets the tolerance of the phase consistency check,
+// which compares the target phase found in the two VCSEL periods of each
+// measurement.  The tolerance is in 1.3 fixed point phase units from 1 to 15
+// and defaults to DefaultConsistencyTolerance.
+//
+// When two targets at different distances share the field of view, such as
+// the edge of a table in front of a wall, their phases disagree between the
+// periods.  A tight tolerance rejects these measurements as WrapTargetFail,
+// so the range drops out or flickers between the two targets as either
+// dominates.  A loose tolerance accepts them, reporting a single merged range
+// between the targets as valid.  Tighten it where a wrong distance is worse
+// than no distance, loosen it where continuous output matters more
+func (v *VL53L1X) SetConsistencyTolerance(tol uint8) error {
+
+	if tol == 0 || tol > maxConsistencyTolerance {
+		return fmt.Errorf("consistency tolerance must be between 1 and %d", maxConsistencyTolerance)
+	}
+
+	if err := v.writeReg(ALGO_CONSISTENCY_CHECK_TOLERANCE, tol); err != nil {
+		return err
+	}
+
+	v.consistencyTol = tol
+	v.configChanged("consistency tolerance")
+
+	return nil
+}
+
+// GetConsistencyTolerance returns the tolerance of the phase consistency
+// check
+func (v *VL53L1X) GetConsistencyTolerance() uint8 {
+	return v.consistencyTol
+}
@@ -0,0 +1,220 @@
+package vl53l1x
+
+import "fmt"
+
+// defaultValidPhaseLow is the lower bound of the valid phase window used by
+// all of ST's standard ranging presets
+const defaultValidPhaseLow uint8 = 0x08
+
+// Profile is the preset register table written when a distance mode is
+// selected
+type Profile struct {
+	// timing config
+	VCSELPeriodA   uint8
+	VCSELPeriodB   uint8
+	ValidPhaseHigh uint8
+	// ValidPhaseLow is the lower bound of the valid phase window in 5.3 fixed
+	// point, 0 uses ST's default of 0x08
+	ValidPhaseLow uint8
+
+	// dynamic config
+	WOISD0          uint8
+	WOISD1          uint8
+	InitialPhaseSD0 uint8
+	InitialPhaseSD1 uint8
+
+	// MinBudget is the minimum timing budget in milliseconds allowed with the
+	// profile, 0 uses the 33ms minimum that works for all modes
+	MinBudget uint32
+}
+
+// defaultProfiles are the ST preset tables for the built in distance modes
+var defaultProfiles = map[DistanceMode]Profile{
+	// from VL53L1_preset_mode_standard_ranging_short_range()
+	Short: {
+		VCSELPeriodA:    0x07,
+		VCSELPeriodB:    0x05,
+		ValidPhaseHigh:  0x38,
+		WOISD0:          0x07,
+		WOISD1:          0x05,
+		InitialPhaseSD0: 6,
+		InitialPhaseSD1: 6,
+		MinBudget:       minTimingBudgetShort,
+	},
+	// from VL53L1_preset_mode_standard_ranging()
+	Medium: {
+		VCSELPeriodA:    0x0B,
+		VCSELPeriodB:    0x09,
+		ValidPhaseHigh:  0x78,
+		WOISD0:          0x0B,
+		WOISD1:          0x09,
+		InitialPhaseSD0: 10,
+		InitialPhaseSD1: 10,
+		MinBudget:       minTimingBudget,
+	},
+	// from VL53L1_preset_mode_standard_ranging_long_range()
+	Long: {
+		VCSELPeriodA:    0x0F,
+		VCSELPeriodB:    0x0D,
+		ValidPhaseHigh:  0xB8,
+		WOISD0:          0x0F,
+		WOISD1:          0x0D,
+		InitialPhaseSD0: 14,
+		InitialPhaseSD1: 14,
+		MinBudget:       minTimingBudget,
+	},
+}
+
+// validate checks the profile can be written to the sensor
+func (p Profile) validate() error {
+
+	if p.VCSELPeriodA == 0 || p.VCSELPeriodB == 0 {
+		return fmt.Errorf("profile VCSEL periods must be non-zero")
+	}
+
+	if p.ValidPhaseLow >= p.ValidPhaseHigh && p.ValidPhaseLow != 0 {
+		return fmt.Errorf("profile valid phase low 0x%02X must be below high 0x%02X",
+			p.ValidPhaseLow, p.ValidPhaseHigh)
+	}
+
+	if p.MinBudget > maxTimingBudget {
+		return fmt.Errorf("profile minimum budget %dms exceeds maximum of %dms",
+			p.MinBudget, maxTimingBudget)
+	}
+
+	return nil
+}
+
+// minBudget returns the minimum timing budget of the profile
+func (p Profile) minBudget() uint32 {
+
+	if p.MinBudget == 0 {
+		return minTimingBudget
+	}
+
+	return p.MinBudget
+}
+
+// validPhaseLow returns the lower bound of the valid phase window of the
+// profile
+func (p Profile) validPhaseLow() uint8 {
+
+	if p.ValidPhaseLow == 0 {
+		return defaultValidPhaseLow
+	}
+
+	return p.ValidPhaseLow
+}
+
+// SetModeProfile registers a custom preset register table for the distance
+// mode, overriding the ST defaults for Short, Medium and Long or defining an
+// entirely new mode.  If the mode is currently selected the profile is
+// written to the sensor immediately
+func (v *VL53L1X) SetModeProfile(mode DistanceMode, p Profile) error {
+
+	if err := p.validate(); err != nil {
+		return err
+	}
+
+	if v.profiles == nil {
+		v.profiles = make(map[DistanceMode]Profile)
+	}
+
+	v.profiles[mode] = p
+
+	if mode != v.distanceMode {
+		return nil
+	}
+
+	budget, err := v.checkModeBudget(mode, v.timingBudget)
+
+	if err != nil {
+		return err
+	}
+
+	return v.setDistanceMode(mode, budget)
+}
+
+// GetModeProfile returns the preset register table used for the distance mode
+func (v *VL53L1X) GetModeProfile(mode DistanceMode) (Profile, bool) {
+	return v.modeProfile(mode)
+}
+
+// modeProfile returns the custom profile registered for the mode, falling
+// back to the ST default
+func (v *VL53L1X) modeProfile(mode DistanceMode) (Profile, bool) {
+
+	if p, ok := v.profiles[mode]; ok {
+		return p, true
+	}
+
+	p, ok := defaultProfiles[mode]
+	return p, ok
+}
+
+// writeProfile writes the preset register table to the sensor
+func (v *VL53L1X) writeProfile(p Profile) error {
+
+	// timing config
+	if err := v.writeReg(RANGE_CONFIG_VCSEL_PERIOD_A, p.VCSELPeriodA); err != nil {
+		return err
+	}
+	if err := v.writeReg(RANGE_CONFIG_VCSEL_PERIOD_B, p.VCSELPeriodB); err != nil {
+		return err
+	}
+	if err := v.writeReg(RANGE_CONFIG_VALID_PHASE_LOW, p.validPhaseLow()); err != nil {
+		return err
+	}
+	if err := v.writeReg(RANGE_CONFIG_VALID_PHASE_HIGH, p.ValidPhaseHigh); err != nil {
+		return err
+	}
+
+	// dynamic config
+	if err := v.writeReg(SD_CONFIG_WOI_SD0, p.WOISD0); err != nil {
+		return err
+	}
+	if err := v.writeReg(SD_CONFIG_WOI_SD1, p.WOISD1); err != nil {
+		return err
+	}
+	if err := v.writeReg(SD_CONFIG_INITIAL_PHASE_SD0, p.InitialPhaseSD0); err != nil {
+		return err
+	}
+	if err := v.writeReg(SD_CONFIG_INITIAL_PHASE_SD1, p.InitialPhaseSD1); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetValidPhaseWindow sets the window of target phases in 5.3 fixed point the
+// sensor accepts as valid in the current distance mode, by registering a copy
+// of its profile with the window replaced.  Phases outside the window are
+// reported as OutOfBoundsFail, so narrowing the high bound rejects targets
+// beyond the wrap around distance of the VCSEL period rather than reporting
+// them aliased as near targets
+func (v *VL53L1X) SetValidPhaseWindow(low, high uint8) error {
+
+	if low == 0 || low >= high {
+		return fmt.Errorf("valid phase window 0x%02X to 0x%02X must be non-empty with a non-zero low bound",
+			low, high)
+	}
+
+	p, ok := v.modeProfile(v.distanceMode)
+
+	if !ok {
+		return fmt.Errorf("no profile for distance mode %s", v.distanceMode)
+	}
+
+	p.ValidPhaseLow, p.ValidPhaseHigh = low, high
+
+	return v.SetModeProfile(v.distanceMode, p)
+}
+
+// GetValidPhaseWindow returns the window of target phases in 5.3 fixed point
+// accepted as valid in the current distance mode
+func (v *VL53L1X) GetValidPhaseWindow() (low, high uint8) {
+
+	p, _ := v.modeProfile(v.distanceMode)
+
+	return p.validPhaseLow(), p.ValidPhaseHigh
+}
@@ -1,10 +1,16 @@
 package vl53l1x
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 )
 
+// ErrNotRanging is returned by a blocking read when continuous ranging has not
+// been started, as no measurement would become ready to wait for
+var ErrNotRanging = errors.New("continuous ranging not started, call StartContinuous() or ReadSingle()")
+
 // RangeStatus represents the sensor’s reported status.
 type RangeStatus uint8
 
@@ -20,7 +26,11 @@ const (
 	XtalkSignalFail           RangeStatus = 9
 	SynchronizationInt        RangeStatus = 10
 	MinRangeFail              RangeStatus = 13
-	NoneStatus                RangeStatus = 255
+	// ImplausibleData is not reported by the sensor but set by the driver when
+	// a measurement reported as valid has result fields outside of what the
+	// sensor can physically produce, typically from a corrupted I2C read
+	ImplausibleData RangeStatus = 254
+	NoneStatus      RangeStatus = 255
 )
 
 // RangingData holds a single range measurement and related rate information.
@@ -29,6 +39,45 @@ type RangingData struct {
 	RangeStatus             RangeStatus
 	PeakSignalCountRateMCPS float32
 	AmbientCountRateMCPS    float32
+	// SigmaMM is the estimated standard deviation of the range
+	SigmaMM float32
+	// Raw holds the rates and sigma in the sensors fixed point formats, the
+	// only ones set when WithIntegerResults() is used
+	Raw RawRates
+	// DeviceError is the device error code the RangeStatus was derived from,
+	// which distinguishes the internal failure causes RangeStatus groups
+	DeviceError DeviceError
+	// ReportStatus is the RESULT_REPORT_STATUS register read with the
+	// measurement
+	ReportStatus uint8
+	// Validity flags inconsistencies between the measurements result fields,
+	// only set when enabled with WithConsistencyCheck()
+	Validity Validity
+	// SD1 holds the second sensing period results, only set when enabled with
+	// WithSD1Results()
+	SD1 SD1Data
+	// LostFrames is the number of continuous measurements completed since
+	// the previous read that were never read, only set when enabled with
+	// WithFrameLoss() or WithFrameLossTolerance()
+	LostFrames int
+	// Timing is the timing of the measurement cycle, only set when enabled
+	// with WithCycleTiming()
+	Timing CycleTiming
+}
+
+// RawRates holds measurement rates and sigma as reported by the sensor
+type RawRates struct {
+	// PeakSignalCountRate and AmbientCountRate are in 9.7 fixed point MCPS
+	PeakSignalCountRate uint16
+	AmbientCountRate    uint16
+	// Sigma is in 14.2 fixed point millimeters
+	Sigma uint16
+}
+
+// peakSignalMCPS returns the peak signal rate in MCPS, used by features that
+// need it whether or not WithIntegerResults() left the float rates unset
+func (r RawRates) peakSignalMCPS() float32 {
+	return float32(r.PeakSignalCountRate) / float32(1<<7)
 }
 
 // String implement Stringer interface for RangeStatus
@@ -56,6 +105,8 @@ func (s RangeStatus) String() string {
 		return "synchronization int"
 	case MinRangeFail:
 		return "min range fail"
+	case ImplausibleData:
+		return "implausible data"
 	case NoneStatus:
 		return "no update"
 	default:
@@ -63,24 +114,141 @@ func (s RangeStatus) String() string {
 	}
 }
 
+// IsValid returns true if the status reports a usable distance
+func (s RangeStatus) IsValid() bool {
+	return s == RangeValid || s == RangeValidMinRangeClipped ||
+		s == RangeValidNoWrapCheckFail
+}
+
+// IsRecoverable returns true if the status does not report a usable distance
+// but a later measurement can be expected to, such as when the target is
+// weak or out of range, so the reading should be discarded and another taken.
+// HardwareFail is not recoverable without reinitializing the sensor
+func (s RangeStatus) IsRecoverable() bool {
+	return !s.IsValid() && s != HardwareFail
+}
+
+// StatusSeverity classifies a RangeStatus by how a reading should be treated
+type StatusSeverity int
+
+const (
+	// SeverityOK readings are valid
+	SeverityOK StatusSeverity = iota
+	// SeverityDegraded readings are usable with reduced confidence, such as
+	// a clipped minimum range or the first reading without a wrap check
+	SeverityDegraded
+	// SeverityInvalid readings should be discarded but the next may be valid
+	SeverityInvalid
+	// SeverityFatal readings report a sensor fault
+	SeverityFatal
+)
+
+// String implement Stringer interface for StatusSeverity
+func (s StatusSeverity) String() string {
+	switch s {
+	case SeverityOK:
+		return "ok"
+	case SeverityDegraded:
+		return "degraded"
+	case SeverityInvalid:
+		return "invalid"
+	default:
+		return "fatal"
+	}
+}
+
+// Severity returns the classification of the status
+func (s RangeStatus) Severity() StatusSeverity {
+	switch {
+	case s == RangeValid:
+		return SeverityOK
+	case s.IsValid():
+		return SeverityDegraded
+	case s == HardwareFail:
+		return SeverityFatal
+	default:
+		return SeverityInvalid
+	}
+}
+
 // StartContinuous begins continuous ranging with the given period (in ms).
 func (v *VL53L1X) StartContinuous(periodMs uint32) error {
 
 	v.log.Print("Start continuous mode")
 
 	// Write inter-measurement period (periodMs * osc_calibrate_val)
-	val := periodMs * uint32(v.oscCalibrateVal)
+	val := v.periodRegister(periodMs)
 
 	if err := v.writeReg32Bit(SYSTEM_INTERMEASUREMENT_PERIOD, val); err != nil {
 		return err
 	}
 
+	return v.startRanging(ModeStartTimed, periodMs)
+}
+
+// StartBackToBack begins continuous ranging with each measurement started as
+// soon as the previous completes, giving the highest rate the timing budget
+// allows without the inter-measurement period.  Stop it with StopContinuous()
+func (v *VL53L1X) StartBackToBack() error {
+
+	v.log.Print("Start back to back mode")
+
+	return v.startRanging(ModeStartBackToBack, 0)
+}
+
+// startRanging clears the interrupt and starts continuous ranging in mode,
+// with a periodMs of 0 for back to back ranging
+func (v *VL53L1X) startRanging(mode ModeStart, periodMs uint32) error {
+
 	if err := v.writeReg(SYSTEM_INTERRUPT_CLEAR, 0x01); err != nil {
 		return err
 	}
 
-	// 0x40 is mode_start timed
-	return v.writeReg(SYSTEM_MODE_START, 0x40)
+	if err := v.writeModeStart(mode); err != nil {
+		return err
+	}
+
+	v.armCycle(true)
+
+	// the stream count restarts with ranging
+	v.haveStreamCount = false
+	v.loss.reset()
+	v.ranging = true
+	v.paused = false
+	v.periodMs = periodMs
+	v.holdOff = max(v.holdOff, v.startupDiscard)
+	v.checkBusRate()
+
+	return nil
+}
+
+// restartRanging starts continuous ranging again as it was started, back to
+// back when periodMs is 0
+func (v *VL53L1X) restartRanging(periodMs uint32) error {
+
+	if periodMs == 0 {
+		return v.StartBackToBack()
+	}
+
+	return v.StartContinuous(periodMs)
+}
+
+// suspendRanging stops continuous ranging if it is active so single shot
+// measurements can be taken, returning a function that restarts it with the
+// same period
+func (v *VL53L1X) suspendRanging() (resume func() error, err error) {
+
+	if !v.ranging {
+		return func() error { return nil }, nil
+	}
+
+	period := v.periodMs
+
+	if err := v.StopContinuous(); err != nil {
+		return nil, err
+	}
+
+	return func() error { return v.restartRanging(period) }, nil
 }
 
 // StopContinuous stops continuous ranging.
@@ -88,14 +256,20 @@ func (v *VL53L1X) StopContinuous() error {
 
 	v.log.Print("Stop continuous mode")
 
-	// 0x80 is mode_start abort
-	if err := v.writeReg(SYSTEM_MODE_START, 0x80); err != nil {
+	if err := v.writeModeStart(ModeStartAbort); err != nil {
 		return err
 	}
 
+	v.ranging = false
+	v.paused = false
+
 	// In low-power auto mode, restore VHV configuration.
 	v.calibrated = false
 
+	// stream count restarts with the next ranging session
+	v.haveStreamCount = false
+	v.loss.reset()
+
 	if v.savedVHVInit != 0 {
 		if err := v.writeReg(VHV_CONFIG_INIT, v.savedVHVInit); err != nil {
 			return err
@@ -114,73 +288,262 @@ func (v *VL53L1X) StopContinuous() error {
 
 // Read returns a range data read from sensor. If blocking is true, this function
 // will wait for a new measurement to be captured.  If blocking is false then it
-// reads existing measurement from register.
+// reads existing measurement from register.  A blocking read returns
+// ErrNotRanging when continuous ranging is not started, unless
+// WithAutoSingleShot() is set
 func (v *VL53L1X) Read(blocking bool) (RangingData, error) {
+	return v.strict(v.readRanging(context.Background(), blocking, true))
+}
 
-	if blocking {
+// ReadContext waits for a new measurement to be captured and returns it.  The
+// wait is bound by the context, or if it has no deadline, the timeout set with
+// SetTimeout()
+func (v *VL53L1X) ReadContext(ctx context.Context) (RangingData, error) {
+	return v.strict(v.readRanging(ctx, true, true))
+}
 
-		v.startTimeout()
+// ReadNoClear returns a range data read from sensor like Read() but leaves
+// the interrupt set, so the result registers are not overwritten by the next
+// measurement.  This allows further result fields or debug registers to be
+// read before ClearInterrupt() is called to arm the next measurement
+func (v *VL53L1X) ReadNoClear(blocking bool) (RangingData, error) {
+	return v.strict(v.readRanging(context.Background(), blocking, false))
+}
 
-		for {
-			ready, err := v.dataReady()
+// readRanging performs a read of continuous ranging, returning ErrNotRanging
+// rather than waiting for a measurement that will never be ready when it is
+// not started, or taking a single shot measurement instead when
+// WithAutoSingleShot() is set
+func (v *VL53L1X) readRanging(ctx context.Context, blocking, clear bool) (RangingData, error) {
 
-			if err != nil {
-				return RangingData{}, err
+	if blocking && !v.ranging {
+		if v.autoSingleShot {
+			return v.readSingle(ctx)
+		}
+
+		return RangingData{}, ErrNotRanging
+	}
+
+	return v.readEvent(ctx, blocking, clear)
+}
+
+// ClearInterrupt clears the sensor interrupt which allows the next
+// measurement to be taken, used after ReadNoClear()
+func (v *VL53L1X) ClearInterrupt() error {
+
+	if err := v.writeReg(SYSTEM_INTERRUPT_CLEAR, 0x01); err != nil {
+		return err
+	}
+
+	v.armCycle(false)
+
+	return nil
+}
+
+// readEvent performs the read and emits its event
+func (v *VL53L1X) readEvent(ctx context.Context, blocking, clear bool) (RangingData, error) {
+
+	span := v.traceMeasurement(ctx)
+	rData, err := v.read(ctx, blocking, clear)
+	defer v.endCycle()
+
+	if span != nil {
+		endMeasurement(span, rData, err)
+	}
+
+	v.updateLED(rData, err)
+
+	if err != nil {
+		return rData, v.emitError("read", err)
+	}
+
+	v.stats.measurement(rData.RangeStatus)
+	v.recordDebug(rData)
+	v.captureSnapshot(rData)
+
+	// boxing the events allocates, so they are only built when listened to
+	if v.listening() {
+		name, labels := v.identity()
+		v.emit(MeasurementEvent{Time: time.Now(), Data: rData, Name: name, Labels: labels})
+
+		if v.eventDetect {
+			v.emit(ThresholdEvent{Time: time.Now(), Data: rData, Name: name, Labels: labels})
+		}
+	}
+
+	v.checkSmudge(rData)
+	v.checkTemperature()
+
+	if err := v.trimPeriod(time.Now()); err != nil {
+		return rData, v.emitError("trim period", err)
+	}
+
+	return rData, nil
+}
+
+// read performs the measurement read for Read(), clearing the interrupt
+// afterwards if clear is set
+func (v *VL53L1X) read(ctx context.Context, blocking, clear bool) (RangingData, error) {
+
+	called := time.Now()
+	var ready time.Time
+
+	for {
+		if blocking {
+			if err := v.waitFor(ctx, "data", v.dataReady); err != nil {
+				return RangingData{}, v.timeoutContext(err)
 			}
+		}
+
+		ready = time.Now()
+
+		if err := v.readResults(); err != nil {
+			return RangingData{}, err
+		}
 
-			if ready {
-				break
+		// the manual calibration and DSS update of low power auto mode only
+		// apply to continuous ranging, single shot measurements calibrate
+		// themselves as in ST's timed ranging preset
+		if !v.singleShot {
+			if !v.calibrated {
+				if err := v.setupManualCalibration(); err != nil {
+					return RangingData{}, err
+				}
+
+				v.calibrated = true
+				v.noteCalibrationTemp()
 			}
 
-			if v.checkTimeoutExpired() {
-				v.didTimeout = true
-				return RangingData{}, fmt.Errorf("timeout waiting for data")
+			if err := v.updateDSS(); err != nil {
+				return RangingData{}, err
 			}
+		}
+
+		if !v.holdingOff() {
+			break
+		}
+
+		// discard the measurement and arm the next
+		if err := v.ClearInterrupt(); err != nil {
+			return RangingData{}, err
+		}
 
-			time.Sleep(1 * time.Millisecond)
+		if !blocking {
+			return RangingData{}, ErrHoldOff
 		}
 	}
 
-	if err := v.readResults(); err != nil {
-		return RangingData{}, err
+	rData := v.getRangingData()
+
+	if v.cycle != nil {
+		rData.Timing = v.cycleTiming(blocking, called, ready)
 	}
 
-	if !v.calibrated {
-		if err := v.setupManualCalibration(); err != nil {
+	if clear {
+		if err := v.ClearInterrupt(); err != nil {
 			return RangingData{}, err
 		}
+	}
 
-		v.calibrated = true
+	if v.cycle != nil {
+		rData.Timing.Read = time.Since(ready)
 	}
 
-	if err := v.updateDSS(); err != nil {
+	return rData, v.checkFrameLoss(rData)
+}
+
+// ReadSingle performs a single-shot ranging measurement
+func (v *VL53L1X) ReadSingle() (RangingData, error) {
+	return v.strict(v.readSingle(context.Background()))
+}
+
+// readSingle performs a single-shot ranging measurement with the wait bound
+// by the context.  Startup frames set with WithStartupDiscard() are measured
+// and discarded first.  The VHV and phase calibration run with each
+// measurement, and when the driver manages DSS the firmware selects SPADs
+// for the target rate instead, so the state saved for continuous ranging is
+// not disturbed
+func (v *VL53L1X) readSingle(ctx context.Context) (RangingData, error) {
+
+	// the measurement is started after any configuration change so uses the
+	// new settings and there is no next measurement to wait for
+	v.holdOff = 0
+
+	v.singleShot = true
+	defer func() { v.singleShot = false }()
+
+	restoreDSS, err := v.singleShotDSS()
+
+	if err != nil {
 		return RangingData{}, err
 	}
 
-	rData := v.getRangingData()
+	rData, err := v.measureSingle(ctx)
 
-	if err := v.writeReg(SYSTEM_INTERRUPT_CLEAR, 0x01); err != nil {
+	if rerr := restoreDSS(); err == nil && rerr != nil {
+		return RangingData{}, rerr
+	}
+
+	return rData, err
+}
+
+// measureSingle takes the startup discard and single shot measurements
+func (v *VL53L1X) measureSingle(ctx context.Context) (RangingData, error) {
+
+	for i := 0; i < v.startupDiscard; i++ {
+		if err := v.startSingle(); err != nil {
+			return RangingData{}, err
+		}
+
+		if _, err := v.read(ctx, true, true); err != nil {
+			return RangingData{}, err
+		}
+	}
+
+	if err := v.startSingle(); err != nil {
 		return RangingData{}, err
 	}
 
-	return rData, nil
+	return v.readEvent(ctx, true, true)
 }
 
-// ReadSingle performs a single-shot ranging measurement
-func (v *VL53L1X) ReadSingle() (RangingData, error) {
+// singleShotDSS switches DSS from the requested SPAD count updated by the
+// driver after each continuous measurement to the firmware target rate
+// selection used by the timed ranging preset, returning a function that
+// restores it
+func (v *VL53L1X) singleShotDSS() (restore func() error, err error) {
+
+	val, err := v.readReg(DSS_CONFIG_ROI_MODE_CONTROL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if DSSMode(val&0x03) != DSSRequestedEffectiveSPADs {
+		return func() error { return nil }, nil
+	}
+
+	if err := v.writeReg(DSS_CONFIG_ROI_MODE_CONTROL, val&^0x03|uint8(DSSTargetRate)); err != nil {
+		return nil, err
+	}
+
+	return func() error { return v.writeReg(DSS_CONFIG_ROI_MODE_CONTROL, val) }, nil
+}
+
+// startSingle clears the interrupt and starts a single shot measurement
+func (v *VL53L1X) startSingle() error {
 
 	if err := v.writeReg(SYSTEM_INTERRUPT_CLEAR, 0x01); err != nil {
-		return RangingData{}, err
+		return err
 	}
 
-	// 0x10 is mode_start single shot
-	if err := v.writeReg(SYSTEM_MODE_START, 0x10); err != nil {
-		return RangingData{}, err
+	if err := v.writeModeStart(ModeStartSingleShot); err != nil {
+		return err
 	}
 
-	rData, err := v.Read(true)
-	return rData, err
+	v.armCycle(true)
 
+	return nil
 }
 
 // ReadRangeContinuousMillimeters returns a range reading in millimeters
@@ -197,8 +560,8 @@ func (v *VL53L1X) ReadRangeSingleMillimeters() (uint16, error) {
 	return rData.RangeMM, err
 }
 
-// dataReady checks if the sensor has a new reading available. It assumes interrupt
-// is active Low (GPIO_HV_MUX__CTRL bit 4 is 1)
+// dataReady checks if the sensor has a new reading available, following the
+// interrupt polarity set with SetInterruptPolarity()
 func (v *VL53L1X) dataReady() (bool, error) {
 
 	status, err := v.readReg(GPIO_TIO_HV_STATUS)
@@ -207,48 +570,89 @@ func (v *VL53L1X) dataReady() (bool, error) {
 		return false, err
 	}
 
-	// Active low: data ready when bit 0 == 0.
-	return (status & 0x01) == 0, nil
+	v.lastGPIOStatus = status
+
+	// bit 0 follows the interrupt output, low when ready unless the
+	// polarity has been set active high
+	if v.intActiveHigh {
+		return status&0x01 == 1, nil
+	}
+
+	return status&0x01 == 0, nil
 }
 
-// readResults reads sensor measurement results into buffer
-func (v *VL53L1X) readResults() error {
+// timeoutContext adds the sensor state to a *TimeoutError returned while
+// waiting for data
+func (v *VL53L1X) timeoutContext(err error) error {
 
-	// Begin reading at RESULT_RANGE_STATUS.
-	addr := []byte{byte(RESULT_RANGE_STATUS >> 8), byte(RESULT_RANGE_STATUS)}
+	var te *TimeoutError
 
-	if _, err := v.bus.WriteBytes(addr); err != nil {
+	if !errors.As(err, &te) {
 		return err
 	}
 
-	buf := make([]byte, 17)
+	te.GPIOStatus = v.lastGPIOStatus
+	te.RangingStarted = v.ranging || v.singleShot
+
+	// best effort as a sensor that has dropped off the bus will fail this too
+	if status, serr := v.GetSystemStatus(); serr == nil {
+		te.SystemStatus = status
+	}
+
+	return te
+}
+
+// readResults reads sensor measurement results into buffer
+func (v *VL53L1X) readResults() error {
+
+	size := resultBufferSize
+
+	if v.readSD1 {
+		size = resultBufferSizeSD1
+	}
 
-	n, err := v.bus.ReadBytes(buf)
+	// Begin reading at RESULT_RANGE_STATUS.
+	buf := v.rbuf[:size]
+	n, err := v.readRegBytes(RESULT_RANGE_STATUS, buf)
 
 	if err != nil {
 		return err
 	}
 
-	if n < 17 {
+	if n < size {
 		return fmt.Errorf("readResults: insufficient data read")
 	}
 
-	v.results.rangeStatus = buf[0]
-
-	// report_status (buf[1]) -- not used
+	if v.snapshots != nil {
+		v.snapshots.stage(buf)
+	}
 
+	v.results.rangeStatus = buf[0]
+	v.results.reportStatus = buf[1]
 	v.results.streamCount = buf[2]
 	v.results.dssActualEffectiveSpadsSD0 = uint16(buf[3])<<8 | uint16(buf[4])
 
-	// peak_signal_count_rate_mcps_sd0 (buf[5], buf[6]) -- not used
-
+	v.results.peakSignalCountRateMCPS_SD0 = uint16(buf[5])<<8 | uint16(buf[6])
 	v.results.ambientCountRateMCPS_SD0 = uint16(buf[7])<<8 | uint16(buf[8])
-
-	// sigma_sd0 (buf[9], buf[10]) and phase_sd0 (buf[11], buf[12]) -- not used
+	v.results.sigmaSD0 = uint16(buf[9])<<8 | uint16(buf[10])
+	v.results.phaseSD0 = uint16(buf[11])<<8 | uint16(buf[12])
 
 	v.results.finalCrosstalkCorrectedRangeMM_SD0 = uint16(buf[13])<<8 | uint16(buf[14])
 	v.results.peakSignalCountRateCrosstalkCorrectedMCPS_SD0 = uint16(buf[15])<<8 | uint16(buf[16])
 
+	if v.readSD1 {
+		// mm_inner/mm_outer effective spads, avg_signal_count_rate_mcps_sd0
+		// and dss_actual_effective_spads_sd1 (buf[17] to buf[24]) -- not used
+
+		v.results.peakSignalCountRateMCPS_SD1 = uint16(buf[25])<<8 | uint16(buf[26])
+		v.results.ambientCountRateMCPS_SD1 = uint16(buf[27])<<8 | uint16(buf[28])
+		v.results.sigmaSD1 = uint16(buf[29])<<8 | uint16(buf[30])
+
+		// phase_sd1 (buf[31], buf[32]) -- not used
+
+		v.results.finalCrosstalkCorrectedRangeMM_SD1 = uint16(buf[33])<<8 | uint16(buf[34])
+	}
+
 	return nil
 }
 
@@ -321,7 +725,7 @@ func (v *VL53L1X) updateDSS() error {
 
 		if totalRatePerSpad != 0 {
 			// get the target rate and shift up by 16
-			requiredSpads := (uint32(TargetRate) << 16) / totalRatePerSpad
+			requiredSpads := (uint32(v.targetRate) << 16) / totalRatePerSpad
 
 			// clip to 16 bit
 			if requiredSpads > 0xFFFF {
@@ -347,30 +751,35 @@ func (v *VL53L1X) getRangingData() RangingData {
 
 	rangeVal := v.results.finalCrosstalkCorrectedRangeMM_SD0
 
-	// apply a gain correction: (r * 2011 + 0x0400) / 0x0800
-	rData.RangeMM = uint16((uint32(rangeVal)*2011 + 0x0400) / 0x0800)
+	rData.RangeMM = v.rangeGainCorrect(rangeVal)
 
-	switch v.results.rangeStatus {
+	// only the lower 5 bits hold the status, as per
+	// VL53L1_RANGE_STATUS__RANGE_STATUS_MASK
+	rData.DeviceError = DeviceError(v.results.rangeStatus & 0x1F)
+	rData.ReportStatus = v.results.reportStatus
 
-	case 17, 2, 1, 3:
+	switch rData.DeviceError {
+
+	case DeviceErrorMultClipFail, DeviceErrorVCSELWatchdogTestFailure,
+		DeviceErrorVCSELContinuityTestFailure, DeviceErrorNoVHVValueFound:
 		rData.RangeStatus = HardwareFail
-	case 13:
+	case DeviceErrorUserROIClip:
 		rData.RangeStatus = MinRangeFail
-	case 18:
+	case DeviceErrorGPHStreamCount0Ready:
 		rData.RangeStatus = SynchronizationInt
-	case 5:
+	case DeviceErrorRangePhaseCheck:
 		rData.RangeStatus = OutOfBoundsFail
-	case 4:
+	case DeviceErrorMSRCNoTarget:
 		rData.RangeStatus = SignalFail
-	case 6:
+	case DeviceErrorSigmaThresholdCheck:
 		rData.RangeStatus = SigmaFail
-	case 7:
+	case DeviceErrorPhaseConsistency:
 		rData.RangeStatus = WrapTargetFail
-	case 12:
+	case DeviceErrorRangeIgnoreThreshold:
 		rData.RangeStatus = XtalkSignalFail
-	case 8:
+	case DeviceErrorMinClip:
 		rData.RangeStatus = RangeValidMinRangeClipped
-	case 9:
+	case DeviceErrorRangeComplete:
 		if v.results.streamCount == 0 {
 			rData.RangeStatus = RangeValidNoWrapCheckFail
 		} else {
@@ -381,12 +790,72 @@ func (v *VL53L1X) getRangingData() RangingData {
 	}
 
 	// from SetSimpleData()
-	rData.PeakSignalCountRateMCPS = v.countRateFixedToFloat(v.results.peakSignalCountRateCrosstalkCorrectedMCPS_SD0)
-	rData.AmbientCountRateMCPS = v.countRateFixedToFloat(v.results.ambientCountRateMCPS_SD0)
+	rData.Raw = RawRates{
+		PeakSignalCountRate: v.results.peakSignalCountRateCrosstalkCorrectedMCPS_SD0,
+		AmbientCountRate:    v.results.ambientCountRateMCPS_SD0,
+		Sigma:               v.results.sigmaSD0,
+	}
+
+	if !v.integerResults {
+		rData.PeakSignalCountRateMCPS = v.countRateFixedToFloat(rData.Raw.PeakSignalCountRate)
+		rData.AmbientCountRateMCPS = v.countRateFixedToFloat(rData.Raw.AmbientCountRate)
+		rData.SigmaMM = float32(rData.Raw.Sigma) / 4
+	}
+
+	if v.implausible(rData) {
+		rData.RangeStatus = ImplausibleData
+	}
+
+	if v.readSD1 {
+		sd1 := &rData.SD1
+
+		sd1.RangeMM = v.rangeGainCorrect(v.results.finalCrosstalkCorrectedRangeMM_SD1)
+		sd1.Raw = RawRates{
+			PeakSignalCountRate: v.results.peakSignalCountRateMCPS_SD1,
+			AmbientCountRate:    v.results.ambientCountRateMCPS_SD1,
+			Sigma:               v.results.sigmaSD1,
+		}
+
+		if !v.integerResults {
+			sd1.PeakSignalCountRateMCPS = v.countRateFixedToFloat(sd1.Raw.PeakSignalCountRate)
+			sd1.AmbientCountRateMCPS = v.countRateFixedToFloat(sd1.Raw.AmbientCountRate)
+			sd1.SigmaMM = float32(sd1.Raw.Sigma) / 4
+		}
+	}
+
+	if v.checkConsistency {
+		rData.Validity = v.validateResults(rData.RangeStatus)
+	}
+
+	rData.LostFrames = v.countLostFrames()
 
 	return rData
 }
 
+// implausible returns true if a measurement with a valid status has result
+// fields the sensor can not physically produce.  An all ones field is what
+// is read when the sensor stops driving the bus part way through a transfer
+func (v *VL53L1X) implausible(rData RangingData) bool {
+
+	switch rData.RangeStatus {
+	case RangeValid, RangeValidMinRangeClipped, RangeValidNoWrapCheckFail:
+	default:
+		return false
+	}
+
+	return rData.RangeMM > maxRangeLimitMM ||
+		v.results.finalCrosstalkCorrectedRangeMM_SD0 == 0xFFFF ||
+		v.results.peakSignalCountRateCrosstalkCorrectedMCPS_SD0 == 0xFFFF ||
+		v.results.ambientCountRateMCPS_SD0 == 0xFFFF ||
+		v.results.dssActualEffectiveSpadsSD0 == 0xFFFF
+}
+
+// rangeGainCorrect applies a gain correction to a raw range value:
+// (r * 2011 + 0x0400) / 0x0800
+func (v *VL53L1X) rangeGainCorrect(rangeVal uint16) uint16 {
+	return uint16((uint32(rangeVal)*2011 + 0x0400) / 0x0800)
+}
+
 // countRateFixedToFloat converts count rate from fixed point 9.7 format to float
 func (v *VL53L1X) countRateFixedToFloat(countRateFixed uint16) float32 {
 	return float32(countRateFixed) / float32(1<<7)
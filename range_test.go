@@ -0,0 +1,76 @@
+package vl53l1x
+
+import (
+	"io"
+	"log"
+	"testing"
+)
+
+// resultBus is a Bus returning a fixed result block to every read
+type resultBus struct {
+	result []byte
+}
+
+func (b *resultBus) GetAddr() uint8                     { return Address }
+func (b *resultBus) GetDev() string                     { return "fuzz" }
+func (b *resultBus) WriteBytes(buf []byte) (int, error) { return len(buf), nil }
+func (b *resultBus) Close() error                       { return nil }
+
+func (b *resultBus) ReadBytes(buf []byte) (int, error) {
+	return copy(buf, b.result), nil
+}
+
+func FuzzGetRangingData(f *testing.F) {
+
+	f.Add(make([]byte, resultBufferSize), false, false)
+	f.Add(make([]byte, resultBufferSizeSD1), true, false)
+	f.Add([]byte{0x09, 0, 5, 0xE0, 0, 0x08, 0, 0, 0x10, 0, 0x20, 0x01, 0x00, 0x01, 0xF4, 0x08, 0}, false, true)
+
+	for _, b := range [][]byte{make([]byte, resultBufferSize), make([]byte, resultBufferSizeSD1)} {
+		for i := range b {
+			b[i] = 0xFF
+		}
+
+		f.Add(b, len(b) == resultBufferSizeSD1, false)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte, sd1, integer bool) {
+
+		size := resultBufferSize
+
+		if sd1 {
+			size = resultBufferSizeSD1
+		}
+
+		result := make([]byte, size)
+		copy(result, data)
+
+		// the sensor is not initialized, only the result decoding is run
+		v, err := new(&resultBus{result: result}, Short, 20,
+			WithConsistencyCheck(), WithFrameLoss(), WithCycleTiming(),
+			WithLogger(log.New(io.Discard, "", 0)))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		v.readSD1 = sd1
+		v.integerResults = integer
+		v.fastOscFrequency = 0xB2E4
+		v.oscCalibrateVal = 0x03F0
+
+		if err := v.readResults(); err != nil {
+			t.Fatal(err)
+		}
+
+		rData := v.getRangingData()
+
+		if rData.RangeStatus.IsValid() && rData.RangeMM > maxRangeLimitMM {
+			t.Errorf("%dmm reported as %s", rData.RangeMM, rData.RangeStatus)
+		}
+
+		if rData.RangeStatus.IsValid() && rData.Validity&BusFloat != 0 {
+			t.Errorf("floating bus reported as %s", rData.RangeStatus)
+		}
+	})
+}
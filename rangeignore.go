@@ -0,0 +1,61 @@
+package vl53l1x
+
+import (
+	"fmt"
+	"math"
+)
+
+// DefaultRangeIgnoreMult is the multiple of the crosstalk rate used by ST's
+// full API for the range ignore threshold
+const DefaultRangeIgnoreMult float32 = 2
+
+// SetRangeIgnoreThreshold enables the range ignore check of the sensor with a
+// threshold of mult times the worst crosstalk rate of the compensation
+// plane, based on VL53L1_calc_range_ignore_threshold() from ST's full API.
+// Targets returning a signal rate below the threshold, such as near field
+// reflections from a cover glass, are reported as XtalkSignalFail rather
+// than as phantom short ranges.  Crosstalk compensation must be applied
+// first with CalibrateXtalk() or SetXtalkPlane(), and a mult of 0 disables
+// the check
+func (v *VL53L1X) SetRangeIgnoreThreshold(mult float32) error {
+
+	if mult < 0 {
+		return fmt.Errorf("range ignore multiple must not be negative")
+	}
+
+	plane, err := v.GetXtalkPlane()
+
+	if err != nil {
+		return err
+	}
+
+	// the worst case crosstalk is at the corner of the array 8 SPADs from
+	// the center in each direction
+	xGrad := math.Abs(float64(plane.XGradientKCPS)) / 2048
+	yGrad := math.Abs(float64(plane.YGradientKCPS)) / 2048
+	rateKCPS := float64(plane.OffsetKCPS)/512 + 8*(xGrad+yGrad)
+
+	// the register holds the threshold in MCPS in 3.13 fixed point
+	thresh := min(math.Round(float64(mult)*rateKCPS/1000*8192), 0xFFFF)
+
+	if err := v.writeReg16Bit(ALGO_RANGE_IGNORE_THRESHOLD_MCPS, uint16(thresh)); err != nil {
+		return err
+	}
+
+	v.configChanged("range ignore threshold")
+
+	return nil
+}
+
+// GetRangeIgnoreThreshold returns the threshold of the range ignore check in
+// MCPS per SPAD, 0 when it is disabled
+func (v *VL53L1X) GetRangeIgnoreThreshold() (float32, error) {
+
+	val, err := v.readReg16Bit(ALGO_RANGE_IGNORE_THRESHOLD_MCPS)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return float32(val) / 8192, nil
+}
@@ -0,0 +1,43 @@
+package vl53l1x_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+func TestSetRangeIgnoreThreshold(t *testing.T) {
+
+	v, bus := newSensor(t)
+
+	// 5 kcps offset with 0.5 kcps gradients gives 13 kcps at the corner of
+	// the array
+	plane := vl53l1x.XtalkPlane{OffsetKCPS: 5 * 512, XGradientKCPS: 1024, YGradientKCPS: -1024}
+
+	if err := v.SetXtalkPlane(plane); err != nil {
+		t.Fatalf("SetXtalkPlane: %v", err)
+	}
+
+	if err := v.SetRangeIgnoreThreshold(2); err != nil {
+		t.Fatalf("SetRangeIgnoreThreshold: %v", err)
+	}
+
+	// 26 kcps is 0.026 MCPS, in 3.13 fixed point
+	want := uint16(math.Round(0.026 * 8192))
+	buf := readReg(t, bus, vl53l1x.ALGO_RANGE_IGNORE_THRESHOLD_MCPS, 2)
+
+	if got := uint16(buf[0])<<8 | uint16(buf[1]); got != want {
+		t.Errorf("register = %d, want %d", got, want)
+	}
+
+	mcps, err := v.GetRangeIgnoreThreshold()
+
+	if err != nil {
+		t.Fatalf("GetRangeIgnoreThreshold: %v", err)
+	}
+
+	if math.Abs(float64(mcps)-0.026) > 1.0/8192 {
+		t.Errorf("GetRangeIgnoreThreshold() = %v MCPS, want 0.026", mcps)
+	}
+}
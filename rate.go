@@ -0,0 +1,193 @@
+package vl53l1x
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// rateGuardMs is the margin in milliseconds kept between the timing budget
+// and the inter-measurement period, as recommended on the ST community forum
+const rateGuardMs uint32 = 5
+
+// RateConfig configures a RateController
+type RateConfig struct {
+	// Hz is the target measurement frequency, the period is rounded to whole
+	// milliseconds
+	Hz float64
+	// Window is the number of intervals between reads the achieved rate and
+	// jitter are calculated over, defaults to 100
+	Window int
+	// Trim configures the correction holding the period against the host
+	// clock, used when the sensor was not created with WithPeriodTrim()
+	Trim PeriodTrimConfig
+}
+
+// RateStats holds the achieved measurement rate of a RateController
+type RateStats struct {
+	TargetHz   float64
+	AchievedHz float64
+	// PeriodMs and TimingBudget are the settings chosen for the target
+	PeriodMs     uint32
+	TimingBudget uint32
+	// Jitter is the standard deviation of the interval between reads and
+	// MaxJitter the largest deviation of an interval from their mean
+	Jitter    time.Duration
+	MaxJitter time.Duration
+	// Samples is the number of intervals the statistics cover
+	Samples int
+}
+
+// RateController runs continuous ranging at a target measurement frequency,
+// choosing the longest timing budget the period allows for the distance mode
+// and trimming the period for oscillator drift, and reports the achieved rate
+// and jitter of the reads made through it
+type RateController struct {
+	v        *VL53L1X
+	cfg      RateConfig
+	periodMs uint32
+	budget   uint32
+	// trimmed is set when Start installed the period trimmer
+	trimmed bool
+
+	last      time.Time
+	intervals []time.Duration
+	next      int
+}
+
+// NewRateController returns a RateController for the sensor at its current
+// distance mode.  An error is returned when the frequency can not be reached
+// with a legal timing budget
+func NewRateController(v *VL53L1X, cfg RateConfig) (*RateController, error) {
+
+	if cfg.Hz <= 0 {
+		return nil, fmt.Errorf("rate must be greater than 0Hz")
+	}
+
+	if cfg.Window <= 0 {
+		cfg.Window = 100
+	}
+
+	minBudget, err := v.MinBudgetForMode(v.distanceMode)
+
+	if err != nil {
+		return nil, err
+	}
+
+	periodMs := uint32(math.Round(1000 / cfg.Hz))
+
+	if periodMs < minBudget+rateGuardMs {
+		maxRate, _ := v.EffectiveMaxRate(v.distanceMode)
+		return nil, fmt.Errorf("%.1fHz exceeds maximum rate of %.1fHz for distance mode %s",
+			cfg.Hz, maxRate, v.distanceMode)
+	}
+
+	return &RateController{
+		v:         v,
+		cfg:       cfg,
+		periodMs:  periodMs,
+		budget:    min(periodMs-rateGuardMs, maxTimingBudget),
+		intervals: make([]time.Duration, 0, cfg.Window),
+	}, nil
+}
+
+// Start sets the timing budget and starts continuous ranging at the target
+// period, restarting the rate statistics
+func (r *RateController) Start() error {
+
+	if r.v.timingBudget != r.budget {
+		if err := r.v.SetMeasurementTimingBudget(r.budget); err != nil {
+			return err
+		}
+	}
+
+	if r.v.trim == nil {
+		r.v.trim = newPeriodTrimmer(r.cfg.Trim)
+		r.trimmed = true
+	}
+
+	r.last = time.Time{}
+	r.intervals, r.next = r.intervals[:0], 0
+
+	return r.v.StartContinuous(r.periodMs)
+}
+
+// Stop stops continuous ranging and removes the period trimming installed by
+// Start(), leaving that of WithPeriodTrim() in place
+func (r *RateController) Stop() error {
+
+	if r.trimmed {
+		r.v.trim = nil
+		r.trimmed = false
+	}
+
+	return r.v.StopContinuous()
+}
+
+// Read waits for and returns the next measurement as ReadContext() does,
+// recording the interval since the previous read
+func (r *RateController) Read(ctx context.Context) (RangingData, error) {
+
+	rData, err := r.v.ReadContext(ctx)
+
+	var merr *MeasurementError
+
+	// a measurement rejected by WithStrictStatus() still arrived on time
+	if err != nil && !errors.As(err, &merr) {
+		return rData, err
+	}
+
+	now := time.Now()
+
+	if !r.last.IsZero() {
+		if len(r.intervals) < r.cfg.Window {
+			r.intervals = append(r.intervals, now.Sub(r.last))
+		} else {
+			r.intervals[r.next] = now.Sub(r.last)
+			r.next = (r.next + 1) % r.cfg.Window
+		}
+	}
+
+	r.last = now
+
+	return rData, err
+}
+
+// Stats returns the achieved rate and jitter over the last Window intervals
+func (r *RateController) Stats() RateStats {
+
+	s := RateStats{
+		TargetHz:     r.cfg.Hz,
+		PeriodMs:     r.periodMs,
+		TimingBudget: r.budget,
+		Samples:      len(r.intervals),
+	}
+
+	if s.Samples == 0 {
+		return s
+	}
+
+	var sum float64
+
+	for _, d := range r.intervals {
+		sum += float64(d)
+	}
+
+	mean := sum / float64(s.Samples)
+
+	var sq, worst float64
+
+	for _, d := range r.intervals {
+		dev := float64(d) - mean
+		sq += dev * dev
+		worst = max(worst, math.Abs(dev))
+	}
+
+	s.AchievedHz = float64(time.Second) / mean
+	s.Jitter = time.Duration(math.Sqrt(sq / float64(s.Samples)))
+	s.MaxJitter = time.Duration(worst)
+
+	return s
+}
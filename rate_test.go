@@ -0,0 +1,54 @@
+package vl53l1x
+
+import (
+	"io"
+	"log"
+	"testing"
+)
+
+func TestRateControllerStopRemovesTrim(t *testing.T) {
+
+	for _, tc := range []struct {
+		name string
+		opts []Option
+		want bool
+	}{
+		{"installed by Start", nil, false},
+		{"WithPeriodTrim", []Option{WithPeriodTrim(PeriodTrimConfig{})}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+
+			opts := append([]Option{WithLogger(log.New(io.Discard, "", 0))}, tc.opts...)
+			v, err := new(&resultBus{result: make([]byte, resultBufferSize)}, Short, 20, opts...)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			v.fastOscFrequency = 0xB2E4
+			v.oscCalibrateVal = 0x03F0
+
+			r, err := NewRateController(v, RateConfig{Hz: 20})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := r.Start(); err != nil {
+				t.Fatal(err)
+			}
+
+			if v.trim == nil {
+				t.Fatal("Start did not install period trimming")
+			}
+
+			if err := r.Stop(); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := v.trim != nil; got != tc.want {
+				t.Errorf("trimming after Stop = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package vl53l1x_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+func TestReadAllocations(t *testing.T) {
+
+	v, _ := newSensor(t)
+
+	if err := v.StartContinuous(25); err != nil {
+		t.Fatal(err)
+	}
+
+	defer v.StopContinuous()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reads := []struct {
+		name string
+		read func() (vl53l1x.RangingData, error)
+	}{
+		{"Read(false)", func() (vl53l1x.RangingData, error) { return v.Read(false) }},
+		{"Read(true)", func() (vl53l1x.RangingData, error) { return v.Read(true) }},
+		{"ReadContext", func() (vl53l1x.RangingData, error) { return v.ReadContext(ctx) }},
+	}
+
+	for _, r := range reads {
+		allocs := testing.AllocsPerRun(10, func() {
+			if _, err := r.read(); err != nil {
+				t.Fatal(err)
+			}
+		})
+
+		if allocs > 0 {
+			t.Errorf("%s made %.0f allocations", r.name, allocs)
+		}
+	}
+}
+
+// BenchmarkRead measures the driver's read path, reading the result registers
+// without waiting for a new measurement
+func BenchmarkRead(b *testing.B) {
+
+	v, _ := newSensor(b)
+
+	if err := v.StartContinuous(25); err != nil {
+		b.Fatal(err)
+	}
+
+	defer v.StopContinuous()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := v.Read(false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkReadContinuous measures the reads per second achieved by blocking
+// reads at the timing budgets and periods of the README's measurement rate
+// table
+func BenchmarkReadContinuous(b *testing.B) {
+
+	for _, budget := range []uint32{20, 33, 50, 100, 200, 500} {
+		b.Run(fmt.Sprintf("budget=%dms", budget), func(b *testing.B) {
+
+			v, _ := newSensor(b)
+			v.SetTimeout(time.Duration(2*budget) * time.Millisecond)
+
+			if err := v.SetMeasurementTimingBudget(budget); err != nil {
+				b.Fatal(err)
+			}
+
+			if err := v.StartContinuous(budget + 5); err != nil {
+				b.Fatal(err)
+			}
+
+			defer v.StopContinuous()
+
+			// wait for the first measurement so the rate is steady
+			if _, err := v.Read(true); err != nil {
+				b.Fatal(err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				if _, err := v.Read(true); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "reads/s")
+		})
+	}
+}
@@ -1,159 +1,263 @@
 package vl53l1x
 
-import "fmt"
+import (
+	"fmt"
+	"slices"
+)
+
+//go:generate go run ./internal/regmap
+
+// Access is whether a register can be read and written
+type Access uint8
 
 const (
-	// Basic registers
-	SOFT_RESET uint16 = 0x0000
-
-	// I2C address configuration
-	I2C_SLAVE_DEVICE_ADDRESS uint16 = 0x0001
-
-	// Identification and status registers
-	IDENTIFICATION_MODEL_ID uint16 = 0x010F
-	FIRMWARE_SYSTEM_STATUS  uint16 = 0x00E5
-
-	// Oscillator and calibration registers
-	OSC_MEASURED_FAST_OSC_FREQUENCY uint16 = 0x0006
-	RESULT_OSC_CALIBRATE_VAL        uint16 = 0x00DE
-
-	// DSS (Dynamic SPAD Selection) and related
-	DSS_CONFIG_TARGET_TOTAL_RATE_MCPS        uint16 = 0x0024
-	DSS_CONFIG_MANUAL_EFFECTIVE_SPADS_SELECT uint16 = 0x0054
-	DSS_CONFIG_ROI_MODE_CONTROL              uint16 = 0x004F
-	DSS_CONFIG_APERTURE_ATTENUATION          uint16 = 0x0057
-
-	// SD (Single Detector) configuration registers for ROI
-	SD_CONFIG_WOI_SD0           uint16 = 0x0078
-	SD_CONFIG_WOI_SD1           uint16 = 0x0079
-	SD_CONFIG_INITIAL_PHASE_SD0 uint16 = 0x007A
-	SD_CONFIG_INITIAL_PHASE_SD1 uint16 = 0x007B
-
-	// I/O voltage selection register
-	PAD_I2C_HV_EXTSUP_CONFIG uint16 = 0x002E
-
-	// GPIO status
-	GPIO_TIO_HV_STATUS uint16 = 0x0031
-
-	// Sigma estimator parameters
-	SIGMA_EST_EFFECTIVE_PULSE_WIDTH_NS   uint16 = 0x0036
-	SIGMA_EST_EFFECTIVE_AMBIENT_WIDTH_NS uint16 = 0x0037
-
-	// Algorithm parameters
-	ALGO_CROSSTALK_COMP_VALID_HEIGHT_MM uint16 = 0x0039
-	ALGO_RANGE_IGNORE_VALID_HEIGHT_MM   uint16 = 0x003E
-	ALGO_RANGE_MIN_CLIP                 uint16 = 0x003F
-	ALGO_CONSISTENCY_CHECK_TOLERANCE    uint16 = 0x0040
-
-	// Timing thresholds
-	SYSTEM_THRESH_RATE_HIGH uint16 = 0x0050
-	SYSTEM_THRESH_RATE_LOW  uint16 = 0x0052
-
-	// Range configuration
-	RANGE_CONFIG_SIGMA_THRESH                  uint16 = 0x0064
-	RANGE_CONFIG_MIN_COUNT_RATE_RTN_LIMIT_MCPS uint16 = 0x0066
-	RANGE_CONFIG_VCSEL_PERIOD_A                uint16 = 0x0060
-	RANGE_CONFIG_VCSEL_PERIOD_B                uint16 = 0x0063
-	// For short/medium/long modes – these values determine phase validity.
-	RANGE_CONFIG_VALID_PHASE_HIGH uint16 = 0x0069 // approximate
-
-	// Grouped parameters, seed and sequence config
-	SYSTEM_GROUPED_PARAMETER_HOLD_0 uint16 = 0x0071
-	SYSTEM_GROUPED_PARAMETER_HOLD_1 uint16 = 0x007C
-	SD_CONFIG_QUANTIFIER            uint16 = 0x007E
-	SYSTEM_GROUPED_PARAMETER_HOLD   uint16 = 0x0082
-	SYSTEM_SEED_CONFIG              uint16 = 0x0077
-	SYSTEM_SEQUENCE_CONFIG          uint16 = 0x0081
-
-	// ROI (region of interest) registers
-	ROI_CONFIG_USER_ROI_CENTRE_SPAD              uint16 = 0x007F
-	ROI_CONFIG_USER_ROI_REQUESTED_GLOBAL_XY_SIZE uint16 = 0x0080
-
-	// Timing timeout registers
-	MM_CONFIG_OUTER_OFFSET_MM      uint16 = 0x0022
-	PHASECAL_CONFIG_TIMEOUT_MACROP uint16 = 0x004B
-	MM_CONFIG_TIMEOUT_MACROP_A     uint16 = 0x005A
-	RANGE_CONFIG_TIMEOUT_MACROP_A  uint16 = 0x005E
-	MM_CONFIG_TIMEOUT_MACROP_B     uint16 = 0x005C
-	RANGE_CONFIG_TIMEOUT_MACROP_B  uint16 = 0x0061
-
-	// Calibration and override registers
-	PHASECAL_CONFIG_OVERRIDE uint16 = 0x004D
-	CAL_CONFIG_VCSEL_START   uint16 = 0x0047
-
-	// VHV configuration registers (for low‑power auto mode)
-	VHV_CONFIG_INIT                      uint16 = 0x000B
-	VHV_CONFIG_TIMEOUT_MACROP_LOOP_BOUND uint16 = 0x0008
-	PHASECAL_RESULT_VCSEL_START          uint16 = 0x00D8
-
-	// Interrupt and mode registers
-	SYSTEM_INTERRUPT_CLEAR         uint16 = 0x0086
-	SYSTEM_MODE_START              uint16 = 0x0087
-	SYSTEM_INTERMEASUREMENT_PERIOD uint16 = 0x006C
-
-	// Result registers – reading range, etc.
-	RESULT_RANGE_STATUS uint16 = 0x0089
-
-	// Algorithm part-to-part range offset
-	ALGO_PART_TO_PART_RANGE_OFFSET_MM uint16 = 0x001E
+	AccessReadWrite Access = iota
+	AccessReadOnly
+	AccessWriteOnly
 )
 
+// String returns the access as in registers.csv
+func (a Access) String() string {
+	switch a {
+	case AccessReadWrite:
+		return "rw"
+	case AccessReadOnly:
+		return "ro"
+	case AccessWriteOnly:
+		return "wo"
+	default:
+		return fmt.Sprintf("Access(%d)", uint8(a))
+	}
+}
+
+// RegisterInfo describes a register of the register map
+type RegisterInfo struct {
+	Address uint16
+	// Name is the name of the constant and STName the name used in ST's
+	// documentation and drivers
+	Name   string
+	STName string
+	// Width is the size of the register in bytes
+	Width  int
+	Access Access
+}
+
+// registerIndex maps register addresses to their position in registerMap
+var registerIndex = func() map[uint16]int {
+
+	idx := make(map[uint16]int, len(registerMap))
+
+	for i, r := range registerMap {
+		idx[r.Address] = i
+	}
+
+	return idx
+}()
+
+// Registers returns the register map ordered by address
+func Registers() []RegisterInfo {
+	return slices.Clone(registerMap)
+}
+
+// LookupRegister returns the description of the register at reg
+func LookupRegister(reg uint16) (RegisterInfo, bool) {
+
+	i, ok := registerIndex[reg]
+
+	if !ok {
+		return RegisterInfo{}, false
+	}
+
+	return registerMap[i], true
+}
+
+// RegisterName returns the name of the register at reg, such as
+// RANGE_CONFIG_TIMEOUT_MACROP_A, or its address in hex when it is not in the
+// register map
+func RegisterName(reg uint16) string {
+
+	if i, ok := registerIndex[reg]; ok {
+		return registerMap[i].Name
+	}
+
+	return fmt.Sprintf("0x%04X", reg)
+}
+
 // writeReg writes a 8 bit value to the register
 func (v *VL53L1X) writeReg(reg uint16, value uint8) error {
 
-	buf := []byte{byte(reg >> 8), byte(reg), value}
+	buf := v.wbuf[:3]
+	buf[0], buf[1], buf[2] = byte(reg>>8), byte(reg), value
+
+	v.stats.regWrites.Add(1)
 
-	if _, err := v.bus.WriteBytes(buf); err != nil {
+	if err := v.busWrite(buf); err != nil {
 		return err
 	}
 
-	v.lastStatus = 0
-	return nil
+	v.logWrite(reg, 1, uint32(value))
+
+	return v.recordWrite(reg, 1, uint32(value))
 }
 
 // writeReg16Bit writes a 16 bit value to the register
 func (v *VL53L1X) writeReg16Bit(reg uint16, value uint16) error {
 
-	buf := []byte{byte(reg >> 8), byte(reg), byte(value >> 8), byte(value)}
+	buf := v.wbuf[:4]
+	buf[0], buf[1] = byte(reg>>8), byte(reg)
+	buf[2], buf[3] = byte(value>>8), byte(value)
+
+	v.stats.regWrites.Add(1)
 
-	if _, err := v.bus.WriteBytes(buf); err != nil {
+	if err := v.busWrite(buf); err != nil {
 		return err
 	}
 
-	v.lastStatus = 0
-	return nil
+	v.logWrite(reg, 2, uint32(value))
+
+	return v.recordWrite(reg, 2, uint32(value))
 }
 
 // writeReg32Bit writes a 32 bit value to the register
 func (v *VL53L1X) writeReg32Bit(reg uint16, value uint32) error {
 
-	buf := []byte{
-		byte(reg >> 8), byte(reg),
-		byte(value >> 24), byte(value >> 16),
-		byte(value >> 8), byte(value),
-	}
+	buf := v.wbuf[:6]
+	buf[0], buf[1] = byte(reg>>8), byte(reg)
+	buf[2], buf[3] = byte(value>>24), byte(value>>16)
+	buf[4], buf[5] = byte(value>>8), byte(value)
 
-	if _, err := v.bus.WriteBytes(buf); err != nil {
+	v.stats.regWrites.Add(1)
+
+	if err := v.busWrite(buf); err != nil {
 		return err
 	}
 
-	v.lastStatus = 0
+	v.logWrite(reg, 4, value)
+
+	return v.recordWrite(reg, 4, value)
+}
+
+// writeRegSized writes a value to a register of size 1, 2 or 4 bytes
+func (v *VL53L1X) writeRegSized(reg uint16, size int, value uint32) error {
+
+	switch size {
+	case 1:
+		return v.writeReg(reg, uint8(value))
+	case 2:
+		return v.writeReg16Bit(reg, uint16(value))
+	default:
+		return v.writeReg32Bit(reg, value)
+	}
+}
+
+// writeRegBytes writes data to consecutive registers starting at reg.  If a
+// maximum transfer size is set the write is split into chunks, each starting
+// at its own register address
+func (v *VL53L1X) writeRegBytes(reg uint16, data []byte) error {
+
+	chunk := len(data)
+
+	if v.maxTransfer > 0 && v.maxTransfer < chunk {
+		chunk = v.maxTransfer
+	}
+
+	buf := make([]byte, 2+chunk)
+
+	for total := 0; total < len(data); total += chunk {
+
+		end := min(total+chunk, len(data))
+		start := reg + uint16(total)
+		buf[0], buf[1] = byte(start>>8), byte(start)
+		n := copy(buf[2:], data[total:end])
+
+		v.stats.regWrites.Add(1)
+
+		if err := v.busWrite(buf[:2+n]); err != nil {
+			return err
+		}
+	}
+
+	v.logBytes("write", reg, data)
+
+	// keep the values of the verified registers in the block
+	for _, r := range registerMap {
+		off := int(r.Address) - int(reg)
+
+		if off < 0 || off+r.Width > len(data) || !verifiedRegisters[r.Address] {
+			continue
+		}
+
+		var value uint32
+
+		for _, b := range data[off : off+r.Width] {
+			value = value<<8 | uint32(b)
+		}
+
+		if err := v.recordWrite(r.Address, r.Width, value); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-// readReg reads an 8-bit value from a 16-bit register.
-func (v *VL53L1X) readReg(reg uint16) (uint8, error) {
+// readRegBytes writes the 16-bit register address then reads len(buf) bytes
+// from the sensor into buf.  The address is staged in the instance write
+// buffer so no allocation is made per transaction.  If a maximum transfer
+// size is set the read is split into chunks, each starting at its own register
+// address as the sensor auto-increments the address within a transfer
+func (v *VL53L1X) readRegBytes(reg uint16, buf []byte) (int, error) {
 
-	// Write the register address.
-	addr := []byte{byte(reg >> 8), byte(reg)}
+	v.stats.regReads.Add(1)
 
-	if _, err := v.bus.WriteBytes(addr); err != nil {
-		return 0, err
+	chunk := len(buf)
+
+	if v.maxTransfer > 0 && v.maxTransfer < chunk {
+		chunk = v.maxTransfer
 	}
 
-	// Read one byte.
-	buf := make([]byte, 1)
-	n, err := v.bus.ReadBytes(buf)
+	total := 0
+
+	for total < len(buf) {
+
+		end := total + chunk
+
+		if end > len(buf) {
+			end = len(buf)
+		}
+
+		addr := v.wbuf[:2]
+		start := reg + uint16(total)
+		addr[0], addr[1] = byte(start>>8), byte(start)
+
+		if err := v.busWrite(addr); err != nil {
+			return total, err
+		}
+
+		want := end - total
+		n, err := v.busRead(buf[total:end])
+		total += n
+
+		if err != nil {
+			return total, err
+		}
+
+		if n < want {
+			// short read, let caller report insufficient data
+			return total, nil
+		}
+	}
+
+	v.logBytes("read", reg, buf)
+
+	return total, nil
+}
+
+// readReg reads an 8-bit value from a 16-bit register.
+func (v *VL53L1X) readReg(reg uint16) (uint8, error) {
+
+	buf := v.rbuf[:1]
+	n, err := v.readRegBytes(reg, buf)
 
 	if err != nil {
 		return 0, err
@@ -169,14 +273,8 @@ func (v *VL53L1X) readReg(reg uint16) (uint8, error) {
 // readReg16Bit reads a 16-bit value from a 16-bit register.
 func (v *VL53L1X) readReg16Bit(reg uint16) (uint16, error) {
 
-	addr := []byte{byte(reg >> 8), byte(reg)}
-
-	if _, err := v.bus.WriteBytes(addr); err != nil {
-		return 0, err
-	}
-
-	buf := make([]byte, 2)
-	n, err := v.bus.ReadBytes(buf)
+	buf := v.rbuf[:2]
+	n, err := v.readRegBytes(reg, buf)
 
 	if err != nil {
 		return 0, err
@@ -192,14 +290,8 @@ func (v *VL53L1X) readReg16Bit(reg uint16) (uint16, error) {
 // readReg32Bit reads a 32-bit value from a 16-bit register.
 func (v *VL53L1X) readReg32Bit(reg uint16) (uint32, error) {
 
-	addr := []byte{byte(reg >> 8), byte(reg)}
-
-	if _, err := v.bus.WriteBytes(addr); err != nil {
-		return 0, err
-	}
-
-	buf := make([]byte, 4)
-	n, err := v.bus.ReadBytes(buf)
+	buf := v.rbuf[:4]
+	n, err := v.readRegBytes(reg, buf)
 
 	if err != nil {
 		return 0, err
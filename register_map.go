@@ -0,0 +1,354 @@
+// Code generated by go run ./internal/regmap; DO NOT EDIT.
+
+package vl53l1x
+
+// Register addresses, grouped as in ST's register map
+const (
+	// static_nvm_managed
+	SOFT_RESET                           uint16 = 0x0000
+	I2C_SLAVE_DEVICE_ADDRESS             uint16 = 0x0001
+	ANA_CONFIG_VHV_REF_SEL_VDDPIX        uint16 = 0x0002
+	ANA_CONFIG_VHV_REF_SEL_VQUENCH       uint16 = 0x0003
+	ANA_CONFIG_REG_AVDD1V2_SEL           uint16 = 0x0004
+	ANA_CONFIG_FAST_OSC_TRIM             uint16 = 0x0005
+	OSC_MEASURED_FAST_OSC_FREQUENCY      uint16 = 0x0006
+	VHV_CONFIG_TIMEOUT_MACROP_LOOP_BOUND uint16 = 0x0008
+	VHV_CONFIG_COUNT_THRESH              uint16 = 0x0009
+	VHV_CONFIG_OFFSET                    uint16 = 0x000A
+	VHV_CONFIG_INIT                      uint16 = 0x000B
+
+	// customer_nvm_managed
+	GLOBAL_CONFIG_SPAD_ENABLES_REF_0                  uint16 = 0x000D
+	GLOBAL_CONFIG_SPAD_ENABLES_REF_1                  uint16 = 0x000E
+	GLOBAL_CONFIG_SPAD_ENABLES_REF_2                  uint16 = 0x000F
+	GLOBAL_CONFIG_SPAD_ENABLES_REF_3                  uint16 = 0x0010
+	GLOBAL_CONFIG_SPAD_ENABLES_REF_4                  uint16 = 0x0011
+	GLOBAL_CONFIG_SPAD_ENABLES_REF_5                  uint16 = 0x0012
+	GLOBAL_CONFIG_REF_EN_START_SELECT                 uint16 = 0x0013
+	REF_SPAD_MAN_NUM_REQUESTED_REF_SPADS              uint16 = 0x0014
+	REF_SPAD_MAN_REF_LOCATION                         uint16 = 0x0015
+	ALGO_CROSSTALK_COMPENSATION_PLANE_OFFSET_KCPS     uint16 = 0x0016
+	ALGO_CROSSTALK_COMPENSATION_X_PLANE_GRADIENT_KCPS uint16 = 0x0018
+	ALGO_CROSSTALK_COMPENSATION_Y_PLANE_GRADIENT_KCPS uint16 = 0x001A
+	REF_SPAD_CHAR_TOTAL_RATE_TARGET_MCPS              uint16 = 0x001C
+	ALGO_PART_TO_PART_RANGE_OFFSET_MM                 uint16 = 0x001E
+	MM_CONFIG_INNER_OFFSET_MM                         uint16 = 0x0020
+	MM_CONFIG_OUTER_OFFSET_MM                         uint16 = 0x0022
+
+	// static_config
+	DSS_CONFIG_TARGET_TOTAL_RATE_MCPS       uint16 = 0x0024
+	DEBUG_CTRL                              uint16 = 0x0026
+	TEST_MODE_CTRL                          uint16 = 0x0027
+	CLK_GATING_CTRL                         uint16 = 0x0028
+	NVM_BIST_CTRL                           uint16 = 0x0029
+	NVM_BIST_NUM_NVM_WORDS                  uint16 = 0x002A
+	NVM_BIST_START_ADDRESS                  uint16 = 0x002B
+	HOST_IF_STATUS                          uint16 = 0x002C
+	PAD_I2C_HV_CONFIG                       uint16 = 0x002D
+	PAD_I2C_HV_EXTSUP_CONFIG                uint16 = 0x002E
+	GPIO_HV_PAD_CTRL                        uint16 = 0x002F
+	GPIO_HV_MUX_CTRL                        uint16 = 0x0030
+	GPIO_TIO_HV_STATUS                      uint16 = 0x0031
+	GPIO_FIO_HV_STATUS                      uint16 = 0x0032
+	ANA_CONFIG_SPAD_SEL_PSWIDTH             uint16 = 0x0033
+	ANA_CONFIG_VCSEL_PULSE_WIDTH_OFFSET     uint16 = 0x0034
+	ANA_CONFIG_FAST_OSC_CONFIG_CTRL         uint16 = 0x0035
+	SIGMA_EST_EFFECTIVE_PULSE_WIDTH_NS      uint16 = 0x0036
+	SIGMA_EST_EFFECTIVE_AMBIENT_WIDTH_NS    uint16 = 0x0037
+	SIGMA_EST_SIGMA_REF_MM                  uint16 = 0x0038
+	ALGO_CROSSTALK_COMP_VALID_HEIGHT_MM     uint16 = 0x0039
+	SPARE_HOST_CONFIG_STATIC_CONFIG_SPARE_0 uint16 = 0x003A
+	SPARE_HOST_CONFIG_STATIC_CONFIG_SPARE_1 uint16 = 0x003B
+	ALGO_RANGE_IGNORE_THRESHOLD_MCPS        uint16 = 0x003C
+	ALGO_RANGE_IGNORE_VALID_HEIGHT_MM       uint16 = 0x003E
+	ALGO_RANGE_MIN_CLIP                     uint16 = 0x003F
+	ALGO_CONSISTENCY_CHECK_TOLERANCE        uint16 = 0x0040
+	SPARE_HOST_CONFIG_STATIC_CONFIG_SPARE_2 uint16 = 0x0041
+	SD_CONFIG_RESET_STAGES_MSB              uint16 = 0x0042
+	SD_CONFIG_RESET_STAGES_LSB              uint16 = 0x0043
+	GPH_CONFIG_STREAM_COUNT_UPDATE_VALUE    uint16 = 0x0044
+	GLOBAL_CONFIG_STREAM_DIVIDER            uint16 = 0x0045
+
+	// general_config
+	SYSTEM_INTERRUPT_CONFIG_GPIO             uint16 = 0x0046
+	CAL_CONFIG_VCSEL_START                   uint16 = 0x0047
+	CAL_CONFIG_REPEAT_RATE                   uint16 = 0x0048
+	GLOBAL_CONFIG_VCSEL_WIDTH                uint16 = 0x004A
+	PHASECAL_CONFIG_TIMEOUT_MACROP           uint16 = 0x004B
+	PHASECAL_CONFIG_TARGET                   uint16 = 0x004C
+	PHASECAL_CONFIG_OVERRIDE                 uint16 = 0x004D
+	DSS_CONFIG_ROI_MODE_CONTROL              uint16 = 0x004F
+	SYSTEM_THRESH_RATE_HIGH                  uint16 = 0x0050
+	SYSTEM_THRESH_RATE_LOW                   uint16 = 0x0052
+	DSS_CONFIG_MANUAL_EFFECTIVE_SPADS_SELECT uint16 = 0x0054
+	DSS_CONFIG_MANUAL_BLOCK_SELECT           uint16 = 0x0056
+	DSS_CONFIG_APERTURE_ATTENUATION          uint16 = 0x0057
+	DSS_CONFIG_MAX_SPADS_LIMIT               uint16 = 0x0058
+	DSS_CONFIG_MIN_SPADS_LIMIT               uint16 = 0x0059
+
+	// timing_config
+	MM_CONFIG_TIMEOUT_MACROP_A                 uint16 = 0x005A
+	MM_CONFIG_TIMEOUT_MACROP_B                 uint16 = 0x005C
+	RANGE_CONFIG_TIMEOUT_MACROP_A              uint16 = 0x005E
+	RANGE_CONFIG_VCSEL_PERIOD_A                uint16 = 0x0060
+	RANGE_CONFIG_TIMEOUT_MACROP_B              uint16 = 0x0061
+	RANGE_CONFIG_VCSEL_PERIOD_B                uint16 = 0x0063
+	RANGE_CONFIG_SIGMA_THRESH                  uint16 = 0x0064
+	RANGE_CONFIG_MIN_COUNT_RATE_RTN_LIMIT_MCPS uint16 = 0x0066
+	RANGE_CONFIG_VALID_PHASE_LOW               uint16 = 0x0068
+	RANGE_CONFIG_VALID_PHASE_HIGH              uint16 = 0x0069
+	SYSTEM_INTERMEASUREMENT_PERIOD             uint16 = 0x006C
+
+	// dynamic_config
+	SYSTEM_FRACTIONAL_ENABLE                     uint16 = 0x0070
+	SYSTEM_GROUPED_PARAMETER_HOLD_0              uint16 = 0x0071
+	SYSTEM_THRESH_HIGH                           uint16 = 0x0072
+	SYSTEM_THRESH_LOW                            uint16 = 0x0074
+	SYSTEM_ENABLE_XTALK_PER_QUADRANT             uint16 = 0x0076
+	SYSTEM_SEED_CONFIG                           uint16 = 0x0077
+	SD_CONFIG_WOI_SD0                            uint16 = 0x0078
+	SD_CONFIG_WOI_SD1                            uint16 = 0x0079
+	SD_CONFIG_INITIAL_PHASE_SD0                  uint16 = 0x007A
+	SD_CONFIG_INITIAL_PHASE_SD1                  uint16 = 0x007B
+	SYSTEM_GROUPED_PARAMETER_HOLD_1              uint16 = 0x007C
+	SD_CONFIG_FIRST_ORDER_SELECT                 uint16 = 0x007D
+	SD_CONFIG_QUANTIFIER                         uint16 = 0x007E
+	ROI_CONFIG_USER_ROI_CENTRE_SPAD              uint16 = 0x007F
+	ROI_CONFIG_USER_ROI_REQUESTED_GLOBAL_XY_SIZE uint16 = 0x0080
+	SYSTEM_SEQUENCE_CONFIG                       uint16 = 0x0081
+	SYSTEM_GROUPED_PARAMETER_HOLD                uint16 = 0x0082
+
+	// system_control
+	POWER_MANAGEMENT_GO1_POWER_FORCE uint16 = 0x0083
+	SYSTEM_STREAM_COUNT_CTRL         uint16 = 0x0084
+	FIRMWARE_ENABLE                  uint16 = 0x0085
+	SYSTEM_INTERRUPT_CLEAR           uint16 = 0x0086
+	SYSTEM_MODE_START                uint16 = 0x0087
+
+	// system_results
+	RESULT_INTERRUPT_STATUS                                    uint16 = 0x0088
+	RESULT_RANGE_STATUS                                        uint16 = 0x0089
+	RESULT_REPORT_STATUS                                       uint16 = 0x008A
+	RESULT_STREAM_COUNT                                        uint16 = 0x008B
+	RESULT_DSS_ACTUAL_EFFECTIVE_SPADS_SD0                      uint16 = 0x008C
+	RESULT_PEAK_SIGNAL_COUNT_RATE_MCPS_SD0                     uint16 = 0x008E
+	RESULT_AMBIENT_COUNT_RATE_MCPS_SD0                         uint16 = 0x0090
+	RESULT_SIGMA_SD0                                           uint16 = 0x0092
+	RESULT_PHASE_SD0                                           uint16 = 0x0094
+	RESULT_FINAL_CROSSTALK_CORRECTED_RANGE_MM_SD0              uint16 = 0x0096
+	RESULT_PEAK_SIGNAL_COUNT_RATE_CROSSTALK_CORRECTED_MCPS_SD0 uint16 = 0x0098
+	RESULT_MM_INNER_ACTUAL_EFFECTIVE_SPADS_SD0                 uint16 = 0x009A
+	RESULT_MM_OUTER_ACTUAL_EFFECTIVE_SPADS_SD0                 uint16 = 0x009C
+	RESULT_AVG_SIGNAL_COUNT_RATE_MCPS_SD0                      uint16 = 0x009E
+	RESULT_DSS_ACTUAL_EFFECTIVE_SPADS_SD1                      uint16 = 0x00A0
+	RESULT_PEAK_SIGNAL_COUNT_RATE_MCPS_SD1                     uint16 = 0x00A2
+	RESULT_AMBIENT_COUNT_RATE_MCPS_SD1                         uint16 = 0x00A4
+	RESULT_SIGMA_SD1                                           uint16 = 0x00A6
+	RESULT_PHASE_SD1                                           uint16 = 0x00A8
+	RESULT_FINAL_CROSSTALK_CORRECTED_RANGE_MM_SD1              uint16 = 0x00AA
+	RESULT_SPARE_0_SD1                                         uint16 = 0x00AC
+	RESULT_SPARE_1_SD1                                         uint16 = 0x00AE
+	RESULT_SPARE_2_SD1                                         uint16 = 0x00B0
+	RESULT_SPARE_3_SD1                                         uint16 = 0x00B2
+	RESULT_THRESH_INFO                                         uint16 = 0x00B3
+
+	// core_results
+	RESULT_CORE_AMBIENT_WINDOW_EVENTS_SD0 uint16 = 0x00B4
+	RESULT_CORE_RANGING_TOTAL_EVENTS_SD0  uint16 = 0x00B8
+	RESULT_CORE_SIGNAL_TOTAL_EVENTS_SD0   uint16 = 0x00BC
+	RESULT_CORE_TOTAL_PERIODS_ELAPSED_SD0 uint16 = 0x00C0
+	RESULT_CORE_AMBIENT_WINDOW_EVENTS_SD1 uint16 = 0x00C4
+	RESULT_CORE_RANGING_TOTAL_EVENTS_SD1  uint16 = 0x00C8
+	RESULT_CORE_SIGNAL_TOTAL_EVENTS_SD1   uint16 = 0x00CC
+	RESULT_CORE_TOTAL_PERIODS_ELAPSED_SD1 uint16 = 0x00D0
+	RESULT_CORE_SPARE_0                   uint16 = 0x00D4
+
+	// debug_results
+	PHASECAL_RESULT_REFERENCE_PHASE           uint16 = 0x00D6
+	PHASECAL_RESULT_VCSEL_START               uint16 = 0x00D8
+	REF_SPAD_CHAR_RESULT_NUM_ACTUAL_REF_SPADS uint16 = 0x00D9
+	REF_SPAD_CHAR_RESULT_REF_LOCATION         uint16 = 0x00DA
+	VHV_RESULT_COLDBOOT_STATUS                uint16 = 0x00DB
+	VHV_RESULT_SEARCH_RESULT                  uint16 = 0x00DC
+	VHV_RESULT_LATEST_SETTING                 uint16 = 0x00DD
+	RESULT_OSC_CALIBRATE_VAL                  uint16 = 0x00DE
+	ANA_CONFIG_POWERDOWN_GO1                  uint16 = 0x00E0
+	ANA_CONFIG_REF_BG_CTRL                    uint16 = 0x00E1
+	ANA_CONFIG_REGDVDD1V2_CTRL                uint16 = 0x00E2
+	ANA_CONFIG_OSC_SLOW_CTRL                  uint16 = 0x00E3
+	TEST_MODE_STATUS                          uint16 = 0x00E4
+	FIRMWARE_SYSTEM_STATUS                    uint16 = 0x00E5
+	FIRMWARE_MODE_STATUS                      uint16 = 0x00E6
+	FIRMWARE_SECONDARY_MODE_STATUS            uint16 = 0x00E7
+	FIRMWARE_CAL_REPEAT_RATE_COUNTER          uint16 = 0x00E8
+
+	// identification
+	IDENTIFICATION_MODEL_ID    uint16 = 0x010F
+	IDENTIFICATION_MODULE_TYPE uint16 = 0x0110
+	IDENTIFICATION_REVISION_ID uint16 = 0x0111
+)
+
+// registerMap describes the registers declared above ordered by address
+var registerMap = []RegisterInfo{
+	{SOFT_RESET, "SOFT_RESET", "SOFT_RESET", 1, AccessReadWrite},
+	{I2C_SLAVE_DEVICE_ADDRESS, "I2C_SLAVE_DEVICE_ADDRESS", "I2C_SLAVE__DEVICE_ADDRESS", 1, AccessReadWrite},
+	{ANA_CONFIG_VHV_REF_SEL_VDDPIX, "ANA_CONFIG_VHV_REF_SEL_VDDPIX", "ANA_CONFIG__VHV_REF_SEL_VDDPIX", 1, AccessReadWrite},
+	{ANA_CONFIG_VHV_REF_SEL_VQUENCH, "ANA_CONFIG_VHV_REF_SEL_VQUENCH", "ANA_CONFIG__VHV_REF_SEL_VQUENCH", 1, AccessReadWrite},
+	{ANA_CONFIG_REG_AVDD1V2_SEL, "ANA_CONFIG_REG_AVDD1V2_SEL", "ANA_CONFIG__REG_AVDD1V2_SEL", 1, AccessReadWrite},
+	{ANA_CONFIG_FAST_OSC_TRIM, "ANA_CONFIG_FAST_OSC_TRIM", "ANA_CONFIG__FAST_OSC__TRIM", 1, AccessReadWrite},
+	{OSC_MEASURED_FAST_OSC_FREQUENCY, "OSC_MEASURED_FAST_OSC_FREQUENCY", "OSC_MEASURED__FAST_OSC__FREQUENCY", 2, AccessReadWrite},
+	{VHV_CONFIG_TIMEOUT_MACROP_LOOP_BOUND, "VHV_CONFIG_TIMEOUT_MACROP_LOOP_BOUND", "VHV_CONFIG__TIMEOUT_MACROP_LOOP_BOUND", 1, AccessReadWrite},
+	{VHV_CONFIG_COUNT_THRESH, "VHV_CONFIG_COUNT_THRESH", "VHV_CONFIG__COUNT_THRESH", 1, AccessReadWrite},
+	{VHV_CONFIG_OFFSET, "VHV_CONFIG_OFFSET", "VHV_CONFIG__OFFSET", 1, AccessReadWrite},
+	{VHV_CONFIG_INIT, "VHV_CONFIG_INIT", "VHV_CONFIG__INIT", 1, AccessReadWrite},
+	{GLOBAL_CONFIG_SPAD_ENABLES_REF_0, "GLOBAL_CONFIG_SPAD_ENABLES_REF_0", "GLOBAL_CONFIG__SPAD_ENABLES_REF_0", 1, AccessReadWrite},
+	{GLOBAL_CONFIG_SPAD_ENABLES_REF_1, "GLOBAL_CONFIG_SPAD_ENABLES_REF_1", "GLOBAL_CONFIG__SPAD_ENABLES_REF_1", 1, AccessReadWrite},
+	{GLOBAL_CONFIG_SPAD_ENABLES_REF_2, "GLOBAL_CONFIG_SPAD_ENABLES_REF_2", "GLOBAL_CONFIG__SPAD_ENABLES_REF_2", 1, AccessReadWrite},
+	{GLOBAL_CONFIG_SPAD_ENABLES_REF_3, "GLOBAL_CONFIG_SPAD_ENABLES_REF_3", "GLOBAL_CONFIG__SPAD_ENABLES_REF_3", 1, AccessReadWrite},
+	{GLOBAL_CONFIG_SPAD_ENABLES_REF_4, "GLOBAL_CONFIG_SPAD_ENABLES_REF_4", "GLOBAL_CONFIG__SPAD_ENABLES_REF_4", 1, AccessReadWrite},
+	{GLOBAL_CONFIG_SPAD_ENABLES_REF_5, "GLOBAL_CONFIG_SPAD_ENABLES_REF_5", "GLOBAL_CONFIG__SPAD_ENABLES_REF_5", 1, AccessReadWrite},
+	{GLOBAL_CONFIG_REF_EN_START_SELECT, "GLOBAL_CONFIG_REF_EN_START_SELECT", "GLOBAL_CONFIG__REF_EN_START_SELECT", 1, AccessReadWrite},
+	{REF_SPAD_MAN_NUM_REQUESTED_REF_SPADS, "REF_SPAD_MAN_NUM_REQUESTED_REF_SPADS", "REF_SPAD_MAN__NUM_REQUESTED_REF_SPADS", 1, AccessReadWrite},
+	{REF_SPAD_MAN_REF_LOCATION, "REF_SPAD_MAN_REF_LOCATION", "REF_SPAD_MAN__REF_LOCATION", 1, AccessReadWrite},
+	{ALGO_CROSSTALK_COMPENSATION_PLANE_OFFSET_KCPS, "ALGO_CROSSTALK_COMPENSATION_PLANE_OFFSET_KCPS", "ALGO__CROSSTALK_COMPENSATION_PLANE_OFFSET_KCPS", 2, AccessReadWrite},
+	{ALGO_CROSSTALK_COMPENSATION_X_PLANE_GRADIENT_KCPS, "ALGO_CROSSTALK_COMPENSATION_X_PLANE_GRADIENT_KCPS", "ALGO__CROSSTALK_COMPENSATION_X_PLANE_GRADIENT_KCPS", 2, AccessReadWrite},
+	{ALGO_CROSSTALK_COMPENSATION_Y_PLANE_GRADIENT_KCPS, "ALGO_CROSSTALK_COMPENSATION_Y_PLANE_GRADIENT_KCPS", "ALGO__CROSSTALK_COMPENSATION_Y_PLANE_GRADIENT_KCPS", 2, AccessReadWrite},
+	{REF_SPAD_CHAR_TOTAL_RATE_TARGET_MCPS, "REF_SPAD_CHAR_TOTAL_RATE_TARGET_MCPS", "REF_SPAD_CHAR__TOTAL_RATE_TARGET_MCPS", 2, AccessReadWrite},
+	{ALGO_PART_TO_PART_RANGE_OFFSET_MM, "ALGO_PART_TO_PART_RANGE_OFFSET_MM", "ALGO__PART_TO_PART_RANGE_OFFSET_MM", 2, AccessReadWrite},
+	{MM_CONFIG_INNER_OFFSET_MM, "MM_CONFIG_INNER_OFFSET_MM", "MM_CONFIG__INNER_OFFSET_MM", 2, AccessReadWrite},
+	{MM_CONFIG_OUTER_OFFSET_MM, "MM_CONFIG_OUTER_OFFSET_MM", "MM_CONFIG__OUTER_OFFSET_MM", 2, AccessReadWrite},
+	{DSS_CONFIG_TARGET_TOTAL_RATE_MCPS, "DSS_CONFIG_TARGET_TOTAL_RATE_MCPS", "DSS_CONFIG__TARGET_TOTAL_RATE_MCPS", 2, AccessReadWrite},
+	{DEBUG_CTRL, "DEBUG_CTRL", "DEBUG__CTRL", 1, AccessReadWrite},
+	{TEST_MODE_CTRL, "TEST_MODE_CTRL", "TEST_MODE__CTRL", 1, AccessReadWrite},
+	{CLK_GATING_CTRL, "CLK_GATING_CTRL", "CLK_GATING__CTRL", 1, AccessReadWrite},
+	{NVM_BIST_CTRL, "NVM_BIST_CTRL", "NVM_BIST__CTRL", 1, AccessReadWrite},
+	{NVM_BIST_NUM_NVM_WORDS, "NVM_BIST_NUM_NVM_WORDS", "NVM_BIST__NUM_NVM_WORDS", 1, AccessReadWrite},
+	{NVM_BIST_START_ADDRESS, "NVM_BIST_START_ADDRESS", "NVM_BIST__START_ADDRESS", 1, AccessReadWrite},
+	{HOST_IF_STATUS, "HOST_IF_STATUS", "HOST_IF__STATUS", 1, AccessReadOnly},
+	{PAD_I2C_HV_CONFIG, "PAD_I2C_HV_CONFIG", "PAD_I2C_HV__CONFIG", 1, AccessReadWrite},
+	{PAD_I2C_HV_EXTSUP_CONFIG, "PAD_I2C_HV_EXTSUP_CONFIG", "PAD_I2C_HV__EXTSUP_CONFIG", 1, AccessReadWrite},
+	{GPIO_HV_PAD_CTRL, "GPIO_HV_PAD_CTRL", "GPIO_HV_PAD__CTRL", 1, AccessReadWrite},
+	{GPIO_HV_MUX_CTRL, "GPIO_HV_MUX_CTRL", "GPIO_HV_MUX__CTRL", 1, AccessReadWrite},
+	{GPIO_TIO_HV_STATUS, "GPIO_TIO_HV_STATUS", "GPIO__TIO_HV_STATUS", 1, AccessReadWrite},
+	{GPIO_FIO_HV_STATUS, "GPIO_FIO_HV_STATUS", "GPIO__FIO_HV_STATUS", 1, AccessReadWrite},
+	{ANA_CONFIG_SPAD_SEL_PSWIDTH, "ANA_CONFIG_SPAD_SEL_PSWIDTH", "ANA_CONFIG__SPAD_SEL_PSWIDTH", 1, AccessReadWrite},
+	{ANA_CONFIG_VCSEL_PULSE_WIDTH_OFFSET, "ANA_CONFIG_VCSEL_PULSE_WIDTH_OFFSET", "ANA_CONFIG__VCSEL_PULSE_WIDTH_OFFSET", 1, AccessReadWrite},
+	{ANA_CONFIG_FAST_OSC_CONFIG_CTRL, "ANA_CONFIG_FAST_OSC_CONFIG_CTRL", "ANA_CONFIG__FAST_OSC__CONFIG_CTRL", 1, AccessReadWrite},
+	{SIGMA_EST_EFFECTIVE_PULSE_WIDTH_NS, "SIGMA_EST_EFFECTIVE_PULSE_WIDTH_NS", "SIGMA_ESTIMATOR__EFFECTIVE_PULSE_WIDTH_NS", 1, AccessReadWrite},
+	{SIGMA_EST_EFFECTIVE_AMBIENT_WIDTH_NS, "SIGMA_EST_EFFECTIVE_AMBIENT_WIDTH_NS", "SIGMA_ESTIMATOR__EFFECTIVE_AMBIENT_WIDTH_NS", 1, AccessReadWrite},
+	{SIGMA_EST_SIGMA_REF_MM, "SIGMA_EST_SIGMA_REF_MM", "SIGMA_ESTIMATOR__SIGMA_REF_MM", 1, AccessReadWrite},
+	{ALGO_CROSSTALK_COMP_VALID_HEIGHT_MM, "ALGO_CROSSTALK_COMP_VALID_HEIGHT_MM", "ALGO__CROSSTALK_COMPENSATION_VALID_HEIGHT_MM", 1, AccessReadWrite},
+	{SPARE_HOST_CONFIG_STATIC_CONFIG_SPARE_0, "SPARE_HOST_CONFIG_STATIC_CONFIG_SPARE_0", "SPARE_HOST_CONFIG__STATIC_CONFIG_SPARE_0", 1, AccessReadWrite},
+	{SPARE_HOST_CONFIG_STATIC_CONFIG_SPARE_1, "SPARE_HOST_CONFIG_STATIC_CONFIG_SPARE_1", "SPARE_HOST_CONFIG__STATIC_CONFIG_SPARE_1", 1, AccessReadWrite},
+	{ALGO_RANGE_IGNORE_THRESHOLD_MCPS, "ALGO_RANGE_IGNORE_THRESHOLD_MCPS", "ALGO__RANGE_IGNORE_THRESHOLD_MCPS", 2, AccessReadWrite},
+	{ALGO_RANGE_IGNORE_VALID_HEIGHT_MM, "ALGO_RANGE_IGNORE_VALID_HEIGHT_MM", "ALGO__RANGE_IGNORE_VALID_HEIGHT_MM", 1, AccessReadWrite},
+	{ALGO_RANGE_MIN_CLIP, "ALGO_RANGE_MIN_CLIP", "ALGO__RANGE_MIN_CLIP", 1, AccessReadWrite},
+	{ALGO_CONSISTENCY_CHECK_TOLERANCE, "ALGO_CONSISTENCY_CHECK_TOLERANCE", "ALGO__CONSISTENCY_CHECK__TOLERANCE", 1, AccessReadWrite},
+	{SPARE_HOST_CONFIG_STATIC_CONFIG_SPARE_2, "SPARE_HOST_CONFIG_STATIC_CONFIG_SPARE_2", "SPARE_HOST_CONFIG__STATIC_CONFIG_SPARE_2", 1, AccessReadWrite},
+	{SD_CONFIG_RESET_STAGES_MSB, "SD_CONFIG_RESET_STAGES_MSB", "SD_CONFIG__RESET_STAGES_MSB", 1, AccessReadWrite},
+	{SD_CONFIG_RESET_STAGES_LSB, "SD_CONFIG_RESET_STAGES_LSB", "SD_CONFIG__RESET_STAGES_LSB", 1, AccessReadWrite},
+	{GPH_CONFIG_STREAM_COUNT_UPDATE_VALUE, "GPH_CONFIG_STREAM_COUNT_UPDATE_VALUE", "GPH_CONFIG__STREAM_COUNT_UPDATE_VALUE", 1, AccessReadWrite},
+	{GLOBAL_CONFIG_STREAM_DIVIDER, "GLOBAL_CONFIG_STREAM_DIVIDER", "GLOBAL_CONFIG__STREAM_DIVIDER", 1, AccessReadWrite},
+	{SYSTEM_INTERRUPT_CONFIG_GPIO, "SYSTEM_INTERRUPT_CONFIG_GPIO", "SYSTEM__INTERRUPT_CONFIG_GPIO", 1, AccessReadWrite},
+	{CAL_CONFIG_VCSEL_START, "CAL_CONFIG_VCSEL_START", "CAL_CONFIG__VCSEL_START", 1, AccessReadWrite},
+	{CAL_CONFIG_REPEAT_RATE, "CAL_CONFIG_REPEAT_RATE", "CAL_CONFIG__REPEAT_RATE", 2, AccessReadWrite},
+	{GLOBAL_CONFIG_VCSEL_WIDTH, "GLOBAL_CONFIG_VCSEL_WIDTH", "GLOBAL_CONFIG__VCSEL_WIDTH", 1, AccessReadWrite},
+	{PHASECAL_CONFIG_TIMEOUT_MACROP, "PHASECAL_CONFIG_TIMEOUT_MACROP", "PHASECAL_CONFIG__TIMEOUT_MACROP", 1, AccessReadWrite},
+	{PHASECAL_CONFIG_TARGET, "PHASECAL_CONFIG_TARGET", "PHASECAL_CONFIG__TARGET", 1, AccessReadWrite},
+	{PHASECAL_CONFIG_OVERRIDE, "PHASECAL_CONFIG_OVERRIDE", "PHASECAL_CONFIG__OVERRIDE", 1, AccessReadWrite},
+	{DSS_CONFIG_ROI_MODE_CONTROL, "DSS_CONFIG_ROI_MODE_CONTROL", "DSS_CONFIG__ROI_MODE_CONTROL", 1, AccessReadWrite},
+	{SYSTEM_THRESH_RATE_HIGH, "SYSTEM_THRESH_RATE_HIGH", "SYSTEM__THRESH_RATE_HIGH", 2, AccessReadWrite},
+	{SYSTEM_THRESH_RATE_LOW, "SYSTEM_THRESH_RATE_LOW", "SYSTEM__THRESH_RATE_LOW", 2, AccessReadWrite},
+	{DSS_CONFIG_MANUAL_EFFECTIVE_SPADS_SELECT, "DSS_CONFIG_MANUAL_EFFECTIVE_SPADS_SELECT", "DSS_CONFIG__MANUAL_EFFECTIVE_SPADS_SELECT", 2, AccessReadWrite},
+	{DSS_CONFIG_MANUAL_BLOCK_SELECT, "DSS_CONFIG_MANUAL_BLOCK_SELECT", "DSS_CONFIG__MANUAL_BLOCK_SELECT", 1, AccessReadWrite},
+	{DSS_CONFIG_APERTURE_ATTENUATION, "DSS_CONFIG_APERTURE_ATTENUATION", "DSS_CONFIG__APERTURE_ATTENUATION", 1, AccessReadWrite},
+	{DSS_CONFIG_MAX_SPADS_LIMIT, "DSS_CONFIG_MAX_SPADS_LIMIT", "DSS_CONFIG__MAX_SPADS_LIMIT", 1, AccessReadWrite},
+	{DSS_CONFIG_MIN_SPADS_LIMIT, "DSS_CONFIG_MIN_SPADS_LIMIT", "DSS_CONFIG__MIN_SPADS_LIMIT", 1, AccessReadWrite},
+	{MM_CONFIG_TIMEOUT_MACROP_A, "MM_CONFIG_TIMEOUT_MACROP_A", "MM_CONFIG__TIMEOUT_MACROP_A", 2, AccessReadWrite},
+	{MM_CONFIG_TIMEOUT_MACROP_B, "MM_CONFIG_TIMEOUT_MACROP_B", "MM_CONFIG__TIMEOUT_MACROP_B", 2, AccessReadWrite},
+	{RANGE_CONFIG_TIMEOUT_MACROP_A, "RANGE_CONFIG_TIMEOUT_MACROP_A", "RANGE_CONFIG__TIMEOUT_MACROP_A", 2, AccessReadWrite},
+	{RANGE_CONFIG_VCSEL_PERIOD_A, "RANGE_CONFIG_VCSEL_PERIOD_A", "RANGE_CONFIG__VCSEL_PERIOD_A", 1, AccessReadWrite},
+	{RANGE_CONFIG_TIMEOUT_MACROP_B, "RANGE_CONFIG_TIMEOUT_MACROP_B", "RANGE_CONFIG__TIMEOUT_MACROP_B", 2, AccessReadWrite},
+	{RANGE_CONFIG_VCSEL_PERIOD_B, "RANGE_CONFIG_VCSEL_PERIOD_B", "RANGE_CONFIG__VCSEL_PERIOD_B", 1, AccessReadWrite},
+	{RANGE_CONFIG_SIGMA_THRESH, "RANGE_CONFIG_SIGMA_THRESH", "RANGE_CONFIG__SIGMA_THRESH", 2, AccessReadWrite},
+	{RANGE_CONFIG_MIN_COUNT_RATE_RTN_LIMIT_MCPS, "RANGE_CONFIG_MIN_COUNT_RATE_RTN_LIMIT_MCPS", "RANGE_CONFIG__MIN_COUNT_RATE_RTN_LIMIT_MCPS", 2, AccessReadWrite},
+	{RANGE_CONFIG_VALID_PHASE_LOW, "RANGE_CONFIG_VALID_PHASE_LOW", "RANGE_CONFIG__VALID_PHASE_LOW", 1, AccessReadWrite},
+	{RANGE_CONFIG_VALID_PHASE_HIGH, "RANGE_CONFIG_VALID_PHASE_HIGH", "RANGE_CONFIG__VALID_PHASE_HIGH", 1, AccessReadWrite},
+	{SYSTEM_INTERMEASUREMENT_PERIOD, "SYSTEM_INTERMEASUREMENT_PERIOD", "SYSTEM__INTERMEASUREMENT_PERIOD", 4, AccessReadWrite},
+	{SYSTEM_FRACTIONAL_ENABLE, "SYSTEM_FRACTIONAL_ENABLE", "SYSTEM__FRACTIONAL_ENABLE", 1, AccessReadWrite},
+	{SYSTEM_GROUPED_PARAMETER_HOLD_0, "SYSTEM_GROUPED_PARAMETER_HOLD_0", "SYSTEM__GROUPED_PARAMETER_HOLD_0", 1, AccessReadWrite},
+	{SYSTEM_THRESH_HIGH, "SYSTEM_THRESH_HIGH", "SYSTEM__THRESH_HIGH", 2, AccessReadWrite},
+	{SYSTEM_THRESH_LOW, "SYSTEM_THRESH_LOW", "SYSTEM__THRESH_LOW", 2, AccessReadWrite},
+	{SYSTEM_ENABLE_XTALK_PER_QUADRANT, "SYSTEM_ENABLE_XTALK_PER_QUADRANT", "SYSTEM__ENABLE_XTALK_PER_QUADRANT", 1, AccessReadWrite},
+	{SYSTEM_SEED_CONFIG, "SYSTEM_SEED_CONFIG", "SYSTEM__SEED_CONFIG", 1, AccessReadWrite},
+	{SD_CONFIG_WOI_SD0, "SD_CONFIG_WOI_SD0", "SD_CONFIG__WOI_SD0", 1, AccessReadWrite},
+	{SD_CONFIG_WOI_SD1, "SD_CONFIG_WOI_SD1", "SD_CONFIG__WOI_SD1", 1, AccessReadWrite},
+	{SD_CONFIG_INITIAL_PHASE_SD0, "SD_CONFIG_INITIAL_PHASE_SD0", "SD_CONFIG__INITIAL_PHASE_SD0", 1, AccessReadWrite},
+	{SD_CONFIG_INITIAL_PHASE_SD1, "SD_CONFIG_INITIAL_PHASE_SD1", "SD_CONFIG__INITIAL_PHASE_SD1", 1, AccessReadWrite},
+	{SYSTEM_GROUPED_PARAMETER_HOLD_1, "SYSTEM_GROUPED_PARAMETER_HOLD_1", "SYSTEM__GROUPED_PARAMETER_HOLD_1", 1, AccessReadWrite},
+	{SD_CONFIG_FIRST_ORDER_SELECT, "SD_CONFIG_FIRST_ORDER_SELECT", "SD_CONFIG__FIRST_ORDER_SELECT", 1, AccessReadWrite},
+	{SD_CONFIG_QUANTIFIER, "SD_CONFIG_QUANTIFIER", "SD_CONFIG__QUANTIFIER", 1, AccessReadWrite},
+	{ROI_CONFIG_USER_ROI_CENTRE_SPAD, "ROI_CONFIG_USER_ROI_CENTRE_SPAD", "ROI_CONFIG__USER_ROI_CENTRE_SPAD", 1, AccessReadWrite},
+	{ROI_CONFIG_USER_ROI_REQUESTED_GLOBAL_XY_SIZE, "ROI_CONFIG_USER_ROI_REQUESTED_GLOBAL_XY_SIZE", "ROI_CONFIG__USER_ROI_REQUESTED_GLOBAL_XY_SIZE", 1, AccessReadWrite},
+	{SYSTEM_SEQUENCE_CONFIG, "SYSTEM_SEQUENCE_CONFIG", "SYSTEM__SEQUENCE_CONFIG", 1, AccessReadWrite},
+	{SYSTEM_GROUPED_PARAMETER_HOLD, "SYSTEM_GROUPED_PARAMETER_HOLD", "SYSTEM__GROUPED_PARAMETER_HOLD", 1, AccessReadWrite},
+	{POWER_MANAGEMENT_GO1_POWER_FORCE, "POWER_MANAGEMENT_GO1_POWER_FORCE", "POWER_MANAGEMENT__GO1_POWER_FORCE", 1, AccessReadWrite},
+	{SYSTEM_STREAM_COUNT_CTRL, "SYSTEM_STREAM_COUNT_CTRL", "SYSTEM__STREAM_COUNT_CTRL", 1, AccessReadWrite},
+	{FIRMWARE_ENABLE, "FIRMWARE_ENABLE", "FIRMWARE__ENABLE", 1, AccessReadWrite},
+	{SYSTEM_INTERRUPT_CLEAR, "SYSTEM_INTERRUPT_CLEAR", "SYSTEM__INTERRUPT_CLEAR", 1, AccessWriteOnly},
+	{SYSTEM_MODE_START, "SYSTEM_MODE_START", "SYSTEM__MODE_START", 1, AccessWriteOnly},
+	{RESULT_INTERRUPT_STATUS, "RESULT_INTERRUPT_STATUS", "RESULT__INTERRUPT_STATUS", 1, AccessReadOnly},
+	{RESULT_RANGE_STATUS, "RESULT_RANGE_STATUS", "RESULT__RANGE_STATUS", 1, AccessReadOnly},
+	{RESULT_REPORT_STATUS, "RESULT_REPORT_STATUS", "RESULT__REPORT_STATUS", 1, AccessReadOnly},
+	{RESULT_STREAM_COUNT, "RESULT_STREAM_COUNT", "RESULT__STREAM_COUNT", 1, AccessReadOnly},
+	{RESULT_DSS_ACTUAL_EFFECTIVE_SPADS_SD0, "RESULT_DSS_ACTUAL_EFFECTIVE_SPADS_SD0", "RESULT__DSS_ACTUAL_EFFECTIVE_SPADS_SD0", 2, AccessReadOnly},
+	{RESULT_PEAK_SIGNAL_COUNT_RATE_MCPS_SD0, "RESULT_PEAK_SIGNAL_COUNT_RATE_MCPS_SD0", "RESULT__PEAK_SIGNAL_COUNT_RATE_MCPS_SD0", 2, AccessReadOnly},
+	{RESULT_AMBIENT_COUNT_RATE_MCPS_SD0, "RESULT_AMBIENT_COUNT_RATE_MCPS_SD0", "RESULT__AMBIENT_COUNT_RATE_MCPS_SD0", 2, AccessReadOnly},
+	{RESULT_SIGMA_SD0, "RESULT_SIGMA_SD0", "RESULT__SIGMA_SD0", 2, AccessReadOnly},
+	{RESULT_PHASE_SD0, "RESULT_PHASE_SD0", "RESULT__PHASE_SD0", 2, AccessReadOnly},
+	{RESULT_FINAL_CROSSTALK_CORRECTED_RANGE_MM_SD0, "RESULT_FINAL_CROSSTALK_CORRECTED_RANGE_MM_SD0", "RESULT__FINAL_CROSSTALK_CORRECTED_RANGE_MM_SD0", 2, AccessReadOnly},
+	{RESULT_PEAK_SIGNAL_COUNT_RATE_CROSSTALK_CORRECTED_MCPS_SD0, "RESULT_PEAK_SIGNAL_COUNT_RATE_CROSSTALK_CORRECTED_MCPS_SD0", "RESULT__PEAK_SIGNAL_COUNT_RATE_CROSSTALK_CORRECTED_MCPS_SD0", 2, AccessReadOnly},
+	{RESULT_MM_INNER_ACTUAL_EFFECTIVE_SPADS_SD0, "RESULT_MM_INNER_ACTUAL_EFFECTIVE_SPADS_SD0", "RESULT__MM_INNER_ACTUAL_EFFECTIVE_SPADS_SD0", 2, AccessReadOnly},
+	{RESULT_MM_OUTER_ACTUAL_EFFECTIVE_SPADS_SD0, "RESULT_MM_OUTER_ACTUAL_EFFECTIVE_SPADS_SD0", "RESULT__MM_OUTER_ACTUAL_EFFECTIVE_SPADS_SD0", 2, AccessReadOnly},
+	{RESULT_AVG_SIGNAL_COUNT_RATE_MCPS_SD0, "RESULT_AVG_SIGNAL_COUNT_RATE_MCPS_SD0", "RESULT__AVG_SIGNAL_COUNT_RATE_MCPS_SD0", 2, AccessReadOnly},
+	{RESULT_DSS_ACTUAL_EFFECTIVE_SPADS_SD1, "RESULT_DSS_ACTUAL_EFFECTIVE_SPADS_SD1", "RESULT__DSS_ACTUAL_EFFECTIVE_SPADS_SD1", 2, AccessReadOnly},
+	{RESULT_PEAK_SIGNAL_COUNT_RATE_MCPS_SD1, "RESULT_PEAK_SIGNAL_COUNT_RATE_MCPS_SD1", "RESULT__PEAK_SIGNAL_COUNT_RATE_MCPS_SD1", 2, AccessReadOnly},
+	{RESULT_AMBIENT_COUNT_RATE_MCPS_SD1, "RESULT_AMBIENT_COUNT_RATE_MCPS_SD1", "RESULT__AMBIENT_COUNT_RATE_MCPS_SD1", 2, AccessReadOnly},
+	{RESULT_SIGMA_SD1, "RESULT_SIGMA_SD1", "RESULT__SIGMA_SD1", 2, AccessReadOnly},
+	{RESULT_PHASE_SD1, "RESULT_PHASE_SD1", "RESULT__PHASE_SD1", 2, AccessReadOnly},
+	{RESULT_FINAL_CROSSTALK_CORRECTED_RANGE_MM_SD1, "RESULT_FINAL_CROSSTALK_CORRECTED_RANGE_MM_SD1", "RESULT__FINAL_CROSSTALK_CORRECTED_RANGE_MM_SD1", 2, AccessReadOnly},
+	{RESULT_SPARE_0_SD1, "RESULT_SPARE_0_SD1", "RESULT__SPARE_0_SD1", 2, AccessReadOnly},
+	{RESULT_SPARE_1_SD1, "RESULT_SPARE_1_SD1", "RESULT__SPARE_1_SD1", 2, AccessReadOnly},
+	{RESULT_SPARE_2_SD1, "RESULT_SPARE_2_SD1", "RESULT__SPARE_2_SD1", 2, AccessReadOnly},
+	{RESULT_SPARE_3_SD1, "RESULT_SPARE_3_SD1", "RESULT__SPARE_3_SD1", 1, AccessReadOnly},
+	{RESULT_THRESH_INFO, "RESULT_THRESH_INFO", "RESULT__THRESH_INFO", 1, AccessReadOnly},
+	{RESULT_CORE_AMBIENT_WINDOW_EVENTS_SD0, "RESULT_CORE_AMBIENT_WINDOW_EVENTS_SD0", "RESULT_CORE__AMBIENT_WINDOW_EVENTS_SD0", 4, AccessReadOnly},
+	{RESULT_CORE_RANGING_TOTAL_EVENTS_SD0, "RESULT_CORE_RANGING_TOTAL_EVENTS_SD0", "RESULT_CORE__RANGING_TOTAL_EVENTS_SD0", 4, AccessReadOnly},
+	{RESULT_CORE_SIGNAL_TOTAL_EVENTS_SD0, "RESULT_CORE_SIGNAL_TOTAL_EVENTS_SD0", "RESULT_CORE__SIGNAL_TOTAL_EVENTS_SD0", 4, AccessReadOnly},
+	{RESULT_CORE_TOTAL_PERIODS_ELAPSED_SD0, "RESULT_CORE_TOTAL_PERIODS_ELAPSED_SD0", "RESULT_CORE__TOTAL_PERIODS_ELAPSED_SD0", 4, AccessReadOnly},
+	{RESULT_CORE_AMBIENT_WINDOW_EVENTS_SD1, "RESULT_CORE_AMBIENT_WINDOW_EVENTS_SD1", "RESULT_CORE__AMBIENT_WINDOW_EVENTS_SD1", 4, AccessReadOnly},
+	{RESULT_CORE_RANGING_TOTAL_EVENTS_SD1, "RESULT_CORE_RANGING_TOTAL_EVENTS_SD1", "RESULT_CORE__RANGING_TOTAL_EVENTS_SD1", 4, AccessReadOnly},
+	{RESULT_CORE_SIGNAL_TOTAL_EVENTS_SD1, "RESULT_CORE_SIGNAL_TOTAL_EVENTS_SD1", "RESULT_CORE__SIGNAL_TOTAL_EVENTS_SD1", 4, AccessReadOnly},
+	{RESULT_CORE_TOTAL_PERIODS_ELAPSED_SD1, "RESULT_CORE_TOTAL_PERIODS_ELAPSED_SD1", "RESULT_CORE__TOTAL_PERIODS_ELAPSED_SD1", 4, AccessReadOnly},
+	{RESULT_CORE_SPARE_0, "RESULT_CORE_SPARE_0", "RESULT_CORE__SPARE_0", 1, AccessReadOnly},
+	{PHASECAL_RESULT_REFERENCE_PHASE, "PHASECAL_RESULT_REFERENCE_PHASE", "PHASECAL_RESULT__REFERENCE_PHASE", 2, AccessReadOnly},
+	{PHASECAL_RESULT_VCSEL_START, "PHASECAL_RESULT_VCSEL_START", "PHASECAL_RESULT__VCSEL_START", 1, AccessReadOnly},
+	{REF_SPAD_CHAR_RESULT_NUM_ACTUAL_REF_SPADS, "REF_SPAD_CHAR_RESULT_NUM_ACTUAL_REF_SPADS", "REF_SPAD_CHAR_RESULT__NUM_ACTUAL_REF_SPADS", 1, AccessReadOnly},
+	{REF_SPAD_CHAR_RESULT_REF_LOCATION, "REF_SPAD_CHAR_RESULT_REF_LOCATION", "REF_SPAD_CHAR_RESULT__REF_LOCATION", 1, AccessReadOnly},
+	{VHV_RESULT_COLDBOOT_STATUS, "VHV_RESULT_COLDBOOT_STATUS", "VHV_RESULT__COLDBOOT_STATUS", 1, AccessReadOnly},
+	{VHV_RESULT_SEARCH_RESULT, "VHV_RESULT_SEARCH_RESULT", "VHV_RESULT__SEARCH_RESULT", 1, AccessReadOnly},
+	{VHV_RESULT_LATEST_SETTING, "VHV_RESULT_LATEST_SETTING", "VHV_RESULT__LATEST_SETTING", 1, AccessReadOnly},
+	{RESULT_OSC_CALIBRATE_VAL, "RESULT_OSC_CALIBRATE_VAL", "RESULT__OSC_CALIBRATE_VAL", 2, AccessReadOnly},
+	{ANA_CONFIG_POWERDOWN_GO1, "ANA_CONFIG_POWERDOWN_GO1", "ANA_CONFIG__POWERDOWN_GO1", 1, AccessReadWrite},
+	{ANA_CONFIG_REF_BG_CTRL, "ANA_CONFIG_REF_BG_CTRL", "ANA_CONFIG__REF_BG_CTRL", 1, AccessReadWrite},
+	{ANA_CONFIG_REGDVDD1V2_CTRL, "ANA_CONFIG_REGDVDD1V2_CTRL", "ANA_CONFIG__REGDVDD1V2_CTRL", 1, AccessReadWrite},
+	{ANA_CONFIG_OSC_SLOW_CTRL, "ANA_CONFIG_OSC_SLOW_CTRL", "ANA_CONFIG__OSC_SLOW_CTRL", 1, AccessReadWrite},
+	{TEST_MODE_STATUS, "TEST_MODE_STATUS", "TEST_MODE__STATUS", 1, AccessReadOnly},
+	{FIRMWARE_SYSTEM_STATUS, "FIRMWARE_SYSTEM_STATUS", "FIRMWARE__SYSTEM_STATUS", 1, AccessReadOnly},
+	{FIRMWARE_MODE_STATUS, "FIRMWARE_MODE_STATUS", "FIRMWARE__MODE_STATUS", 1, AccessReadOnly},
+	{FIRMWARE_SECONDARY_MODE_STATUS, "FIRMWARE_SECONDARY_MODE_STATUS", "FIRMWARE__SECONDARY_MODE_STATUS", 1, AccessReadOnly},
+	{FIRMWARE_CAL_REPEAT_RATE_COUNTER, "FIRMWARE_CAL_REPEAT_RATE_COUNTER", "FIRMWARE__CAL_REPEAT_RATE_COUNTER", 2, AccessReadOnly},
+	{IDENTIFICATION_MODEL_ID, "IDENTIFICATION_MODEL_ID", "IDENTIFICATION__MODEL_ID", 1, AccessReadOnly},
+	{IDENTIFICATION_MODULE_TYPE, "IDENTIFICATION_MODULE_TYPE", "IDENTIFICATION__MODULE_TYPE", 1, AccessReadOnly},
+	{IDENTIFICATION_REVISION_ID, "IDENTIFICATION_REVISION_ID", "IDENTIFICATION__REVISION_ID", 1, AccessReadOnly},
+}
@@ -0,0 +1,47 @@
+package vl53l1x
+
+import (
+	"fmt"
+	"strings"
+)
+
+// logWrite logs a register write when the register log is enabled
+func (v *VL53L1X) logWrite(reg uint16, size int, value uint32) {
+
+	if !v.regLog {
+		return
+	}
+
+	if v.regRedact[reg] {
+		v.log.Printf("write %s = <redacted>", RegisterName(reg))
+		return
+	}
+
+	v.log.Printf("write %s = 0x%0*X", RegisterName(reg), size*2, value)
+}
+
+// logBytes logs a register read or block write when the register log is
+// enabled
+func (v *VL53L1X) logBytes(op string, reg uint16, data []byte) {
+
+	if !v.regLog {
+		return
+	}
+
+	if v.regRedact[reg] {
+		v.log.Printf("%s %s [%d] = <redacted>", op, RegisterName(reg), len(data))
+		return
+	}
+
+	var sb strings.Builder
+
+	for i, b := range data {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+
+		fmt.Fprintf(&sb, "%02X", b)
+	}
+
+	v.log.Printf("%s %s [%d] = %s", op, RegisterName(reg), len(data), sb.String())
+}
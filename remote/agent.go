@@ -0,0 +1,163 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"sync"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+// Agent serves the I2C bus to remote clients.  Transfers from all clients are
+// serialized on the bus
+type Agent struct {
+	// open returns a Bus to the device at addr on the served I2C bus
+	open func(addr uint8) (vl53l1x.Bus, error)
+
+	mu      sync.Mutex
+	devices map[uint8]vl53l1x.Bus
+
+	log *log.Logger
+}
+
+// NewAgent returns an agent that opens devices on the served bus with open,
+// logging connections to logger if not nil
+func NewAgent(open func(addr uint8) (vl53l1x.Bus, error), logger *log.Logger) *Agent {
+
+	if logger == nil {
+		logger = log.New(io.Discard, "", 0)
+	}
+
+	return &Agent{
+		open:    open,
+		devices: make(map[uint8]vl53l1x.Bus),
+		log:     logger,
+	}
+}
+
+// Serve accepts client connections on l until the context is cancelled, then
+// closes the listener and the devices opened
+func (a *Agent) Serve(ctx context.Context, l net.Listener) error {
+
+	var wg sync.WaitGroup
+
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+
+	defer a.closeDevices()
+	defer wg.Wait()
+
+	for {
+		conn, err := l.Accept()
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			a.serveConn(ctx, conn)
+		}()
+	}
+}
+
+// serveConn handles requests from a client until it disconnects
+func (a *Agent) serveConn(ctx context.Context, conn net.Conn) {
+
+	a.log.Printf("Client %s connected", conn.RemoteAddr())
+	defer a.log.Printf("Client %s disconnected", conn.RemoteAddr())
+
+	// unblock reads when the agent is stopped
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+	defer conn.Close()
+
+	header := make([]byte, 2)
+
+	for {
+		n, err := readHeader(conn, header)
+
+		if err != nil {
+			if !errors.Is(err, io.EOF) && ctx.Err() == nil {
+				a.log.Printf("Client %s: %v", conn.RemoteAddr(), err)
+			}
+
+			return
+		}
+
+		op, addr := header[0], header[1]
+		buf := make([]byte, n)
+
+		if op == opWrite {
+			if _, err := io.ReadFull(conn, buf); err != nil {
+				return
+			}
+		}
+
+		status := byte(statusOK)
+		data, err := a.transfer(op, addr, buf)
+
+		if err != nil {
+			status, data = errorStatus(err), []byte(err.Error())
+		}
+
+		if err := writeFrame(conn, []byte{status}, len(data), data); err != nil {
+			return
+		}
+	}
+}
+
+// transfer performs a request on the bus, returning the data read
+func (a *Agent) transfer(op, addr byte, buf []byte) ([]byte, error) {
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	dev, ok := a.devices[addr]
+
+	if !ok {
+		var err error
+
+		if dev, err = a.open(addr); err != nil {
+			return nil, err
+		}
+
+		a.devices[addr] = dev
+	}
+
+	switch op {
+	case opWrite:
+		_, err := dev.WriteBytes(buf)
+		return nil, err
+
+	case opRead:
+		n, err := dev.ReadBytes(buf)
+		return buf[:n], err
+
+	default:
+		return nil, errors.New("unknown operation")
+	}
+}
+
+// closeDevices closes the devices opened by the agent
+func (a *Agent) closeDevices() {
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for addr, dev := range a.devices {
+		dev.Close()
+		delete(a.devices, addr)
+	}
+}
@@ -0,0 +1,185 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+// DefaultTimeout is the time allowed for a transfer to complete over the
+// network unless changed with SetTimeout()
+const DefaultTimeout = 2 * time.Second
+
+// ErrClosed is returned when a transfer is made on a closed client
+var ErrClosed = errors.New("remote connection is closed")
+
+// Error is a bus error returned by the agent
+type Error struct {
+	// Msg is the error message from the agent
+	Msg string
+	// NACK is set when the device did not acknowledge
+	NACK bool
+}
+
+// Error returns the agents error message
+func (e *Error) Error() string {
+	return "remote: " + e.Msg
+}
+
+// Is reports a NACK as vl53l1x.ErrNACK
+func (e *Error) Is(target error) bool {
+	return e.NACK && target == vl53l1x.ErrNACK
+}
+
+// clientConn is the network connection to the agent shared by all devices
+// opened on it
+type clientConn struct {
+	mu      sync.Mutex
+	conn    net.Conn
+	host    string
+	timeout time.Duration
+	refs    int
+}
+
+// Client is a vl53l1x.Bus to a device on the I2C bus served by an Agent
+type Client struct {
+	conn   *clientConn
+	addr   uint8
+	closed bool
+}
+
+// Dial connects to the agent at host, eg: raspberrypi.local:5329, and returns
+// a Bus to the device at addr.  If host has no port DefaultPort is used
+func Dial(host string, addr uint8) (*Client, error) {
+
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, strconv.Itoa(DefaultPort))
+	}
+
+	conn, err := net.DialTimeout("tcp", host, DefaultTimeout)
+
+	if err != nil {
+		return nil, err
+	}
+
+	c := &clientConn{conn: conn, host: host, timeout: DefaultTimeout, refs: 1}
+
+	return &Client{conn: c, addr: addr}, nil
+}
+
+// Open returns a Bus to the device at addr over the same connection, for use
+// with vl53l1x.WithBusOpener()
+func (c *Client) Open(addr uint8, dev string) (vl53l1x.Bus, error) {
+
+	c.conn.mu.Lock()
+	defer c.conn.mu.Unlock()
+
+	c.conn.refs++
+
+	return &Client{conn: c.conn, addr: addr}, nil
+}
+
+// SetTimeout sets the time allowed for each transfer on the connection
+func (c *Client) SetTimeout(d time.Duration) {
+
+	c.conn.mu.Lock()
+	defer c.conn.mu.Unlock()
+
+	c.conn.timeout = d
+}
+
+// GetAddr returns the I2C address of the device
+func (c *Client) GetAddr() uint8 {
+	return c.addr
+}
+
+// GetDev returns the address of the agent
+func (c *Client) GetDev() string {
+	return "tcp://" + c.conn.host
+}
+
+// Close the device, the network connection is closed once all devices opened
+// on it are closed
+func (c *Client) Close() error {
+
+	c.conn.mu.Lock()
+	defer c.conn.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+
+	c.closed = true
+	c.conn.refs--
+
+	if c.conn.refs > 0 {
+		return nil
+	}
+
+	return c.conn.conn.Close()
+}
+
+// WriteBytes writes buf to the device
+func (c *Client) WriteBytes(buf []byte) (int, error) {
+
+	if _, err := c.transfer(opWrite, len(buf), buf, nil); err != nil {
+		return 0, err
+	}
+
+	return len(buf), nil
+}
+
+// ReadBytes reads len(buf) bytes from the device
+func (c *Client) ReadBytes(buf []byte) (int, error) {
+	return c.transfer(opRead, len(buf), nil, buf)
+}
+
+// transfer sends a request and reads its response into buf
+func (c *Client) transfer(op byte, n int, data, buf []byte) (int, error) {
+
+	c.conn.mu.Lock()
+	defer c.conn.mu.Unlock()
+
+	if c.closed {
+		return 0, ErrClosed
+	}
+
+	conn := c.conn.conn
+
+	if err := conn.SetDeadline(time.Now().Add(c.conn.timeout)); err != nil {
+		return 0, err
+	}
+
+	if err := writeFrame(conn, []byte{op, c.addr}, n, data); err != nil {
+		return 0, err
+	}
+
+	status := make([]byte, 1)
+	size, err := readHeader(conn, status)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if status[0] != statusOK {
+		msg := make([]byte, size)
+
+		if _, err := io.ReadFull(conn, msg); err != nil {
+			return 0, err
+		}
+
+		return 0, &Error{Msg: string(msg), NACK: status[0] == statusNACK}
+	}
+
+	if size > len(buf) {
+		return 0, fmt.Errorf("remote returned %d bytes, expected %d", size, len(buf))
+	}
+
+	return io.ReadFull(conn, buf[:size])
+}
@@ -0,0 +1,82 @@
+// Package remote tunnels I2C transfers over TCP so an application can run on
+// a workstation while the sensor stays attached to the target device.  An
+// Agent serves the I2C bus on the target and Dial() returns a vl53l1x.Bus
+// connected to it.
+//
+// Each request is a frame of an operation byte, the I2C address, a 16-bit big
+// endian length and for writes the data.  Each response is a status byte, a
+// 16-bit length and the data read or an error message.
+package remote
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"syscall"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+// DefaultPort is the TCP port the agent listens on by default
+const DefaultPort = 5329
+
+// request operations
+const (
+	opWrite = 'W'
+	opRead  = 'R'
+)
+
+// response status codes
+const (
+	statusOK    = 0x00
+	statusError = 0x01
+	statusNACK  = 0x02
+)
+
+// maxFrame is the largest transfer carried in one frame
+const maxFrame = 0xFFFF
+
+// writeFrame writes a frame of the header bytes, the length n and data
+func writeFrame(w io.Writer, header []byte, n int, data []byte) error {
+
+	if n > maxFrame {
+		return fmt.Errorf("transfer of %d bytes too large", n)
+	}
+
+	buf := make([]byte, 0, len(header)+2+len(data))
+	buf = append(buf, header...)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(n))
+	buf = append(buf, data...)
+
+	_, err := w.Write(buf)
+	return err
+}
+
+// readHeader reads the header bytes and the frame length
+func readHeader(r io.Reader, header []byte) (int, error) {
+
+	buf := make([]byte, len(header)+2)
+
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return 0, err
+	}
+
+	copy(header, buf)
+
+	return int(binary.BigEndian.Uint16(buf[len(header):])), nil
+}
+
+// errorStatus returns the response status for a bus error
+func errorStatus(err error) byte {
+
+	var errno syscall.Errno
+
+	// i2c-dev reports a NACK as EREMOTEIO or ENXIO
+	if errors.Is(err, vl53l1x.ErrNACK) ||
+		(errors.As(err, &errno) && (errno == 121 || errno == 6)) {
+		return statusNACK
+	}
+
+	return statusError
+}
@@ -0,0 +1,230 @@
+package replay
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+// ErrEnd is returned by a Player once every recorded transaction has been
+// replayed
+var ErrEnd = errors.New("replay: end of recording")
+
+// MismatchError is returned by a Player when the driver makes a transaction
+// other than the one recorded next, which is where a replayed run departs
+// from the recording
+type MismatchError struct {
+	// Line is the line of the recording expected next
+	Line int
+	// Want is the transaction recorded and Got the one made
+	Want string
+	Got  string
+}
+
+// Error describes the mismatch
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("replay: line %d: want %s, got %s", e.Line, e.Want, e.Got)
+}
+
+// Player is a vl53l1x.Bus that plays back a recording in place of the
+// sensor.  Each write must match the one recorded and each read returns the
+// bytes and error recorded
+type Player struct {
+	mu      sync.Mutex
+	dev     string
+	addr    uint8
+	entries []entry
+	next    int
+	// paced delays each transaction to its recorded time, see SetPaced()
+	paced bool
+	start time.Time
+}
+
+// NewPlayer returns a Player for the recording read from r
+func NewPlayer(r io.Reader) (*Player, error) {
+
+	p := &Player{}
+	sc := bufio.NewScanner(r)
+
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+
+		if dev, ok := strings.CutPrefix(line, "# dev "); ok {
+			p.dev = dev
+			continue
+		}
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		e, err := parseEntry(line)
+
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+
+		e.line = n
+		p.entries = append(p.entries, e)
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(p.entries) == 0 {
+		return nil, fmt.Errorf("recording has no transactions")
+	}
+
+	p.addr = p.entries[0].addr
+
+	return p, nil
+}
+
+// Load returns a Player for the recording in the file at path
+func Load(path string) (*Player, error) {
+
+	f, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	p, err := NewPlayer(f)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return p, nil
+}
+
+// SetPaced delays each transaction until its recorded time since the first,
+// so timeouts expire as they did when recorded.  By default transactions are
+// replayed as fast as they are made
+func (p *Player) SetPaced(paced bool) {
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.paced = paced
+}
+
+// Remaining returns the number of recorded transactions not yet replayed
+func (p *Player) Remaining() int {
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.entries) - p.next
+}
+
+// Open returns the Player for vl53l1x.WithBusOpener() so the recording
+// continues at the new address after SetAddress()
+func (p *Player) Open(addr uint8, dev string) (vl53l1x.Bus, error) {
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.addr = addr
+
+	return p, nil
+}
+
+// GetAddr returns the address the driver is communicating with
+func (p *Player) GetAddr() uint8 {
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.addr
+}
+
+// GetDev returns the device path recorded
+func (p *Player) GetDev() string {
+	return p.dev
+}
+
+// ReadBytes returns the bytes and error of the next recorded transaction,
+// which must be a read of the same size
+func (p *Player) ReadBytes(buf []byte) (int, error) {
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	e, err := p.take(entry{addr: p.addr, size: len(buf)})
+
+	if err != nil {
+		return 0, err
+	}
+
+	n := copy(buf, e.data)
+
+	if e.err != "" {
+		return n, recordedError(e.err)
+	}
+
+	return n, nil
+}
+
+// WriteBytes checks the bytes written against the next recorded transaction
+// and returns its error
+func (p *Player) WriteBytes(buf []byte) (int, error) {
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	e, err := p.take(entry{write: true, addr: p.addr, size: len(buf), data: buf})
+
+	if err != nil {
+		return 0, err
+	}
+
+	if e.err != "" {
+		return 0, recordedError(e.err)
+	}
+
+	return len(buf), nil
+}
+
+// take returns the next recorded transaction if it matches the one made,
+// the caller must hold p.mu
+func (p *Player) take(got entry) (entry, error) {
+
+	if p.next >= len(p.entries) {
+		return entry{}, ErrEnd
+	}
+
+	e := p.entries[p.next]
+
+	if e.write != got.write || e.addr != got.addr || e.size != got.size ||
+		(e.write && string(e.data) != string(got.data)) {
+		return entry{}, &MismatchError{Line: e.line, Want: e.describe(), Got: got.describe()}
+	}
+
+	p.next++
+
+	if p.paced {
+		if p.start.IsZero() {
+			p.start = time.Now().Add(-e.offset)
+		}
+
+		time.Sleep(time.Until(p.start.Add(e.offset)))
+	}
+
+	return e, nil
+}
+
+// Close does nothing, the recording can be replayed up to its end
+func (p *Player) Close() error {
+	return nil
+}
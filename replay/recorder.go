@@ -0,0 +1,216 @@
+package replay
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+// output is the recording shared by a Recorder and the recorders it opens
+// when the sensor address changes
+type output struct {
+	mu    sync.Mutex
+	w     io.Writer
+	start time.Time
+	// closer closes the file created by Create() once every recorder is
+	// closed
+	closer io.Closer
+	refs   int
+	err    error
+	// reg is the register last addressed at each address, to name the
+	// register of a read
+	reg map[uint8]uint16
+}
+
+// write writes the transaction as a line, keeping the first error
+func (o *output) write(e entry) {
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if len(e.data) >= 2 && e.write {
+		o.reg[e.addr] = uint16(e.data[0])<<8 | uint16(e.data[1])
+	}
+
+	if reg, ok := o.reg[e.addr]; ok {
+		e.comment = vl53l1x.RegisterName(reg)
+	}
+
+	e.offset = time.Since(o.start)
+
+	if o.err != nil {
+		return
+	}
+
+	_, o.err = fmt.Fprintln(o.w, e.format())
+}
+
+// Recorder is a vl53l1x.Bus that passes every transaction to the bus it
+// wraps and writes it to a recording.  Lines are written as transactions are
+// made so the recording is complete up to a crash or hang
+type Recorder struct {
+	bus    vl53l1x.Bus
+	out    *output
+	closed bool
+}
+
+// NewRecorder returns a Recorder writing the transactions made on bus to w
+func NewRecorder(bus vl53l1x.Bus, w io.Writer) *Recorder {
+
+	out := &output{
+		w:     w,
+		start: time.Now(),
+		refs:  1,
+		reg:   make(map[uint8]uint16),
+	}
+
+	_, out.err = fmt.Fprintf(w, "%s\n# dev %s\n", header, bus.GetDev())
+
+	return &Recorder{bus: bus, out: out}
+}
+
+// Create returns a Recorder writing the transactions made on bus to the file
+// at path, which is closed with the Recorder
+func Create(path string, bus vl53l1x.Bus) (*Recorder, error) {
+
+	f, err := os.Create(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	r := NewRecorder(bus, f)
+	r.out.closer = f
+
+	return r, nil
+}
+
+// Opener returns a function for vl53l1x.WithBusOpener() that opens the bus
+// with open and records it to the same recording, so transactions made after
+// SetAddress() are recorded
+func (r *Recorder) Opener(open func(addr uint8, dev string) (vl53l1x.Bus, error)) func(addr uint8, dev string) (vl53l1x.Bus, error) {
+
+	return func(addr uint8, dev string) (vl53l1x.Bus, error) {
+
+		bus, err := open(addr, dev)
+
+		if err != nil {
+			return nil, err
+		}
+
+		r.out.mu.Lock()
+		r.out.refs++
+		r.out.mu.Unlock()
+
+		return &Recorder{bus: bus, out: r.out}, nil
+	}
+}
+
+// Err returns the first error writing the recording
+func (r *Recorder) Err() error {
+
+	r.out.mu.Lock()
+	defer r.out.mu.Unlock()
+
+	return r.out.err
+}
+
+// GetAddr returns the address of the wrapped bus
+func (r *Recorder) GetAddr() uint8 {
+	return r.bus.GetAddr()
+}
+
+// GetDev returns the device path of the wrapped bus
+func (r *Recorder) GetDev() string {
+	return r.bus.GetDev()
+}
+
+// ReadBytes reads from the wrapped bus and records the bytes read
+func (r *Recorder) ReadBytes(buf []byte) (int, error) {
+
+	n, err := r.bus.ReadBytes(buf)
+	r.record(false, buf, n, err)
+
+	return n, err
+}
+
+// WriteBytes writes to the wrapped bus and records the bytes written
+func (r *Recorder) WriteBytes(buf []byte) (int, error) {
+
+	n, err := r.bus.WriteBytes(buf)
+	r.record(true, buf, len(buf), err)
+
+	return n, err
+}
+
+// record writes the transaction to the recording
+func (r *Recorder) record(write bool, buf []byte, n int, err error) {
+
+	e := entry{
+		write: write,
+		addr:  r.bus.GetAddr(),
+		size:  len(buf),
+		data:  buf[:max(0, min(n, len(buf)))],
+	}
+
+	if err != nil {
+		e.err = errorText(err)
+	}
+
+	r.out.write(e)
+}
+
+// MaxTransferSize returns the transfer limit of the wrapped bus, 0 if it has
+// none
+func (r *Recorder) MaxTransferSize() int {
+
+	if ts, ok := r.bus.(vl53l1x.TransferSizer); ok {
+		return ts.MaxTransferSize()
+	}
+
+	return 0
+}
+
+// Retries returns the retries counted by the wrapped bus, 0 if it does not
+// count them
+func (r *Recorder) Retries() uint64 {
+
+	if rc, ok := r.bus.(vl53l1x.RetryCounter); ok {
+		return rc.Retries()
+	}
+
+	return 0
+}
+
+// Close closes the wrapped bus and, once every recorder sharing it is
+// closed, the file created by Create().  It returns the first error writing
+// the recording
+func (r *Recorder) Close() error {
+
+	err := r.bus.Close()
+
+	r.out.mu.Lock()
+	defer r.out.mu.Unlock()
+
+	if r.closed {
+		return err
+	}
+
+	r.closed = true
+
+	if r.out.refs--; r.out.refs == 0 && r.out.closer != nil {
+		if cerr := r.out.closer.Close(); r.out.err == nil {
+			r.out.err = cerr
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return r.out.err
+}
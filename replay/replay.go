@@ -0,0 +1,175 @@
+// Package replay records the I2C transactions between the driver and a
+// sensor to a file and plays them back in place of the sensor, so a failing
+// sequence seen on one device can be attached to a bug report and reproduced
+// exactly without the hardware.
+//
+// A recording is a text file with one transaction per line:
+//
+//	# vl53l1x bus recording
+//	# dev /dev/i2c-1
+//	0.000000 W 29 2 010f # IDENTIFICATION_MODEL_ID
+//	0.000187 R 29 2 eacc # IDENTIFICATION_MODEL_ID
+//	0.004512 R 29 1 - ! nack # GPIO_TIO_HV_STATUS
+//
+// giving the seconds since recording started, W for a write or R for a read,
+// the address, the number of bytes requested, the bytes transferred in hex
+// and, after a !, the error returned.  The register a transaction addresses
+// is given as a comment.
+package replay
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+// header is the first line of a recording
+const header = "# vl53l1x bus recording"
+
+// nack is the error text recorded for errors wrapping vl53l1x.ErrNACK
+const nack = "nack"
+
+// entry is a recorded transaction
+type entry struct {
+	line    int
+	offset  time.Duration
+	write   bool
+	addr    uint8
+	size    int
+	data    []byte
+	err     string
+	comment string
+}
+
+// format returns the entry as a line of the recording
+func (e entry) format() string {
+
+	op := "R"
+
+	if e.write {
+		op = "W"
+	}
+
+	data := "-"
+
+	if len(e.data) > 0 {
+		data = hex.EncodeToString(e.data)
+	}
+
+	line := fmt.Sprintf("%.6f %s %02x %d %s", e.offset.Seconds(), op, e.addr, e.size, data)
+
+	if e.err != "" {
+		line += " ! " + e.err
+	}
+
+	if e.comment != "" {
+		line += " # " + e.comment
+	}
+
+	return line
+}
+
+// describe returns the transaction without its timing for mismatch errors
+func (e entry) describe() string {
+
+	op := "read"
+
+	if e.write {
+		op = "write"
+	}
+
+	s := fmt.Sprintf("%s of %d bytes at 0x%02x", op, e.size, e.addr)
+
+	if e.write {
+		s += " " + hex.EncodeToString(e.data)
+	}
+
+	return s
+}
+
+// parseEntry parses a line of the recording
+func parseEntry(line string) (entry, error) {
+
+	var e entry
+
+	if i := strings.Index(line, " # "); i >= 0 {
+		line, e.comment = line[:i], line[i+3:]
+	}
+
+	if i := strings.Index(line, " ! "); i >= 0 {
+		line, e.err = line[:i], line[i+3:]
+	}
+
+	f := strings.Fields(line)
+
+	if len(f) != 5 {
+		return e, fmt.Errorf("expected 5 fields, got %d", len(f))
+	}
+
+	secs, err := strconv.ParseFloat(f[0], 64)
+
+	if err != nil {
+		return e, fmt.Errorf("invalid time %q", f[0])
+	}
+
+	e.offset = time.Duration(secs * float64(time.Second))
+
+	switch f[1] {
+	case "W":
+		e.write = true
+	case "R":
+	default:
+		return e, fmt.Errorf("invalid operation %q", f[1])
+	}
+
+	addr, err := strconv.ParseUint(f[2], 16, 8)
+
+	if err != nil {
+		return e, fmt.Errorf("invalid address %q", f[2])
+	}
+
+	e.addr = uint8(addr)
+
+	if e.size, err = strconv.Atoi(f[3]); err != nil || e.size < 0 {
+		return e, fmt.Errorf("invalid size %q", f[3])
+	}
+
+	if f[4] != "-" {
+		if e.data, err = hex.DecodeString(f[4]); err != nil {
+			return e, fmt.Errorf("invalid data %q", f[4])
+		}
+	}
+
+	if len(e.data) > e.size {
+		return e, fmt.Errorf("%d bytes of data exceed size %d", len(e.data), e.size)
+	}
+
+	return e, nil
+}
+
+// recordedError returns the error recorded as text, wrapping
+// vl53l1x.ErrNACK when it was a NACK
+func recordedError(text string) error {
+
+	if text == nack {
+		return fmt.Errorf("replay: %w", vl53l1x.ErrNACK)
+	}
+
+	return errors.New(text)
+}
+
+// errorText returns the text an error is recorded as
+func errorText(err error) string {
+
+	if errors.Is(err, vl53l1x.ErrNACK) {
+		return nack
+	}
+
+	// keep the error on its line
+	return strings.NewReplacer("\n", " ", " # ", " ").Replace(err.Error())
+}
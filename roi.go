@@ -30,7 +30,12 @@ func (v *VL53L1X) SetROISize(width, height uint8) error {
 
 	val := ((height - 1) << 4) | (width - 1)
 
-	return v.writeReg(ROI_CONFIG_USER_ROI_REQUESTED_GLOBAL_XY_SIZE, val)
+	if err := v.writeReg(ROI_CONFIG_USER_ROI_REQUESTED_GLOBAL_XY_SIZE, val); err != nil {
+		return err
+	}
+
+	v.configChanged("roi size")
+	return nil
 }
 
 // GetROISize returns the current ROI width and height
@@ -98,10 +103,105 @@ func (v *VL53L1X) GetROISize() (width, height uint8, err error) {
 // sense objects toward the upper left, you should pick a center SPAD in the
 // lower right.
 func (v *VL53L1X) SetROICenter(spadNumber uint8) error {
-	return v.writeReg(ROI_CONFIG_USER_ROI_CENTRE_SPAD, spadNumber)
+
+	if err := v.writeReg(ROI_CONFIG_USER_ROI_CENTRE_SPAD, spadNumber); err != nil {
+		return err
+	}
+
+	v.configChanged("roi center")
+	return nil
 }
 
 // GetROICenter returns the current center SPAD
 func (v *VL53L1X) GetROICenter() (uint8, error) {
 	return v.readReg(ROI_CONFIG_USER_ROI_CENTRE_SPAD)
 }
+
+// SPADRect is a rectangular region of the 16x16 SPAD array.  Columns and rows
+// are oriented as per the table in SetROICenter() with column 0 on the left
+// and row 0 at the top
+type SPADRect struct {
+	Col, Row      uint8
+	Width, Height uint8
+}
+
+// valid returns true if the rectangle lies entirely within the SPAD array
+func (r SPADRect) valid() bool {
+	return r.Width > 0 && r.Height > 0 &&
+		int(r.Col)+int(r.Width) <= 16 && int(r.Row)+int(r.Height) <= 16
+}
+
+// Center returns the SPAD number at the center of the rectangle to be used
+// with SetROICenter().  For even sizes the center is taken to the right and
+// above the geometric center, matching ST's ULD multi-zone examples
+func (r SPADRect) Center() uint8 {
+	return SPADNumber(r.Col+r.Width/2, r.Row+(r.Height-1)/2)
+}
+
+// SPADNumber returns the SPAD number at the given column and row of the SPAD
+// array
+func SPADNumber(col, row uint8) uint8 {
+
+	if row < 8 {
+		return 128 + col*8 + row
+	}
+
+	return (15-col)*8 + (15 - row)
+}
+
+// ROIExcluding returns the largest ROI that fits within the SPAD array without
+// overlapping the excluded region, such as part of the field of view occluded
+// by an enclosure
+func ROIExcluding(exclude SPADRect) (SPADRect, error) {
+
+	if !exclude.valid() {
+		return SPADRect{}, fmt.Errorf("excluded region does not fit in SPAD array")
+	}
+
+	candidates := []SPADRect{
+		// left of exclusion
+		{Col: 0, Row: 0, Width: exclude.Col, Height: 16},
+		// right of exclusion
+		{Col: exclude.Col + exclude.Width, Row: 0, Width: 16 - exclude.Col - exclude.Width, Height: 16},
+		// above exclusion
+		{Col: 0, Row: 0, Width: 16, Height: exclude.Row},
+		// below exclusion
+		{Col: 0, Row: exclude.Row + exclude.Height, Width: 16, Height: 16 - exclude.Row - exclude.Height},
+	}
+
+	best := SPADRect{}
+
+	for _, c := range candidates {
+		if int(c.Width)*int(c.Height) > int(best.Width)*int(best.Height) {
+			best = c
+		}
+	}
+
+	if best.Width < 4 || best.Height < 4 {
+		return SPADRect{}, fmt.Errorf("no ROI of at least 4x4 avoids the excluded region")
+	}
+
+	return best, nil
+}
+
+// SetROIMask sets the largest ROI that avoids the excluded region of the SPAD
+// array and returns the ROI chosen
+func (v *VL53L1X) SetROIMask(exclude SPADRect) (SPADRect, error) {
+
+	roi, err := ROIExcluding(exclude)
+
+	if err != nil {
+		return SPADRect{}, err
+	}
+
+	// size must be set first as large ROI's force the center
+	if err := v.SetROISize(roi.Width, roi.Height); err != nil {
+		return SPADRect{}, err
+	}
+
+	if err := v.SetROICenter(roi.Center()); err != nil {
+		return SPADRect{}, err
+	}
+
+	return roi, nil
+}
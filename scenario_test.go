@@ -0,0 +1,256 @@
+package vl53l1x_test
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+	"github.com/swdee/go-vl53l1x/sim"
+)
+
+// clock scripts the simulated scene from when the scenario starts rather than
+// from when the simulator was created, as Init takes a measurement
+type clock struct {
+	mu    sync.Mutex
+	start time.Time
+}
+
+// begin starts the scenario
+func (c *clock) begin() {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.start = time.Now()
+}
+
+// elapsed returns the time since the scenario started
+func (c *clock) elapsed() time.Duration {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return time.Since(c.start)
+}
+
+// scripted sets a scene on the simulator following the distance returned by
+// fn for the time since the returned clock is started
+func scripted(bus *sim.Sensor, fn func(elapsed time.Duration) float64) *clock {
+
+	c := &clock{start: time.Now()}
+
+	bus.SetSceneFunc(func(time.Duration) sim.Scene {
+		sc := sim.DefaultScene()
+		sc.DistanceMM = fn(c.elapsed())
+		return sc
+	})
+
+	return c
+}
+
+func TestScenarioTargetApproach(t *testing.T) {
+
+	v, bus := newSensor(t)
+
+	if err := v.SetMeasurementTimingBudget(33); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := v.SetDistanceMode(vl53l1x.Long); err != nil {
+		t.Fatal(err)
+	}
+
+	// the target approaches from 1200mm to 200mm over a second
+	distance := func(elapsed time.Duration) float64 {
+		return math.Max(1200-elapsed.Seconds()*1000, 200)
+	}
+
+	c := scripted(bus, distance)
+	c.begin()
+
+	if err := v.StartContinuous(38); err != nil {
+		t.Fatal(err)
+	}
+
+	defer v.StopContinuous()
+
+	var first, last uint16
+
+	for i := 0; i < 20; i++ {
+		data, err := v.Read(true)
+
+		if err != nil {
+			t.Fatalf("read %d: %v", i, err)
+		}
+
+		want := distance(c.elapsed())
+
+		if !data.RangeStatus.IsValid() {
+			t.Fatalf("read %d: status %s at %.0fmm", i, data.RangeStatus, want)
+		}
+
+		// the measurement was taken up to a period before it was read
+		if math.Abs(float64(data.RangeMM)-want) > 100 {
+			t.Errorf("read %d: range %dmm, target at %.0fmm", i, data.RangeMM, want)
+		}
+
+		if i == 0 {
+			first = data.RangeMM
+		}
+
+		last = data.RangeMM
+	}
+
+	if last+500 > first {
+		t.Errorf("target approached from %dmm only to %dmm", first, last)
+	}
+}
+
+func TestScenarioNoTarget(t *testing.T) {
+
+	v, bus := newSensor(t)
+
+	sc := sim.DefaultScene()
+	sc.DistanceMM = 6000
+	bus.SetScene(sc)
+
+	if err := v.StartContinuous(25); err != nil {
+		t.Fatal(err)
+	}
+
+	defer v.StopContinuous()
+
+	for i := 0; i < 5; i++ {
+		data, err := v.Read(true)
+
+		if err != nil {
+			t.Fatalf("read %d: %v", i, err)
+		}
+
+		if data.RangeStatus != vl53l1x.SignalFail {
+			t.Errorf("read %d: status %s, want %s", i, data.RangeStatus, vl53l1x.SignalFail)
+		}
+	}
+}
+
+func TestScenarioThresholdWindow(t *testing.T) {
+
+	v, bus := newSensor(t)
+
+	// the target stays beyond the window for 300ms then steps into it
+	c := scripted(bus, func(elapsed time.Duration) float64 {
+		if elapsed < 300*time.Millisecond {
+			return 900
+		}
+
+		return 450
+	})
+
+	if err := v.SetDistanceThreshold(300, 600, vl53l1x.ThresholdInside, false); err != nil {
+		t.Fatal(err)
+	}
+
+	c.begin()
+
+	if err := v.StartContinuous(25); err != nil {
+		t.Fatal(err)
+	}
+
+	defer v.StopContinuous()
+
+	data, err := v.Read(true)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := c.elapsed(); elapsed < 250*time.Millisecond {
+		t.Errorf("interrupt raised after %s, before the target entered the window", elapsed)
+	}
+
+	if data.RangeMM < 300 || data.RangeMM > 600 {
+		t.Errorf("range %dmm outside the 300-600mm window", data.RangeMM)
+	}
+
+	if err := v.ClearDistanceThreshold(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScenarioModesAndROI(t *testing.T) {
+
+	v, _ := newSensor(t)
+
+	if err := v.SetMeasurementTimingBudget(50); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, mode := range []vl53l1x.DistanceMode{vl53l1x.Short, vl53l1x.Medium, vl53l1x.Long} {
+		if err := v.SetDistanceMode(mode); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, size := range []uint8{16, 8, 4} {
+			if err := v.SetROISize(size, size); err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := v.ReadSingle()
+
+			if err != nil {
+				t.Fatalf("%s %dx%d: %v", mode, size, size, err)
+			}
+
+			// single shot measurements start a new stream so have no wrap
+			// check
+			if !data.RangeStatus.IsValid() || math.Abs(float64(data.RangeMM)-500) > 50 {
+				t.Errorf("%s %dx%d: %s %dmm, want valid 500mm", mode, size, size,
+					data.RangeStatus, data.RangeMM)
+			}
+		}
+	}
+}
+
+func TestScenarioPauseResume(t *testing.T) {
+
+	v, _ := newSensor(t)
+
+	if err := v.StartContinuous(25); err != nil {
+		t.Fatal(err)
+	}
+
+	defer v.StopContinuous()
+
+	if _, err := v.Read(true); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := v.Pause(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !v.Paused() {
+		t.Error("Paused() = false after Pause()")
+	}
+
+	if _, err := v.Read(true); !errors.Is(err, vl53l1x.ErrNotRanging) {
+		t.Errorf("read while paused returned %v, want ErrNotRanging", err)
+	}
+
+	if err := v.Resume(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := v.Read(true)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !data.RangeStatus.IsValid() {
+		t.Errorf("status %s after Resume()", data.RangeStatus)
+	}
+}
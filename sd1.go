@@ -0,0 +1,44 @@
+package vl53l1x
+
+const (
+	// secondaryTargetSeparationMM is the minimum difference between the SD0
+	// and SD1 ranges for SD1 to be considered a separate target
+	secondaryTargetSeparationMM = 60
+	// secondaryTargetMinSignalRate is the minimum SD1 peak signal rate for
+	// SD1 to be considered a real return rather than noise, 0.5 MCPS in 9.7
+	// fixed point
+	secondaryTargetMinSignalRate = 0.5 * (1 << 7)
+)
+
+// SD1Data holds the results of the sensors second sensing period.  SD1 can
+// return a second target or indicate a wrap around condition
+type SD1Data struct {
+	RangeMM                 uint16
+	PeakSignalCountRateMCPS float32
+	AmbientCountRateMCPS    float32
+	// SigmaMM is the estimated standard deviation of the range
+	SigmaMM float32
+	// Raw holds the rates and sigma in the sensors fixed point formats, the
+	// only ones set when WithIntegerResults() is used
+	Raw RawRates
+}
+
+// HasSecondaryTarget is a heuristic reporting whether the SD1 results suggest
+// a second target at a different distance to the primary, such as an object
+// behind a glass window.  It requires SD1 results to be enabled with
+// WithSD1Results() and works with WithIntegerResults() as it uses the raw
+// signal rate
+func (r RangingData) HasSecondaryTarget() bool {
+
+	if r.SD1.RangeMM == 0 || r.SD1.Raw.PeakSignalCountRate < secondaryTargetMinSignalRate {
+		return false
+	}
+
+	diff := int(r.SD1.RangeMM) - int(r.RangeMM)
+
+	if diff < 0 {
+		diff = -diff
+	}
+
+	return diff >= secondaryTargetSeparationMM
+}
@@ -0,0 +1,56 @@
+package vl53l1x_test
+
+import (
+	"testing"
+
+	"github.com/swdee/go-vl53l1x"
+	"github.com/swdee/go-vl53l1x/sim"
+)
+
+func TestHasSecondaryTarget(t *testing.T) {
+
+	for _, tc := range []struct {
+		name string
+		opts []vl53l1x.Option
+	}{
+		{"float", []vl53l1x.Option{vl53l1x.WithSD1Results()}},
+		{"integer", []vl53l1x.Option{vl53l1x.WithSD1Results(), vl53l1x.WithIntegerResults()}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+
+			v, bus := newSensor(t, tc.opts...)
+
+			// a target seen through glass behind a closer reflection
+			sc := sim.DefaultScene()
+			sc.DistanceMM = 300
+			sc.SecondaryDistanceMM = 900
+			sc.SecondaryReflectance = 0.88
+			bus.SetScene(sc)
+
+			if err := v.StartContinuous(25); err != nil {
+				t.Fatal(err)
+			}
+
+			rData, err := v.Read(true)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !rData.HasSecondaryTarget() {
+				t.Errorf("secondary target not reported, SD1 %+v", rData.SD1)
+			}
+
+			sc.SecondaryDistanceMM = 0
+			bus.SetScene(sc)
+
+			if rData, err = v.Read(true); err != nil {
+				t.Fatal(err)
+			}
+
+			if rData.HasSecondaryTarget() {
+				t.Errorf("secondary target reported without one, SD1 %+v", rData.SD1)
+			}
+		})
+	}
+}
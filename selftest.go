@@ -0,0 +1,76 @@
+package vl53l1x
+
+import (
+	"context"
+	"fmt"
+)
+
+// SelfTestReport holds the results of SelfTest()
+type SelfTestReport struct {
+	ModelID uint16
+	System  SystemStatus
+	// Oscillator is the validation of the oscillator calibration values
+	Oscillator OscillatorCheck
+	// Measurement is a single shot measurement taken with the current
+	// settings
+	Measurement RangingData
+	// Passed is true when every check passed, otherwise Failures describes
+	// those that did not
+	Passed   bool
+	Failures []string
+}
+
+// SelfTest checks the sensor identifies and has booted, its oscillator
+// calibration values are in family and a single shot measurement completes
+// without a hardware failure.  Continuous ranging is stopped for the
+// measurement and restarted afterwards.  An error is returned when the sensor
+// can not be communicated with
+func (v *VL53L1X) SelfTest(ctx context.Context) (SelfTestReport, error) {
+
+	var r SelfTestReport
+	var err error
+
+	if r.ModelID, err = v.readReg16Bit(IDENTIFICATION_MODEL_ID); err != nil {
+		return r, err
+	}
+
+	if r.ModelID != ModelID {
+		r.Failures = append(r.Failures, fmt.Sprintf("model ID 0x%04X is not 0x%04X", r.ModelID, ModelID))
+	}
+
+	if r.System, err = v.GetSystemStatus(); err != nil {
+		return r, err
+	}
+
+	if !r.System.Booted {
+		r.Failures = append(r.Failures, "firmware has not booted")
+	}
+
+	r.Oscillator = v.CheckOscillator()
+	r.Failures = append(r.Failures, r.Oscillator.Problems...)
+
+	resume, err := v.suspendRanging()
+
+	if err != nil {
+		return r, err
+	}
+
+	r.Measurement, err = v.readSingle(ctx)
+
+	if rerr := resume(); err == nil {
+		err = rerr
+	}
+
+	if err != nil {
+		return r, err
+	}
+
+	if r.Measurement.RangeStatus.Severity() == SeverityFatal {
+		r.Failures = append(r.Failures, fmt.Sprintf("measurement %s: %s",
+			r.Measurement.RangeStatus, r.Measurement.DeviceError))
+	}
+
+	r.Passed = len(r.Failures) == 0
+
+	return r, nil
+}
@@ -1,6 +1,10 @@
 package vl53l1x
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
 
 // DistanceMode represents the selected ranging mode of sensor
 type DistanceMode int
@@ -14,123 +18,211 @@ const (
 	Long
 )
 
-// GetDistanceMode returns the sensors current DistanceMode setting
-func (v *VL53L1X) GetDistanceMode() DistanceMode {
-	return v.distanceMode
+// String implement Stringer interface for DistanceMode
+func (m DistanceMode) String() string {
+	switch m {
+	case Short:
+		return "short"
+	case Medium:
+		return "medium"
+	case Long:
+		return "long"
+	default:
+		return fmt.Sprintf("DistanceMode(%d)", int(m))
+	}
 }
 
-// SetDistanceMode configures the sensor for Short, Medium, or Long range.
-func (v *VL53L1X) SetDistanceMode(mode DistanceMode) error {
+// ParseDistanceMode returns the DistanceMode named short, medium or long,
+// case insensitive
+func ParseDistanceMode(s string) (DistanceMode, error) {
+
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "short":
+		return Short, nil
+	case "medium":
+		return Medium, nil
+	case "long":
+		return Long, nil
+	default:
+		return Short, fmt.Errorf("unknown distance mode %q, expected short, medium or long", s)
+	}
+}
 
-	// save the existing timing budget.
-	budget, err := v.GetMeasurementTimingBudget()
+// Set implements flag.Value so the mode can be given by name on the command
+// line with flag.Var()
+func (m *DistanceMode) Set(s string) error {
+
+	mode, err := ParseDistanceMode(s)
 
 	if err != nil {
 		return err
 	}
 
-	switch mode {
-	case Short:
-		// from VL53L1_preset_mode_standard_ranging_short_range()
+	*m = mode
 
-		// timing config
-		if err := v.writeReg(RANGE_CONFIG_VCSEL_PERIOD_A, 0x07); err != nil {
-			return err
-		}
-		if err := v.writeReg(RANGE_CONFIG_VCSEL_PERIOD_B, 0x05); err != nil {
-			return err
-		}
-		if err := v.writeReg(RANGE_CONFIG_VALID_PHASE_HIGH, 0x38); err != nil {
-			return err
-		}
+	return nil
+}
 
-		// dynamic config
-		if err := v.writeReg(SD_CONFIG_WOI_SD0, 0x07); err != nil {
-			return err
-		}
-		if err := v.writeReg(SD_CONFIG_WOI_SD1, 0x05); err != nil {
-			return err
-		}
-		if err := v.writeReg(SD_CONFIG_INITIAL_PHASE_SD0, 6); err != nil {
-			return err
-		}
-		if err := v.writeReg(SD_CONFIG_INITIAL_PHASE_SD1, 6); err != nil {
-			return err
-		}
+// UnmarshalText implements encoding.TextUnmarshaler so the mode can be given
+// by name in configuration files
+func (m *DistanceMode) UnmarshalText(text []byte) error {
+	return m.Set(string(text))
+}
 
-	case Medium:
-		// from VL53L1_preset_mode_standard_ranging()
+// UnmarshalJSON accepts the mode by name or by number, as written by
+// earlier versions of the driver
+func (m *DistanceMode) UnmarshalJSON(data []byte) error {
 
-		// timing config
-		if err := v.writeReg(RANGE_CONFIG_VCSEL_PERIOD_A, 0x0B); err != nil {
-			return err
-		}
-		if err := v.writeReg(RANGE_CONFIG_VCSEL_PERIOD_B, 0x09); err != nil {
-			return err
-		}
-		if err := v.writeReg(RANGE_CONFIG_VALID_PHASE_HIGH, 0x78); err != nil {
-			return err
-		}
+	var n int
 
-		// dynamic config
-		if err := v.writeReg(SD_CONFIG_WOI_SD0, 0x0B); err != nil {
-			return err
-		}
-		if err := v.writeReg(SD_CONFIG_WOI_SD1, 0x09); err != nil {
-			return err
-		}
-		if err := v.writeReg(SD_CONFIG_INITIAL_PHASE_SD0, 10); err != nil {
-			return err
-		}
-		if err := v.writeReg(SD_CONFIG_INITIAL_PHASE_SD1, 10); err != nil {
-			return err
+	if err := json.Unmarshal(data, &n); err == nil {
+		switch mode := DistanceMode(n); mode {
+		case Short, Medium, Long:
+			*m = mode
+			return nil
+		default:
+			return fmt.Errorf("unknown distance mode %d, expected %d to %d", n, Short, Long)
 		}
+	}
 
-	case Long:
-		// from VL53L1_preset_mode_standard_ranging_long_range()
+	var s string
 
-		// timing config
-		if err := v.writeReg(RANGE_CONFIG_VCSEL_PERIOD_A, 0x0F); err != nil {
-			return err
-		}
-		if err := v.writeReg(RANGE_CONFIG_VCSEL_PERIOD_B, 0x0D); err != nil {
-			return err
-		}
-		if err := v.writeReg(RANGE_CONFIG_VALID_PHASE_HIGH, 0xB8); err != nil {
-			return err
-		}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("distance mode must be a name or number: %w", err)
+	}
 
-		// dynamic config
-		if err := v.writeReg(SD_CONFIG_WOI_SD0, 0x0F); err != nil {
-			return err
-		}
-		if err := v.writeReg(SD_CONFIG_WOI_SD1, 0x0D); err != nil {
-			return err
+	return m.Set(s)
+}
+
+// GetDistanceMode returns the sensors current DistanceMode setting as cached by
+// the driver, use GetDistanceModeFromDevice() to read it back from the sensor
+func (v *VL53L1X) GetDistanceMode() DistanceMode {
+	return v.distanceMode
+}
+
+// GetDistanceModeFromDevice infers the DistanceMode from the
+// RANGE_CONFIG_VCSEL_PERIOD_A register and updates the cached mode if it
+// differs, such as after external register writes or a sensor reset
+func (v *VL53L1X) GetDistanceModeFromDevice() (DistanceMode, error) {
+
+	vcselA, err := v.readReg(RANGE_CONFIG_VCSEL_PERIOD_A)
+
+	if err != nil {
+		return v.distanceMode, err
+	}
+
+	mode, ok := v.modeFromVCSEL(vcselA)
+
+	if !ok {
+		return v.distanceMode, fmt.Errorf("VCSEL period A 0x%02X does not match a known distance mode",
+			vcselA)
+	}
+
+	if mode != v.distanceMode {
+		v.log.Printf("Distance mode on device %s differs from cached %s", mode, v.distanceMode)
+		v.distanceMode = mode
+		v.emitConfigChanged("distance mode")
+	}
+
+	return mode, nil
+}
+
+// modeFromVCSEL returns the distance mode whose profile uses the VCSEL period.
+// The cached mode is preferred as custom profiles may share a period
+func (v *VL53L1X) modeFromVCSEL(vcselA uint8) (DistanceMode, bool) {
+
+	if p, ok := v.modeProfile(v.distanceMode); ok && p.VCSELPeriodA == vcselA {
+		return v.distanceMode, true
+	}
+
+	for mode, p := range v.profiles {
+		if p.VCSELPeriodA == vcselA {
+			return mode, true
 		}
-		if err := v.writeReg(SD_CONFIG_INITIAL_PHASE_SD0, 14); err != nil {
-			return err
+	}
+
+	for mode, p := range defaultProfiles {
+		if p.VCSELPeriodA == vcselA {
+			return mode, true
 		}
-		if err := v.writeReg(SD_CONFIG_INITIAL_PHASE_SD1, 14); err != nil {
+	}
+
+	return v.distanceMode, false
+}
+
+// SetDistanceMode configures the sensor for Short, Medium, or Long range.  The
+// timing budget currently on the sensor is kept and must be legal for the new
+// mode, otherwise a *ModeBudgetError is returned unless WithBudgetAutoAdjust()
+// is set
+func (v *VL53L1X) SetDistanceMode(mode DistanceMode) error {
+
+	// save the existing timing budget, preferring the requested value as
+	// reading it back from the device rounds down
+	budget := v.timingBudget
+
+	if budget == 0 {
+		var err error
+
+		if budget, err = v.GetMeasurementTimingBudget(); err != nil {
 			return err
 		}
+	}
 
-	default:
+	budget, err := v.checkModeBudget(mode, budget)
+
+	if err != nil {
+		return err
+	}
+
+	return v.setDistanceMode(mode, budget)
+}
+
+// setDistanceMode writes the mode settings followed by the timing budget
+// without validating the combination
+func (v *VL53L1X) setDistanceMode(mode DistanceMode, budget uint32) error {
+
+	p, ok := v.modeProfile(mode)
+
+	if !ok {
 		return fmt.Errorf("unrecognized distance mode")
 	}
 
+	if err := p.validate(); err != nil {
+		return err
+	}
+
+	if err := v.writeProfile(p); err != nil {
+		return err
+	}
+
 	// reapply the timing budget
-	if err := v.SetMeasurementTimingBudget(budget); err != nil {
+	if err := v.setMeasurementTimingBudget(budget); err != nil {
 		return err
 	}
 
 	v.distanceMode = mode
+	v.configChanged("distance mode")
 	return nil
 }
 
 // SetMeasurementTimingBudget sets the timing budget in milliseconds for one
-// measurement, which is the time allowed for sensor to take one measurement
+// measurement, which is the time allowed for sensor to take one measurement.
+// The budget must be legal for the current distance mode
 func (v *VL53L1X) SetMeasurementTimingBudget(budget uint32) error {
 
+	budget, err := v.checkModeBudget(v.distanceMode, budget)
+
+	if err != nil {
+		return err
+	}
+
+	return v.setMeasurementTimingBudget(budget)
+}
+
+// setMeasurementTimingBudget writes the timing budget without validating it
+// against the distance mode
+func (v *VL53L1X) setMeasurementTimingBudget(budget uint32) error {
+
 	// convert milliseconds to microseconds
 	budgetUs := budget * 1000
 
@@ -203,10 +295,15 @@ func (v *VL53L1X) SetMeasurementTimingBudget(budget uint32) error {
 		return err
 	}
 
+	v.timingBudget = budget
+	v.configChanged("timing budget")
 	return nil
 }
 
 // GetMeasurementTimingBudget returns the current timing budget in milliseconds
+// as read back from the sensor, rounded to the nearest millisecond.  The
+// timeout registers hold 8 significant bits so budgets above about 90ms may
+// read back up to 13ms lower than set, with the maximum budget being 2204ms
 func (v *VL53L1X) GetMeasurementTimingBudget() (uint32, error) {
 
 	vcselA, err := v.readReg(RANGE_CONFIG_VCSEL_PERIOD_A)
@@ -223,7 +320,11 @@ func (v *VL53L1X) GetMeasurementTimingBudget() (uint32, error) {
 	}
 
 	rangeTimeoutUs := v.timeoutMclksToMicroseconds(v.decodeTimeout(encoded), macroPeriodUs)
-	budgetMs := (2*rangeTimeoutUs + TimingGuard) / 1000
+
+	// the encoded timeout never exceeds that requested so rounding to the
+	// nearest millisecond returns small budgets exactly and no budget above
+	// that set
+	budgetMs := (2*rangeTimeoutUs + TimingGuard + 500) / 1000
 
 	return budgetMs, nil
 }
@@ -256,16 +357,18 @@ func (v *VL53L1X) encodeTimeout(timeoutMclks uint32) uint16 {
 
 // timeoutMclksToMicroseconds convert sequence step timeout from macro periods
 // to microseconds with given macro period in microseconds (12.12 format)
-// based on VL53L1_calc_timeout_us()
+// based on VL53L1_calc_timeout_us(), which like it calculates in 64 bits as
+// timeouts above about one second overflow 32 bits
 func (v *VL53L1X) timeoutMclksToMicroseconds(timeoutMclks, macroPeriodUs uint32) uint32 {
-	return ((timeoutMclks * macroPeriodUs) + 0x800) >> 12
+	return uint32((uint64(timeoutMclks)*uint64(macroPeriodUs) + 0x800) >> 12)
 }
 
 // timeoutMicrosecondsToMclks convert sequence step timeout from microseconds
 // to macro periods with given macro period in microseconds (12.12 format)
-// based on VL53L1_calc_timeout_mclks()
+// based on VL53L1_calc_timeout_mclks(), calculated in 64 bits as timeouts
+// above about one second overflow 32 bits
 func (v *VL53L1X) timeoutMicrosecondsToMclks(timeoutUs, macroPeriodUs uint32) uint32 {
-	return (((timeoutUs << 12) + (macroPeriodUs >> 1)) / macroPeriodUs)
+	return uint32(((uint64(timeoutUs) << 12) + uint64(macroPeriodUs>>1)) / uint64(macroPeriodUs))
 }
 
 // calcMacroPeriod calculate macro period in microseconds (12.12 format) with
@@ -0,0 +1,50 @@
+package vl53l1x
+
+import (
+	"testing"
+	"testing/quick"
+)
+
+func TestEncodeDecodeTimeoutRoundTrip(t *testing.T) {
+
+	var v VL53L1X
+
+	// register values in the form encodeTimeout() produces, with the most
+	// significant bit of the mantissa set whenever the exponent is not 0
+	canonical := func(exp, mantissa uint8) bool {
+
+		exp %= 24
+
+		if exp > 0 {
+			mantissa |= 0x80
+		}
+
+		reg := uint16(exp)<<8 | uint16(mantissa)
+
+		return v.encodeTimeout(v.decodeTimeout(reg)) == reg
+	}
+
+	if err := quick.Check(canonical, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDecodeEncodeTimeoutPrecision(t *testing.T) {
+
+	var v VL53L1X
+
+	// the 8 bit mantissa truncates, so the timeout decoded is at most the one
+	// encoded and within one unit of the lowest mantissa bit kept
+	precision := func(mclks uint32) bool {
+
+		mclks = mclks%(1<<31) + 1
+		got := v.decodeTimeout(v.encodeTimeout(mclks))
+		unit := uint32(1) << (v.encodeTimeout(mclks) >> 8)
+
+		return got <= mclks && mclks-got < unit
+	}
+
+	if err := quick.Check(precision, &quick.Config{MaxCount: 10000}); err != nil {
+		t.Error(err)
+	}
+}
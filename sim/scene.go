@@ -0,0 +1,239 @@
+package sim
+
+import (
+	"math"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+const (
+	// signalConstant scales the return signal rate in MCPS for a target of
+	// reflectance 1.0 at 1m using the full SPAD array
+	signalConstant = 10.0
+	// maxSignalMCPS is the rate at which the SPAD array saturates
+	maxSignalMCPS = 40.0
+	// minRangeMM is the closest distance the sensor can measure
+	minRangeMM = 40
+	// whiteReflectance is the reflectance of the white target the datasheet
+	// maximum ranges are specified against
+	whiteReflectance = 0.88
+)
+
+// raw RESULT_RANGE_STATUS codes reported by the sensor
+const (
+	rawSignalFail = 4
+	rawSigmaFail  = 6
+	rawValid      = 9
+	rawMinRange   = 13
+)
+
+// Scene describes what the simulated sensor is looking at
+type Scene struct {
+	// DistanceMM to the primary target
+	DistanceMM float64
+	// Reflectance of the primary target from 0 to 1, where 0.88 is white and
+	// 0.17 is grey
+	Reflectance float64
+	// AmbientMCPS is the ambient light count rate over the full SPAD array,
+	// around 0 in the dark and 10 or more under strong ambient light
+	AmbientMCPS float64
+	// SecondaryDistanceMM to a second target reported in the SD1 results,
+	// such as an object behind glass.  Zero for none
+	SecondaryDistanceMM float64
+	// SecondaryReflectance of the second target
+	SecondaryReflectance float64
+}
+
+// DefaultScene returns a white target at 500mm in the dark
+func DefaultScene() Scene {
+	return Scene{
+		DistanceMM:  500,
+		Reflectance: whiteReflectance,
+		AmbientMCPS: 0.1,
+	}
+}
+
+// target is the simulated return from one target
+type target struct {
+	status  byte
+	rangeMM float64
+	signal  float64
+	ambient float64
+	sigma   float64
+}
+
+// currentScene returns the scene at the current time
+func (s *Sensor) currentScene() Scene {
+
+	if s.sceneFunc != nil {
+		return s.sceneFunc(time.Since(s.created))
+	}
+
+	return s.scene
+}
+
+// measure generates a measurement from the scene and stores it in the result
+// registers
+func (s *Sensor) measure() {
+
+	sc := s.currentScene()
+
+	primary := s.simulate(sc.DistanceMM, sc.Reflectance, sc.AmbientMCPS)
+	s.target = primary
+
+	spads := s.effectiveSPADs()
+
+	s.regs[vl53l1x.RESULT_RANGE_STATUS] = primary.status
+	s.regs[vl53l1x.RESULT_REPORT_STATUS] = 0
+	s.regs[vl53l1x.RESULT_STREAM_COUNT] = s.stream
+	s.put16(vl53l1x.RESULT_DSS_ACTUAL_EFFECTIVE_SPADS_SD0, uint16(spads))
+	s.put16(vl53l1x.RESULT_PEAK_SIGNAL_COUNT_RATE_MCPS_SD0, rateToFixed(primary.signal))
+	s.put16(vl53l1x.RESULT_AMBIENT_COUNT_RATE_MCPS_SD0, rateToFixed(primary.ambient))
+	s.put16(vl53l1x.RESULT_SIGMA_SD0, sigmaToFixed(primary.sigma))
+	s.put16(vl53l1x.RESULT_PHASE_SD0, phaseFor(primary))
+	s.put16(vl53l1x.RESULT_FINAL_CROSSTALK_CORRECTED_RANGE_MM_SD0, s.rangeToRaw(primary.rangeMM))
+	s.put16(vl53l1x.RESULT_PEAK_SIGNAL_COUNT_RATE_CROSSTALK_CORRECTED_MCPS_SD0, rateToFixed(primary.signal))
+
+	// SD1 results
+	for reg := vl53l1x.RESULT_DSS_ACTUAL_EFFECTIVE_SPADS_SD1; reg < vl53l1x.RESULT_SPARE_0_SD1; reg++ {
+		s.regs[reg] = 0
+	}
+
+	if sc.SecondaryDistanceMM > 0 {
+		second := s.simulate(sc.SecondaryDistanceMM, sc.SecondaryReflectance, sc.AmbientMCPS)
+
+		s.put16(vl53l1x.RESULT_DSS_ACTUAL_EFFECTIVE_SPADS_SD1, uint16(spads))
+		s.put16(vl53l1x.RESULT_PEAK_SIGNAL_COUNT_RATE_MCPS_SD1, rateToFixed(second.signal))
+		s.put16(vl53l1x.RESULT_AMBIENT_COUNT_RATE_MCPS_SD1, rateToFixed(second.ambient))
+		s.put16(vl53l1x.RESULT_SIGMA_SD1, sigmaToFixed(second.sigma))
+		s.put16(vl53l1x.RESULT_PHASE_SD1, phaseFor(second))
+		s.put16(vl53l1x.RESULT_FINAL_CROSSTALK_CORRECTED_RANGE_MM_SD1, s.rangeToRaw(second.rangeMM))
+	}
+}
+
+// simulate calculates the return from a target at the given distance.  Signal
+// falls with the square of distance and scales with the number of SPADs in
+// the ROI, sigma grows with ambient light relative to signal and shrinks with
+// longer timing budgets, and targets beyond the achievable maximum range for
+// the distance mode fail on signal
+func (s *Sensor) simulate(distanceMM, reflectance, ambientMCPS float64) target {
+
+	roiFrac := float64(s.roiSPADs()) / 256
+	budgetMs := float64(s.budgetUs()) / 1000
+
+	t := target{
+		ambient: ambientMCPS * roiFrac,
+	}
+
+	if distanceMM < 1 {
+		distanceMM = 1
+	}
+
+	meters := distanceMM / 1000
+	t.signal = math.Min(signalConstant*reflectance*roiFrac/(meters*meters), maxSignalMCPS)
+
+	if t.signal > 0 {
+		t.sigma = 25 * math.Sqrt(t.signal+t.ambient) / (t.signal * math.Sqrt(budgetMs/33))
+	} else {
+		t.sigma = math.MaxUint16 / 4
+	}
+
+	t.rangeMM = distanceMM + s.rand.NormFloat64()*math.Min(t.sigma, 200)
+
+	maxRange := float64(vl53l1x.EstimateMaxRange(s.distanceMode(), uint32(budgetMs),
+		float32(ambientMCPS))) * math.Sqrt(reflectance/whiteReflectance)
+
+	sigmaLimit := float64(s.get16(vl53l1x.RANGE_CONFIG_SIGMA_THRESH)) / 4
+
+	switch {
+	case distanceMM < minRangeMM:
+		t.status = rawMinRange
+	case distanceMM > maxRange:
+		t.status = rawSignalFail
+	case sigmaLimit > 0 && t.sigma > sigmaLimit:
+		t.status = rawSigmaFail
+	default:
+		t.status = rawValid
+	}
+
+	if t.rangeMM < 0 {
+		t.rangeMM = 0
+	}
+
+	return t
+}
+
+// distanceMode infers the distance mode from the programmed VCSEL period
+func (s *Sensor) distanceMode() vl53l1x.DistanceMode {
+
+	switch s.regs[vl53l1x.RANGE_CONFIG_VCSEL_PERIOD_A] {
+	case 0x07:
+		return vl53l1x.Short
+	case 0x0F:
+		return vl53l1x.Long
+	default:
+		return vl53l1x.Medium
+	}
+}
+
+// effectiveSPADs returns the number of SPADs enabled by dynamic SPAD selection
+// in 8.8 fixed point, being the number requested by the driver limited to
+// those in the ROI.  At most 255 are reported as the full 16x16 array can not
+// be represented and 0xFFFF is treated by the driver as a bus fault
+func (s *Sensor) effectiveSPADs() uint32 {
+
+	spads := uint32(s.get16(vl53l1x.DSS_CONFIG_MANUAL_EFFECTIVE_SPADS_SELECT))
+	roi := uint32(min(s.roiSPADs(), 255)) << 8
+
+	if spads == 0 || spads > roi {
+		spads = roi
+	}
+
+	return spads
+}
+
+// roiSPADs returns the number of SPADs in the programmed ROI
+func (s *Sensor) roiSPADs() int {
+	xy := s.regs[vl53l1x.ROI_CONFIG_USER_ROI_REQUESTED_GLOBAL_XY_SIZE]
+	return int(xy&0x0F+1) * int(xy>>4+1)
+}
+
+// rangeToRaw applies the part to part offset and reverses the drivers gain
+// correction to produce the raw range register value
+func (s *Sensor) rangeToRaw(rangeMM float64) uint16 {
+
+	offset := float64(int16(s.get16(vl53l1x.ALGO_PART_TO_PART_RANGE_OFFSET_MM)<<3) >> 5)
+	raw := (rangeMM - offset) * 0x0800 / 2011
+
+	if raw < 0 {
+		return 0
+	}
+
+	if raw > 0xFFFF {
+		return 0xFFFF
+	}
+
+	return uint16(raw)
+}
+
+// rateToFixed converts a count rate in MCPS to 9.7 fixed point
+func rateToFixed(mcps float64) uint16 {
+	return uint16(math.Min(mcps*128, 0xFFFF))
+}
+
+// sigmaToFixed converts sigma in millimeters to 14.2 fixed point
+func sigmaToFixed(mm float64) uint16 {
+	return uint16(math.Min(mm*4, 0xFFFF))
+}
+
+// phaseFor returns a phase value that scales with range so consistency checks
+// on valid measurements pass
+func phaseFor(t target) uint16 {
+
+	if t.status != rawValid {
+		return 0
+	}
+
+	return uint16(math.Min(t.rangeMM*4+1, 0xFFFF))
+}
@@ -0,0 +1,498 @@
+// Package sim provides a simulated VL53L1X sensor that implements the
+// vl53l1x.Bus interface at the register level.  Measurements are generated
+// from a virtual Scene so the driver and code built on it can be exercised
+// against plausible data without hardware.
+package sim
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+const (
+	// regSize is the size of the simulated register space
+	regSize = 0x0200
+	// fastOscFrequency is the simulated OSC_MEASURED_FAST_OSC_FREQUENCY
+	fastOscFrequency = 0xB2E4
+	// oscCalibrateVal is the simulated RESULT_OSC_CALIBRATE_VAL
+	oscCalibrateVal = 0x03F0
+)
+
+// mode_start values written to SYSTEM_MODE_START
+const (
+	modeSingleShot = byte(vl53l1x.ModeStartSingleShot)
+	modeBackToBack = byte(vl53l1x.ModeStartBackToBack)
+	modeTimed      = byte(vl53l1x.ModeStartTimed)
+	modeAbort      = byte(vl53l1x.ModeStartAbort)
+)
+
+// Sensor is a simulated VL53L1X sensor
+type Sensor struct {
+	mu sync.Mutex
+
+	addr uint8
+	regs [regSize]byte
+	// ptr is the register address the next read starts from
+	ptr uint16
+
+	scene     Scene
+	sceneFunc func(elapsed time.Duration) Scene
+	created   time.Time
+
+	// ranging state
+	mode       byte
+	start      time.Time
+	frames     int
+	ready      bool
+	cleared    bool
+	stream     uint8
+	haveStream bool
+	// target is the primary return of the last measurement, compared with
+	// the distance thresholds
+	target target
+
+	// unplugged is set while the sensor is disconnected from the bus
+	unplugged bool
+
+	rand *rand.Rand
+}
+
+// New returns a simulated sensor at the given address with a white target at
+// 500mm in the dark
+func New(addr uint8) *Sensor {
+
+	s := &Sensor{
+		addr:    addr,
+		scene:   DefaultScene(),
+		created: time.Now(),
+		rand:    rand.New(rand.NewSource(1)),
+	}
+
+	s.reset(addr)
+
+	return s
+}
+
+// SetScene sets a static scene for the sensor to measure
+func (s *Sensor) SetScene(sc Scene) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.scene = sc
+	s.sceneFunc = nil
+}
+
+// SetSceneFunc sets a function returning the scene at the time elapsed since
+// the sensor was created, used to script target motion
+func (s *Sensor) SetSceneFunc(f func(elapsed time.Duration) Scene) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sceneFunc = f
+}
+
+// SetSeed seeds the random noise applied to measurements so runs are
+// reproducible
+func (s *Sensor) SetSeed(seed int64) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rand = rand.New(rand.NewSource(seed))
+}
+
+// SetConnected connects or disconnects the simulated sensor from the bus.
+// While disconnected transfers fail with vl53l1x.ErrNACK, and on reconnect
+// the registers return to their power on state as if the sensor was power
+// cycled, including the I2C address returning to the default vl53l1x.Address
+func (s *Sensor) SetConnected(connected bool) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if connected && s.unplugged {
+		s.reset(vl53l1x.Address)
+	}
+
+	s.unplugged = !connected
+}
+
+// GetAddr returns the I2C address of the simulated sensor
+func (s *Sensor) GetAddr() uint8 {
+	return s.addr
+}
+
+// GetDev returns the device path of the simulated sensor
+func (s *Sensor) GetDev() string {
+	return "sim"
+}
+
+// Close the simulated sensor
+func (s *Sensor) Close() error {
+	return nil
+}
+
+// Open returns a connection to the simulated sensor at the given address if
+// its address register matches, for use with vl53l1x.WithBusOpener() so
+// SetAddress() works
+func (s *Sensor) Open(addr uint8, dev string) (vl53l1x.Bus, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.regs[vl53l1x.I2C_SLAVE_DEVICE_ADDRESS]&0x7F != addr {
+		return nil, fmt.Errorf("no device at address 0x%02X", addr)
+	}
+
+	return &conn{s: s, addr: addr}, nil
+}
+
+// conn is a connection to the simulated sensor at an address, which stops
+// being acknowledged when the sensor's address changes
+type conn struct {
+	s    *Sensor
+	addr uint8
+}
+
+// GetAddr returns the address of the connection
+func (c *conn) GetAddr() uint8 { return c.addr }
+
+// GetDev returns the device path of the simulated sensor
+func (c *conn) GetDev() string { return "sim" }
+
+// Close the connection
+func (c *conn) Close() error { return nil }
+
+// WriteBytes writes to the simulated registers as Sensor.WriteBytes()
+func (c *conn) WriteBytes(buf []byte) (int, error) { return c.s.write(c.addr, buf) }
+
+// ReadBytes reads the simulated registers as Sensor.ReadBytes()
+func (c *conn) ReadBytes(buf []byte) (int, error) { return c.s.read(c.addr, buf) }
+
+// WriteBytes writes to the simulated registers.  The first two bytes are the
+// register address and any remaining bytes are written to consecutive
+// registers
+func (s *Sensor) WriteBytes(buf []byte) (int, error) {
+	return s.write(s.addr, buf)
+}
+
+// write performs a write addressed to addr
+func (s *Sensor) write(addr uint8, buf []byte) (int, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.present(addr) {
+		return 0, fmt.Errorf("sim write: %w", vl53l1x.ErrNACK)
+	}
+
+	if len(buf) < 2 {
+		return 0, fmt.Errorf("write requires a register address")
+	}
+
+	reg := uint16(buf[0])<<8 | uint16(buf[1])
+	s.ptr = reg
+
+	for i, b := range buf[2:] {
+		s.writeReg(reg+uint16(i), b)
+	}
+
+	return len(buf), nil
+}
+
+// ReadBytes reads consecutive registers starting from the last address
+// written
+func (s *Sensor) ReadBytes(buf []byte) (int, error) {
+	return s.read(s.addr, buf)
+}
+
+// read performs a read addressed to addr
+func (s *Sensor) read(addr uint8, buf []byte) (int, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.present(addr) {
+		return 0, fmt.Errorf("sim read: %w", vl53l1x.ErrNACK)
+	}
+
+	s.update()
+
+	for i := range buf {
+		buf[i] = s.readReg(s.ptr + uint16(i))
+	}
+
+	return len(buf), nil
+}
+
+// present reports whether the sensor acknowledges transfers to addr
+func (s *Sensor) present(addr uint8) bool {
+	return !s.unplugged && s.regs[vl53l1x.I2C_SLAVE_DEVICE_ADDRESS]&0x7F == addr
+}
+
+// reset returns the registers to their power on state with the sensor at
+// addr
+func (s *Sensor) reset(addr uint8) {
+
+	s.regs = [regSize]byte{}
+	s.regs[vl53l1x.I2C_SLAVE_DEVICE_ADDRESS] = addr
+	s.put16(vl53l1x.IDENTIFICATION_MODEL_ID, vl53l1x.ModelID)
+	s.put16(vl53l1x.OSC_MEASURED_FAST_OSC_FREQUENCY, fastOscFrequency)
+	s.put16(vl53l1x.RESULT_OSC_CALIBRATE_VAL, oscCalibrateVal)
+	s.regs[vl53l1x.FIRMWARE_SYSTEM_STATUS] = 0x01
+	s.regs[vl53l1x.GPIO_HV_MUX_CTRL] = 0x11
+	// interrupt on each new sample
+	s.regs[vl53l1x.SYSTEM_INTERRUPT_CONFIG_GPIO] = 0x20
+	s.regs[vl53l1x.RANGE_CONFIG_VCSEL_PERIOD_A] = 0x0B
+	s.regs[vl53l1x.RANGE_CONFIG_VCSEL_PERIOD_B] = 0x09
+	// range timeouts equivalent to a 33ms timing budget
+	s.put16(vl53l1x.RANGE_CONFIG_TIMEOUT_MACROP_A, 0x00B7)
+	s.put16(vl53l1x.RANGE_CONFIG_TIMEOUT_MACROP_B, 0x00DC)
+	s.regs[vl53l1x.ROI_CONFIG_USER_ROI_CENTRE_SPAD] = 199
+	s.regs[vl53l1x.ROI_CONFIG_USER_ROI_REQUESTED_GLOBAL_XY_SIZE] = 0xFF
+
+	s.mode = modeAbort
+	s.ready = false
+}
+
+// writeReg writes a single register handling those with side effects
+func (s *Sensor) writeReg(reg uint16, val byte) {
+
+	if reg >= regSize {
+		return
+	}
+
+	switch reg {
+	case vl53l1x.SOFT_RESET:
+		// a soft reset keeps the address assigned since power on
+		if val == 0x00 {
+			s.reset(s.regs[vl53l1x.I2C_SLAVE_DEVICE_ADDRESS] & 0x7F)
+		}
+
+	case vl53l1x.SYSTEM_MODE_START:
+		s.startMode(val)
+
+	case vl53l1x.SYSTEM_INTERRUPT_CLEAR:
+		if val&0x01 != 0 {
+			s.ready = false
+			s.cleared = true
+		}
+
+	case vl53l1x.GPIO_TIO_HV_STATUS:
+		// status is generated on read
+		return
+	}
+
+	s.regs[reg] = val
+}
+
+// readReg reads a single register generating those that are dynamic
+func (s *Sensor) readReg(reg uint16) byte {
+
+	if reg >= regSize {
+		return 0
+	}
+
+	if reg == vl53l1x.GPIO_TIO_HV_STATUS {
+		// bit 0 follows the interrupt output which is active low unless
+		// bit 4 of GPIO_HV_MUX_CTRL is set
+		level := s.ready
+
+		if s.regs[vl53l1x.GPIO_HV_MUX_CTRL]&0x10 != 0 {
+			level = !level
+		}
+
+		if level {
+			return 0x03
+		}
+
+		return 0x02
+	}
+
+	return s.regs[reg]
+}
+
+// startMode handles a write to SYSTEM_MODE_START
+func (s *Sensor) startMode(val byte) {
+
+	s.mode = val
+
+	switch val {
+	case modeSingleShot, modeBackToBack, modeTimed:
+		s.start = time.Now()
+		s.frames = 0
+		s.ready = false
+		s.cleared = true
+		s.haveStream = false
+	default:
+		s.ready = false
+	}
+}
+
+// update generates a new measurement when ranging and the next frame is due
+func (s *Sensor) update() {
+
+	if s.mode != modeSingleShot && s.mode != modeBackToBack && s.mode != modeTimed {
+		return
+	}
+
+	frame := s.frameDuration()
+
+	if frame <= 0 {
+		return
+	}
+
+	due := int(time.Since(s.start) / frame)
+
+	if s.mode == modeSingleShot && due > 1 {
+		due = 1
+	}
+
+	if due <= s.frames || !s.cleared {
+		return
+	}
+
+	lost := due - s.frames - 1
+	s.frames = due
+	s.advanceStream(lost + 1)
+	s.measure()
+
+	if s.mode == modeSingleShot {
+		s.mode = modeAbort
+	}
+
+	// with a distance threshold set the measurement is taken but the
+	// interrupt is only raised when it meets the window
+	if !s.thresholdMet() {
+		return
+	}
+
+	s.ready = true
+	s.cleared = false
+}
+
+// thresholdMet reports whether the last measurement raises the interrupt
+// under SYSTEM_INTERRUPT_CONFIG_GPIO, where bit 5 interrupts on every new
+// sample, otherwise bits 0-1 select the distance window, bits 2-3 the signal
+// rate window, bit 7 requires both to be met and bit 6 interrupts when there
+// is no target
+func (s *Sensor) thresholdMet() bool {
+
+	cfg := s.regs[vl53l1x.SYSTEM_INTERRUPT_CONFIG_GPIO]
+
+	if cfg&0x20 != 0 {
+		return true
+	}
+
+	if s.target.status != rawValid {
+		return cfg&0x40 != 0
+	}
+
+	distance := windowMet(cfg&0x03, s.target.rangeMM,
+		float64(s.get16(vl53l1x.SYSTEM_THRESH_LOW)),
+		float64(s.get16(vl53l1x.SYSTEM_THRESH_HIGH)))
+
+	// rate thresholds are in 9.7 fixed point MCPS
+	rate := windowMet(cfg>>2&0x03, s.target.signal,
+		float64(s.get16(vl53l1x.SYSTEM_THRESH_RATE_LOW))/128,
+		float64(s.get16(vl53l1x.SYSTEM_THRESH_RATE_HIGH))/128)
+
+	if cfg&0x80 != 0 {
+		return distance && rate
+	}
+
+	return distance || rate
+}
+
+// windowMet reports whether val meets the threshold window
+func windowMet(window byte, val, low, high float64) bool {
+
+	switch window {
+	case 0:
+		return val < low
+	case 1:
+		return val > high
+	case 2:
+		return val < low || val > high
+	default:
+		return val >= low && val <= high
+	}
+}
+
+// advanceStream increments the stream count by n frames, wrapping from 255
+// back to 128 as the sensor does
+func (s *Sensor) advanceStream(n int) {
+
+	if !s.haveStream {
+		s.stream = 0
+		s.haveStream = true
+		n--
+	}
+
+	for i := 0; i < n; i++ {
+		if s.stream == 255 {
+			s.stream = 128
+		} else {
+			s.stream++
+		}
+	}
+}
+
+// frameDuration returns the time between measurements for the current mode
+func (s *Sensor) frameDuration() time.Duration {
+
+	budget := time.Duration(s.budgetUs()) * time.Microsecond
+
+	if s.mode != modeTimed {
+		return budget
+	}
+
+	period := time.Duration(s.get32(vl53l1x.SYSTEM_INTERMEASUREMENT_PERIOD)/oscCalibrateVal) * time.Millisecond
+
+	if period < budget {
+		return budget
+	}
+
+	return period
+}
+
+// budgetUs calculates the timing budget in microseconds from the range
+// timeout and VCSEL period registers as the driver programmed them
+func (s *Sensor) budgetUs() uint32 {
+
+	vcsel := uint32(s.regs[vl53l1x.RANGE_CONFIG_VCSEL_PERIOD_A])
+	pllPeriodUs := (uint32(1) << 30) / fastOscFrequency
+	macroPeriodUs := 2304 * pllPeriodUs
+	macroPeriodUs >>= 6
+	macroPeriodUs *= (vcsel + 1) << 1
+	macroPeriodUs >>= 6
+
+	encoded := s.get16(vl53l1x.RANGE_CONFIG_TIMEOUT_MACROP_A)
+	mclks := (uint32(encoded&0xFF) << (encoded >> 8)) + 1
+	rangeUs := uint32((uint64(mclks)*uint64(macroPeriodUs) + 0x800) >> 12)
+
+	return 2*rangeUs + vl53l1x.TimingGuard
+}
+
+// put16 stores a 16 bit big endian value in the registers
+func (s *Sensor) put16(reg uint16, val uint16) {
+	s.regs[reg] = byte(val >> 8)
+	s.regs[reg+1] = byte(val)
+}
+
+// get16 returns a 16 bit big endian value from the registers
+func (s *Sensor) get16(reg uint16) uint16 {
+	return uint16(s.regs[reg])<<8 | uint16(s.regs[reg+1])
+}
+
+// get32 returns a 32 bit big endian value from the registers
+func (s *Sensor) get32(reg uint16) uint32 {
+	return uint32(s.regs[reg])<<24 | uint32(s.regs[reg+1])<<16 |
+		uint32(s.regs[reg+2])<<8 | uint32(s.regs[reg+3])
+}
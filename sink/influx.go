@@ -0,0 +1,228 @@
+package sink
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+// InfluxConfig holds the settings for an Influx sink
+type InfluxConfig struct {
+	// URL of the write endpoint including query parameters, eg:
+	// http://localhost:8086/api/v2/write?org=home&bucket=sensors&precision=ns
+	URL string
+	// Token is sent as the Authorization header when set
+	Token string
+	// Writer receives the line protocol instead of the URL when set, such as
+	// a file or UDP connection
+	Writer io.Writer
+	// Measurement name, defaults to "vl53l1x"
+	Measurement string
+	// Tags added to every point, see SensorTags()
+	Tags map[string]string
+	// BatchSize is the number of points buffered before writing, defaults
+	// to 100
+	BatchSize int
+	// FlushInterval writes a partial batch once its oldest point is this
+	// old, defaults to 10 seconds
+	FlushInterval time.Duration
+	// Client used for HTTP writes, defaults to a client with a 10 second
+	// timeout
+	Client *http.Client
+}
+
+// Influx is a Sink that writes readings in InfluxDB line protocol in batches
+type Influx struct {
+	mu    sync.Mutex
+	cfg   InfluxConfig
+	tags  string
+	buf   bytes.Buffer
+	count int
+	first time.Time
+}
+
+// NewInflux returns an Influx sink
+func NewInflux(cfg InfluxConfig) (*Influx, error) {
+
+	if cfg.URL == "" && cfg.Writer == nil {
+		return nil, fmt.Errorf("influx URL or Writer must be set")
+	}
+
+	if cfg.Measurement == "" {
+		cfg.Measurement = "vl53l1x"
+	}
+
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = 10 * time.Second
+	}
+
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &Influx{cfg: cfg, tags: encodeTags(cfg.Tags)}, nil
+}
+
+// SensorTags returns the tags identifying a sensor by bus, address and
+// distance mode, with its name and labels when set
+func SensorTags(sensor *vl53l1x.VL53L1X) map[string]string {
+
+	tags := sensor.Labels()
+
+	if tags == nil {
+		tags = make(map[string]string)
+	}
+
+	tags["bus"] = sensor.Device()
+	tags["address"] = fmt.Sprintf("0x%02x", sensor.Address())
+	tags["mode"] = sensor.GetDistanceMode().String()
+
+	if name := sensor.Name(); name != "" {
+		tags["name"] = name
+	}
+
+	return tags
+}
+
+// Write adds the reading to the batch, writing the batch when it is full or
+// has been held longer than the flush interval
+func (i *Influx) Write(data vl53l1x.RangingData) error {
+	return i.WriteAt(time.Now(), data)
+}
+
+// WriteAt adds the reading with the given timestamp to the batch
+func (i *Influx) WriteAt(ts time.Time, data vl53l1x.RangingData) error {
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.count == 0 {
+		i.first = ts
+	}
+
+	fmt.Fprintf(&i.buf, "%s%s range_mm=%di,status=%di,signal_mcps=%g,ambient_mcps=%g %d\n",
+		escape(i.cfg.Measurement, ", "), i.tags, data.RangeMM, data.RangeStatus,
+		data.PeakSignalCountRateMCPS, data.AmbientCountRateMCPS, ts.UnixNano())
+
+	i.count++
+
+	if i.count >= i.cfg.BatchSize || time.Since(i.first) >= i.cfg.FlushInterval {
+		return i.flush()
+	}
+
+	return nil
+}
+
+// Flush writes any buffered points
+func (i *Influx) Flush() error {
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.flush()
+}
+
+// Close flushes any buffered points
+func (i *Influx) Close() error {
+	return i.Flush()
+}
+
+// flush writes the batch to the writer or URL
+func (i *Influx) flush() error {
+
+	if i.count == 0 {
+		return nil
+	}
+
+	defer func() {
+		i.buf.Reset()
+		i.count = 0
+	}()
+
+	if i.cfg.Writer != nil {
+		_, err := i.cfg.Writer.Write(i.buf.Bytes())
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, i.cfg.URL, bytes.NewReader(i.buf.Bytes()))
+
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+
+	if i.cfg.Token != "" {
+		req.Header.Set("Authorization", "Token "+i.cfg.Token)
+	}
+
+	resp, err := i.cfg.Client.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("influx write failed: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
+
+// encodeTags returns the tags in line protocol format sorted by key as
+// recommended for write performance
+func encodeTags(tags map[string]string) string {
+
+	keys := make([]string, 0, len(tags))
+
+	for k := range tags {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	var sb strings.Builder
+
+	for _, k := range keys {
+		sb.WriteString(",")
+		sb.WriteString(escape(k, ", ="))
+		sb.WriteString("=")
+		sb.WriteString(escape(tags[k], ", ="))
+	}
+
+	return sb.String()
+}
+
+// escape backslash escapes the special characters
+func escape(s, special string) string {
+
+	if !strings.ContainsAny(s, special) {
+		return s
+	}
+
+	var sb strings.Builder
+
+	for _, r := range s {
+		if strings.ContainsRune(special, r) {
+			sb.WriteByte('\\')
+		}
+
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,111 @@
+// Package sink provides destinations that sensor readings can be written to,
+// such as time-series databases, behind a common Sink interface.
+package sink
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+// Sink is a destination for sensor readings
+type Sink interface {
+	// Write a reading to the sink
+	Write(data vl53l1x.RangingData) error
+}
+
+// Func adapts a function to the Sink interface
+type Func func(data vl53l1x.RangingData) error
+
+// Write calls the function
+func (f Func) Write(data vl53l1x.RangingData) error {
+	return f(data)
+}
+
+// Multi is a Sink that writes each reading to all of its sinks
+type Multi []Sink
+
+// NewMulti returns a Sink fanning out to the given sinks
+func NewMulti(sinks ...Sink) Multi {
+	return Multi(sinks)
+}
+
+// Write writes the reading to every sink, continuing past failures, and
+// returns the joined errors
+func (m Multi) Write(data vl53l1x.RangingData) error {
+
+	var errs []error
+
+	for _, s := range m {
+		if err := s.Write(data); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// RateLimited is a Sink decorator that passes at most one reading per
+// interval to the wrapped sink, dropping the rest
+type RateLimited struct {
+	mu       sync.Mutex
+	sink     Sink
+	interval time.Duration
+	last     time.Time
+}
+
+// NewRateLimited returns a Sink passing at most one reading per interval to
+// the sink
+func NewRateLimited(sink Sink, interval time.Duration) *RateLimited {
+	return &RateLimited{sink: sink, interval: interval}
+}
+
+// Write passes the reading to the wrapped sink if the interval has elapsed
+// since the last reading passed
+func (r *RateLimited) Write(data vl53l1x.RangingData) error {
+
+	r.mu.Lock()
+	now := time.Now()
+
+	if !r.last.IsZero() && now.Sub(r.last) < r.interval {
+		r.mu.Unlock()
+		return nil
+	}
+
+	r.last = now
+	r.mu.Unlock()
+
+	return r.sink.Write(data)
+}
+
+// Pipe writes the readings of every MeasurementEvent received from the
+// sensors Events() channel to the sink until the channel is closed or the
+// context is cancelled.  Errors from the sink are passed to onError if not
+// nil
+func Pipe(ctx context.Context, events <-chan vl53l1x.Event, s Sink, onError func(error)) {
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+
+			m, ok := e.(vl53l1x.MeasurementEvent)
+
+			if !ok {
+				continue
+			}
+
+			if err := s.Write(m.Data); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
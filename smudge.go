@@ -0,0 +1,189 @@
+package vl53l1x
+
+import "time"
+
+// SmudgeSeverity is the estimated level of contamination on the cover glass
+type SmudgeSeverity int
+
+const (
+	SmudgeNone SmudgeSeverity = iota
+	SmudgeLight
+	SmudgeModerate
+	SmudgeHeavy
+)
+
+// String implement Stringer interface for SmudgeSeverity
+func (s SmudgeSeverity) String() string {
+	switch s {
+	case SmudgeLight:
+		return "light"
+	case SmudgeModerate:
+		return "moderate"
+	case SmudgeHeavy:
+		return "heavy"
+	default:
+		return "none"
+	}
+}
+
+// SmudgeConfig configures smudge detection.  The thresholds are the excess
+// crosstalk above the applied compensation in kcps per SPAD
+type SmudgeConfig struct {
+	// LightKCPS, ModerateKCPS and HeavyKCPS are the excess crosstalk at which
+	// each severity is reported, defaulting to 0.5, 1 and 2
+	LightKCPS    float32
+	ModerateKCPS float32
+	HeavyKCPS    float32
+	// NearMM is the range below which a valid measurement is taken to be a
+	// return from the cover glass, defaults to 20mm
+	NearMM uint16
+	// Samples is the number of measurements averaged and required before a
+	// severity is reported, defaults to 50
+	Samples int
+}
+
+// SmudgeStatus is the state of smudge detection
+type SmudgeStatus struct {
+	Severity SmudgeSeverity
+	// ExcessXtalkKCPS is the averaged crosstalk above the applied
+	// compensation in kcps per SPAD
+	ExcessXtalkKCPS float32
+	// Samples is the number of measurements used
+	Samples int
+}
+
+// SmudgeEvent is emitted when the smudge severity changes
+type SmudgeEvent struct {
+	Time            time.Time
+	Severity        SmudgeSeverity
+	ExcessXtalkKCPS float32
+}
+
+// EventTime returns when the event occurred
+func (e SmudgeEvent) EventTime() time.Time { return e.Time }
+
+// smudgeDetector estimates crosstalk growth from measurements
+type smudgeDetector struct {
+	cfg    SmudgeConfig
+	status SmudgeStatus
+}
+
+// newSmudgeDetector returns a smudgeDetector with defaults applied
+func newSmudgeDetector(cfg SmudgeConfig) *smudgeDetector {
+
+	if cfg.LightKCPS == 0 {
+		cfg.LightKCPS = 0.5
+	}
+
+	if cfg.ModerateKCPS == 0 {
+		cfg.ModerateKCPS = 1
+	}
+
+	if cfg.HeavyKCPS == 0 {
+		cfg.HeavyKCPS = 2
+	}
+
+	if cfg.NearMM == 0 {
+		cfg.NearMM = 20
+	}
+
+	if cfg.Samples <= 0 {
+		cfg.Samples = 50
+	}
+
+	return &smudgeDetector{cfg: cfg}
+}
+
+// update adds a measurement and returns true if the severity changed.  Only
+// measurements where no target was found or the range is at the cover glass
+// are used, as the crosstalk corrected signal rate of these is the crosstalk
+// the compensation does not remove
+func (d *smudgeDetector) update(rData RangingData, spads float32) bool {
+
+	glass := rData.RangeStatus.IsValid() && rData.RangeMM < d.cfg.NearMM
+
+	if (rData.RangeStatus != SignalFail && !glass) || spads == 0 {
+		return false
+	}
+
+	excess := rData.Raw.peakSignalMCPS() * 1000 / spads
+	s := &d.status
+
+	// average over the first samples then follow with an exponential
+	// moving average of the same length
+	s.Samples++
+	n := float32(min(s.Samples, d.cfg.Samples))
+	s.ExcessXtalkKCPS += (excess - s.ExcessXtalkKCPS) / n
+
+	if s.Samples < d.cfg.Samples {
+		return false
+	}
+
+	severity := d.severity(s.ExcessXtalkKCPS)
+
+	if severity == s.Severity {
+		return false
+	}
+
+	s.Severity = severity
+	return true
+}
+
+// severity returns the severity for the excess crosstalk
+func (d *smudgeDetector) severity(excess float32) SmudgeSeverity {
+
+	switch {
+	case excess >= d.cfg.HeavyKCPS:
+		return SmudgeHeavy
+	case excess >= d.cfg.ModerateKCPS:
+		return SmudgeModerate
+	case excess >= d.cfg.LightKCPS:
+		return SmudgeLight
+	default:
+		return SmudgeNone
+	}
+}
+
+// checkSmudge passes the measurement to smudge detection when enabled
+func (v *VL53L1X) checkSmudge(rData RangingData) {
+
+	if v.smudge == nil {
+		return
+	}
+
+	spads := float32(v.results.dssActualEffectiveSpadsSD0) / 256
+
+	if v.smudge.update(rData, spads) {
+		s := v.smudge.status
+
+		v.emit(SmudgeEvent{
+			Time:            time.Now(),
+			Severity:        s.Severity,
+			ExcessXtalkKCPS: s.ExcessXtalkKCPS,
+		})
+	}
+
+	if err := v.correctXtalk(); err != nil {
+		v.emitError("xtalk correction", err)
+	}
+}
+
+// SmudgeStatus returns the state of smudge detection enabled with
+// WithSmudgeDetection()
+func (v *VL53L1X) SmudgeStatus() SmudgeStatus {
+
+	if v.smudge == nil {
+		return SmudgeStatus{}
+	}
+
+	return v.smudge.status
+}
+
+// ResetSmudge clears the smudge estimate, such as after cleaning the cover
+// glass
+func (v *VL53L1X) ResetSmudge() {
+
+	if v.smudge != nil {
+		v.smudge.status = SmudgeStatus{}
+	}
+}
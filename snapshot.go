@@ -0,0 +1,142 @@
+package vl53l1x
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// snapshotBlocks are the configuration register blocks captured with each
+// snapshot: the ranging timeouts and VCSEL periods, the inter-measurement
+// period and the ROI
+var snapshotBlocks = []struct {
+	reg  uint16
+	size int
+}{
+	{RANGE_CONFIG_TIMEOUT_MACROP_A, 6},
+	{SYSTEM_INTERMEASUREMENT_PERIOD, 4},
+	{ROI_CONFIG_USER_ROI_CENTRE_SPAD, 2},
+}
+
+// RegisterBlock is the raw contents of consecutive registers
+type RegisterBlock struct {
+	Reg  uint16
+	Data []byte
+}
+
+// Snapshot is the raw sensor state captured with a measurement when enabled
+// with WithSnapshots()
+type Snapshot struct {
+	Time time.Time
+	// Result is the raw result block read from RESULT_RANGE_STATUS
+	Result []byte
+	// Config holds the timing and ROI registers read after the measurement
+	Config []RegisterBlock
+	// Data is the measurement decoded from Result
+	Data RangingData
+	// Err is set if the configuration registers could not be read
+	Err error
+}
+
+// String returns the snapshot as hex dumps of the raw registers
+func (s Snapshot) String() string {
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s range=%dmm status=%s\n", s.Time.Format(time.RFC3339Nano),
+		s.Data.RangeMM, s.Data.RangeStatus)
+	fmt.Fprintf(&b, "  0x%04X: % X\n", RESULT_RANGE_STATUS, s.Result)
+
+	for _, blk := range s.Config {
+		fmt.Fprintf(&b, "  0x%04X: % X\n", blk.Reg, blk.Data)
+	}
+
+	if s.Err != nil {
+		fmt.Fprintf(&b, "  error: %v\n", s.Err)
+	}
+
+	return b.String()
+}
+
+// snapshotRing holds the most recent snapshots
+type snapshotRing struct {
+	mu    sync.Mutex
+	snaps []Snapshot
+	next  int
+	count int
+	// result is the raw result block staged by readResults()
+	result []byte
+}
+
+// stage keeps a copy of the raw result block read for the next snapshot
+func (r *snapshotRing) stage(buf []byte) {
+	r.result = append(r.result[:0], buf...)
+}
+
+// add appends a snapshot overwriting the oldest when full
+func (r *snapshotRing) add(s Snapshot) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.snaps[r.next] = s
+	r.next = (r.next + 1) % len(r.snaps)
+	r.count = min(r.count+1, len(r.snaps))
+}
+
+// DumpLastN returns up to the last n snapshots, oldest first.  Returns nil if
+// snapshots are not enabled with WithSnapshots().  It may be called from
+// another goroutine while ranging
+func (v *VL53L1X) DumpLastN(n int) []Snapshot {
+
+	r := v.snapshots
+
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n = min(n, r.count)
+	out := make([]Snapshot, 0, n)
+
+	for i := n; i > 0; i-- {
+		idx := (r.next - i + len(r.snaps)) % len(r.snaps)
+		out = append(out, r.snaps[idx])
+	}
+
+	return out
+}
+
+// captureSnapshot records the staged result block and the configuration
+// registers with the measurement
+func (v *VL53L1X) captureSnapshot(data RangingData) {
+
+	r := v.snapshots
+
+	if r == nil {
+		return
+	}
+
+	s := Snapshot{
+		Time:   time.Now(),
+		Result: append([]byte(nil), r.result...),
+		Config: make([]RegisterBlock, 0, len(snapshotBlocks)),
+		Data:   data,
+	}
+
+	for _, blk := range snapshotBlocks {
+		buf := make([]byte, blk.size)
+
+		if _, err := v.readRegBytes(blk.reg, buf); err != nil {
+			s.Err = fmt.Errorf("failed to read 0x%04X: %w", blk.reg, err)
+			break
+		}
+
+		s.Config = append(s.Config, RegisterBlock{Reg: blk.reg, Data: buf})
+	}
+
+	r.add(s)
+}
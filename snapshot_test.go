@@ -0,0 +1,137 @@
+package vl53l1x_test
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/swdee/go-vl53l1x"
+	"github.com/swdee/go-vl53l1x/replay"
+	"github.com/swdee/go-vl53l1x/sim"
+)
+
+var update = flag.Bool("update", false, "record the register snapshots again from the simulator")
+
+// snapshotNote heads each snapshot, as the traces are recorded from this
+// driver rather than from ST's ULD or a hardware capture
+const snapshotNote = "# regression snapshot recorded from this driver against the simulator,\n" +
+	"# not a reference trace from ST's ULD or hardware\n"
+
+// snapshot creates a sensor, which runs Init(), then runs fn on it while
+// replaying the register snapshot of the test, failing on the first register
+// transaction that departs from it.  The snapshots only detect changes to the
+// register sequence the driver made when they were recorded.  With -update
+// the snapshot is recorded from the simulator instead, to be reviewed before
+// it is committed
+func snapshot(t *testing.T, name string, fn func(v *vl53l1x.VL53L1X) error) {
+
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".trace")
+
+	if *update {
+		record(t, path, fn)
+	}
+
+	p, err := replay.Load(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := vl53l1x.New(p, vl53l1x.Short, 50)
+
+	if err == nil {
+		err = fn(v)
+	}
+
+	if err != nil {
+		var mismatch *replay.MismatchError
+
+		if errors.As(err, &mismatch) {
+			t.Fatalf("register sequence departs from %s: %v", path, mismatch)
+		}
+
+		t.Fatal(err)
+	}
+
+	if n := p.Remaining(); n > 0 {
+		t.Errorf("%d transactions of %s not made", n, path)
+	}
+}
+
+// record records the snapshot at path from the simulator
+func record(t *testing.T, path string, fn func(v *vl53l1x.VL53L1X) error) {
+
+	t.Helper()
+
+	rec, err := replay.Create(path, sim.New(vl53l1x.Address))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := vl53l1x.New(rec, vl53l1x.Short, 50)
+
+	if err == nil {
+		err = fn(v)
+	}
+
+	if err != nil {
+		t.Fatalf("recording: %v", err)
+	}
+
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	trace, err := os.ReadFile(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path, append([]byte(snapshotNote), trace...), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSnapshotInit(t *testing.T) {
+	snapshot(t, "init", func(*vl53l1x.VL53L1X) error {
+		return nil
+	})
+}
+
+func TestSnapshotSetDistanceMode(t *testing.T) {
+	snapshot(t, "distance_mode", func(v *vl53l1x.VL53L1X) error {
+
+		for _, mode := range []vl53l1x.DistanceMode{vl53l1x.Medium, vl53l1x.Long, vl53l1x.Short} {
+			if err := v.SetDistanceMode(mode); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func TestSnapshotSetMeasurementTimingBudget(t *testing.T) {
+	snapshot(t, "timing_budget", func(v *vl53l1x.VL53L1X) error {
+
+		for _, mode := range []vl53l1x.DistanceMode{vl53l1x.Short, vl53l1x.Medium, vl53l1x.Long} {
+			if err := v.SetDistanceMode(mode); err != nil {
+				return err
+			}
+
+			for _, budget := range []uint32{33, 50, 100, 500} {
+				if err := v.SetMeasurementTimingBudget(budget); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,214 @@
+package vl53l1x
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+// Stats is a snapshot of the driver counters returned by Stats()
+type Stats struct {
+	// RegisterReads and RegisterWrites are the number of register read and
+	// write transactions
+	RegisterReads  uint64
+	RegisterWrites uint64
+	// BytesRead and BytesWritten are the number of bytes transferred on the
+	// bus, including register addresses
+	BytesRead    uint64
+	BytesWritten uint64
+	// BusErrors is the number of failed bus transfers, of which NACKs were
+	// not acknowledged by the sensor
+	BusErrors uint64
+	NACKs     uint64
+	// Retries is the number of transfers retried by the Bus, reported by
+	// transports implementing RetryCounter
+	Retries uint64
+	// Timeouts is the number of waits on the sensor that timed out
+	Timeouts uint64
+	// Measurements is the number of measurements read
+	Measurements uint64
+	// LostFrames is the number of continuous measurements never read, only
+	// counted when enabled with WithFrameLoss() or WithFrameLossTolerance()
+	LostFrames uint64
+	// InvalidStatus counts measurements read by RangeStatus where the status
+	// is not valid
+	InvalidStatus map[RangeStatus]uint64
+	// LastBusError is the error of the last bus transfer, nil if it succeeded
+	LastBusError error `json:"-"`
+}
+
+// RetryCounter is an optional capability implemented by a Bus that retries
+// failed transfers, such as a USB or network bridge
+type RetryCounter interface {
+	// Retries returns the number of transfers retried
+	Retries() uint64
+}
+
+// counters holds the live driver counters, updated atomically so Stats() can
+// be called from another goroutine while ranging
+type counters struct {
+	regReads     atomic.Uint64
+	regWrites    atomic.Uint64
+	bytesRead    atomic.Uint64
+	bytesWritten atomic.Uint64
+	busErrors    atomic.Uint64
+	nacks        atomic.Uint64
+	timeouts     atomic.Uint64
+	measurements atomic.Uint64
+	lostFrames   atomic.Uint64
+	status       [256]atomic.Uint64
+	// nackRun is the number of consecutive transfers not acknowledged, used
+	// to detect the sensor leaving the bus
+	nackRun atomic.Uint64
+
+	mu      sync.Mutex
+	lastErr error
+	// last is the latest measurement recorded for DebugState()
+	last debugSample
+}
+
+// Stats returns a snapshot of the register, bus and measurement counters
+// since the sensor was created or ResetStats() was called
+func (v *VL53L1X) Stats() Stats {
+
+	c := &v.stats
+
+	s := Stats{
+		RegisterReads:  c.regReads.Load(),
+		RegisterWrites: c.regWrites.Load(),
+		BytesRead:      c.bytesRead.Load(),
+		BytesWritten:   c.bytesWritten.Load(),
+		BusErrors:      c.busErrors.Load(),
+		NACKs:          c.nacks.Load(),
+		Timeouts:       c.timeouts.Load(),
+		Measurements:   c.measurements.Load(),
+		LostFrames:     c.lostFrames.Load(),
+		InvalidStatus:  make(map[RangeStatus]uint64),
+	}
+
+	if rc, ok := v.bus.(RetryCounter); ok {
+		s.Retries = rc.Retries()
+	}
+
+	for i := range c.status {
+		if n := c.status[i].Load(); n > 0 {
+			s.InvalidStatus[RangeStatus(i)] = n
+		}
+	}
+
+	c.mu.Lock()
+	s.LastBusError = c.lastErr
+	c.mu.Unlock()
+
+	return s
+}
+
+// ResetStats zeroes the counters returned by Stats()
+func (v *VL53L1X) ResetStats() {
+
+	c := &v.stats
+
+	c.regReads.Store(0)
+	c.regWrites.Store(0)
+	c.bytesRead.Store(0)
+	c.bytesWritten.Store(0)
+	c.busErrors.Store(0)
+	c.nacks.Store(0)
+	c.timeouts.Store(0)
+	c.measurements.Store(0)
+	c.lostFrames.Store(0)
+
+	for i := range c.status {
+		c.status[i].Store(0)
+	}
+
+	c.mu.Lock()
+	c.lastErr = nil
+	c.mu.Unlock()
+}
+
+// LastBusError returns the error of the last bus transfer, or nil if it
+// succeeded
+func (v *VL53L1X) LastBusError() error {
+
+	v.stats.mu.Lock()
+	defer v.stats.mu.Unlock()
+
+	return v.stats.lastErr
+}
+
+// busWrite writes buf to the bus recording the transfer
+func (v *VL53L1X) busWrite(buf []byte) error {
+
+	if v.closed {
+		return ErrClosed
+	}
+
+	n, err := v.bus.WriteBytes(buf)
+	v.stats.bytesWritten.Add(uint64(n))
+	v.stats.transfer(err)
+
+	return err
+}
+
+// busRead reads into buf from the bus recording the transfer
+func (v *VL53L1X) busRead(buf []byte) (int, error) {
+
+	if v.closed {
+		return 0, ErrClosed
+	}
+
+	n, err := v.bus.ReadBytes(buf)
+	v.stats.bytesRead.Add(uint64(n))
+	v.stats.transfer(err)
+
+	return n, err
+}
+
+// transfer records the result of a bus transfer
+func (c *counters) transfer(err error) {
+
+	switch {
+	case err == nil:
+		c.nackRun.Store(0)
+	case isNACK(err):
+		c.busErrors.Add(1)
+		c.nacks.Add(1)
+		c.nackRun.Add(1)
+	default:
+		c.busErrors.Add(1)
+	}
+
+	c.mu.Lock()
+	c.lastErr = err
+	c.mu.Unlock()
+}
+
+// measurement records a measurement read with the given status
+func (c *counters) measurement(status RangeStatus) {
+
+	c.measurements.Add(1)
+
+	if !status.IsValid() {
+		c.status[status].Add(1)
+	}
+}
+
+// isNACK reports whether a bus error is the device not acknowledging, which
+// Linux i2c-dev reports as EREMOTEIO, or ENXIO for some bus drivers, and
+// other transports report by wrapping ErrNACK
+func isNACK(err error) bool {
+
+	if errors.Is(err, ErrNACK) {
+		return true
+	}
+
+	var errno syscall.Errno
+
+	if !errors.As(err, &errno) {
+		return false
+	}
+
+	return errno == 121 || errno == 6
+}
@@ -0,0 +1,151 @@
+package vl53l1x
+
+// SystemStatus holds the decoded FIRMWARE_SYSTEM_STATUS register
+type SystemStatus struct {
+	// Raw is the register value as read from the sensor
+	Raw uint8
+	// Booted is true once the firmware has completed booting
+	Booted bool
+}
+
+// String implement Stringer interface for SystemStatus
+func (s SystemStatus) String() string {
+	if s.Booted {
+		return "booted"
+	}
+
+	return "booting"
+}
+
+// InterruptStatus holds the decoded interrupt state of the sensor
+type InterruptStatus struct {
+	// Raw is the RESULT_INTERRUPT_STATUS register value as read from the
+	// sensor
+	Raw uint8
+	// DataReady is true when a new measurement is available to be read
+	DataReady bool
+	// IntStatus is the interrupt type that last fired from bits 0-2 of the
+	// register
+	IntStatus uint8
+	// ErrorStatus is the interrupt error status from bits 3-4 of the register
+	ErrorStatus uint8
+}
+
+// GetSystemStatus reads and decodes FIRMWARE_SYSTEM_STATUS so applications can
+// tell whether the sensor is still booting.  A returned error indicates the
+// sensor is not responding on the bus
+func (v *VL53L1X) GetSystemStatus() (SystemStatus, error) {
+
+	val, err := v.readReg(FIRMWARE_SYSTEM_STATUS)
+
+	if err != nil {
+		return SystemStatus{}, err
+	}
+
+	return SystemStatus{
+		Raw:    val,
+		Booted: val&0x01 != 0,
+	}, nil
+}
+
+// GetInterruptStatus reads and decodes the sensors interrupt state, reporting
+// whether new data is waiting to be read or no measurement has completed since
+// the interrupt was last cleared
+func (v *VL53L1X) GetInterruptStatus() (InterruptStatus, error) {
+
+	val, err := v.readReg(RESULT_INTERRUPT_STATUS)
+
+	if err != nil {
+		return InterruptStatus{}, err
+	}
+
+	ready, err := v.dataReady()
+
+	if err != nil {
+		return InterruptStatus{}, err
+	}
+
+	return InterruptStatus{
+		Raw:         val,
+		DataReady:   ready,
+		IntStatus:   val & 0x07,
+		ErrorStatus: (val >> 3) & 0x03,
+	}, nil
+}
+
+// DeviceError is the device error code reported by the sensor in bits 0-4 of
+// RESULT_RANGE_STATUS, from VL53L1_DEVICEERROR_* in the ST API.  RangeStatus
+// groups several of these, such as the hardware failures, into one status
+type DeviceError uint8
+
+const (
+	DeviceErrorNone                       DeviceError = 0
+	DeviceErrorVCSELContinuityTestFailure DeviceError = 1
+	DeviceErrorVCSELWatchdogTestFailure   DeviceError = 2
+	DeviceErrorNoVHVValueFound            DeviceError = 3
+	DeviceErrorMSRCNoTarget               DeviceError = 4
+	DeviceErrorRangePhaseCheck            DeviceError = 5
+	DeviceErrorSigmaThresholdCheck        DeviceError = 6
+	DeviceErrorPhaseConsistency           DeviceError = 7
+	DeviceErrorMinClip                    DeviceError = 8
+	DeviceErrorRangeComplete              DeviceError = 9
+	DeviceErrorAlgoUnderflow              DeviceError = 10
+	DeviceErrorAlgoOverflow               DeviceError = 11
+	DeviceErrorRangeIgnoreThreshold       DeviceError = 12
+	DeviceErrorUserROIClip                DeviceError = 13
+	DeviceErrorRefSPADCharNotEnoughSPADs  DeviceError = 14
+	DeviceErrorRefSPADCharMoreThanTarget  DeviceError = 15
+	DeviceErrorRefSPADCharLessThanTarget  DeviceError = 16
+	DeviceErrorMultClipFail               DeviceError = 17
+	DeviceErrorGPHStreamCount0Ready       DeviceError = 18
+	DeviceErrorRangeCompleteNoWrapCheck   DeviceError = 19
+	DeviceErrorEventConsistency           DeviceError = 20
+	DeviceErrorMinSignalEventCheck        DeviceError = 21
+	DeviceErrorRangeCompleteMergedPulse   DeviceError = 22
+)
+
+// deviceErrorNames are the descriptions of each DeviceError
+var deviceErrorNames = map[DeviceError]string{
+	DeviceErrorNone:                       "no update",
+	DeviceErrorVCSELContinuityTestFailure: "VCSEL continuity test failure",
+	DeviceErrorVCSELWatchdogTestFailure:   "VCSEL watchdog test failure",
+	DeviceErrorNoVHVValueFound:            "no VHV value found",
+	DeviceErrorMSRCNoTarget:               "MSRC no target",
+	DeviceErrorRangePhaseCheck:            "range phase check",
+	DeviceErrorSigmaThresholdCheck:        "sigma threshold check",
+	DeviceErrorPhaseConsistency:           "phase consistency",
+	DeviceErrorMinClip:                    "min clip",
+	DeviceErrorRangeComplete:              "range complete",
+	DeviceErrorAlgoUnderflow:              "algorithm underflow",
+	DeviceErrorAlgoOverflow:               "algorithm overflow",
+	DeviceErrorRangeIgnoreThreshold:       "range ignore threshold",
+	DeviceErrorUserROIClip:                "user ROI clip",
+	DeviceErrorRefSPADCharNotEnoughSPADs:  "reference SPAD characterization, not enough SPADs",
+	DeviceErrorRefSPADCharMoreThanTarget:  "reference SPAD characterization, rate above target",
+	DeviceErrorRefSPADCharLessThanTarget:  "reference SPAD characterization, rate below target",
+	DeviceErrorMultClipFail:               "multiple clip failure",
+	DeviceErrorGPHStreamCount0Ready:       "stream count 0 ready",
+	DeviceErrorRangeCompleteNoWrapCheck:   "range complete, no wrap check",
+	DeviceErrorEventConsistency:           "event consistency",
+	DeviceErrorMinSignalEventCheck:        "min signal event check",
+	DeviceErrorRangeCompleteMergedPulse:   "range complete, merged pulse",
+}
+
+// String implement Stringer interface for DeviceError
+func (e DeviceError) String() string {
+
+	if name, ok := deviceErrorNames[e]; ok {
+		return name
+	}
+
+	return "unknown device error"
+}
+
+// IsHardware returns true if the error is a failure of the VCSEL or its
+// voltage calibration, rather than a condition of the scene
+func (e DeviceError) IsHardware() bool {
+	return e == DeviceErrorVCSELContinuityTestFailure ||
+		e == DeviceErrorVCSELWatchdogTestFailure ||
+		e == DeviceErrorNoVHVValueFound ||
+		e == DeviceErrorMultClipFail
+}
@@ -0,0 +1,162 @@
+package vl53l1x
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// LED is a status output driven by WithStatusLED(), such as an LED on a GPIO
+// pin of the host
+type LED interface {
+	// Set turns the output on or off
+	Set(on bool) error
+}
+
+// LEDState is the state of the measurements shown on a status LED
+type LEDState uint8
+
+const (
+	// LEDValid shows valid measurements, on by default
+	LEDValid LEDState = iota
+	// LEDThreshold shows valid measurements reported while an event mode is
+	// set with SetEventConfig(), blinking slowly by default
+	LEDThreshold
+	// LEDFailing shows a streak of measurements without a valid range, such
+	// as SignalFail with no target in view, blinking by default
+	LEDFailing
+	// LEDError shows failed reads, flashing briefly by default
+	LEDError
+)
+
+// String returns the name of the state
+func (s LEDState) String() string {
+	switch s {
+	case LEDValid:
+		return "valid"
+	case LEDThreshold:
+		return "threshold"
+	case LEDFailing:
+		return "failing"
+	case LEDError:
+		return "error"
+	default:
+		return fmt.Sprintf("LEDState(%d)", uint8(s))
+	}
+}
+
+// defaultLEDPatterns are the patterns used for states not given in
+// StatusLEDConfig.Patterns
+var defaultLEDPatterns = map[LEDState][]bool{
+	LEDValid:     {true},
+	LEDThreshold: {true, true, false, false},
+	LEDFailing:   {true, false},
+	LEDError:     {true, false, false, false},
+}
+
+// StatusLEDConfig configures the status LED
+type StatusLEDConfig struct {
+	// FailStreak is the number of consecutive measurements without a valid
+	// range before LEDFailing is shown, defaults to 5
+	FailStreak int
+	// Patterns replaces the pattern shown for each state.  A pattern is
+	// stepped through once per read, so blinks follow the measurement rate
+	Patterns map[LEDState][]bool
+}
+
+// statusLED drives the status LED from the measurements read
+type statusLED struct {
+	led      LED
+	cfg      StatusLEDConfig
+	patterns map[LEDState][]bool
+
+	state  LEDState
+	step   int
+	streak int
+	lit    bool
+	set    bool
+}
+
+// newStatusLED returns a statusLED for the configuration with defaults
+// applied
+func newStatusLED(led LED, cfg StatusLEDConfig) *statusLED {
+
+	if cfg.FailStreak <= 0 {
+		cfg.FailStreak = 5
+	}
+
+	patterns := make(map[LEDState][]bool, len(defaultLEDPatterns))
+
+	for state, p := range defaultLEDPatterns {
+		patterns[state] = p
+	}
+
+	for state, p := range cfg.Patterns {
+		if len(p) > 0 {
+			patterns[state] = p
+		}
+	}
+
+	return &statusLED{led: led, cfg: cfg, patterns: patterns}
+}
+
+// StatusLEDState returns the state shown on the status LED, LEDValid when no
+// status LED is set with WithStatusLED()
+func (v *VL53L1X) StatusLEDState() LEDState {
+
+	if v.statusLED == nil {
+		return LEDValid
+	}
+
+	return v.statusLED.state
+}
+
+// updateLED steps the status LED for the read just made
+func (v *VL53L1X) updateLED(rData RangingData, err error) {
+
+	l := v.statusLED
+
+	// discarded measurements and cancelled reads are not faults
+	if l == nil || errors.Is(err, ErrHoldOff) || errors.Is(err, context.Canceled) {
+		return
+	}
+
+	state := l.state
+
+	switch {
+	case err != nil:
+		state = LEDError
+	case !rData.RangeStatus.IsValid():
+		l.streak++
+
+		if l.streak >= l.cfg.FailStreak {
+			state = LEDFailing
+		}
+	case v.eventDetect:
+		l.streak = 0
+		state = LEDThreshold
+	default:
+		l.streak = 0
+		state = LEDValid
+	}
+
+	if state != l.state {
+		l.state, l.step = state, 0
+	}
+
+	p := l.patterns[l.state]
+	on := p[l.step%len(p)]
+	l.step++
+
+	if l.set && on == l.lit {
+		return
+	}
+
+	// the LED is a debugging aid so its failures do not fail the read
+	if err := l.led.Set(on); err != nil {
+		v.log.Printf("Status LED failed: %v", err)
+		return
+	}
+
+	l.lit, l.set = on, true
+}
@@ -0,0 +1,150 @@
+package vl53l1x
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNotStored is returned by a Store when no data has been saved
+var ErrNotStored = errors.New("no data stored")
+
+// Store persists calibration and configuration data so it can be restored
+// when the sensor is initialized, such as to a file or EEPROM
+type Store interface {
+	LoadCalibration() (CalibrationData, error)
+	SaveCalibration(cal CalibrationData) error
+	LoadConfig() (Config, error)
+	SaveConfig(cfg Config) error
+}
+
+// MemoryStore is a Store held in memory
+type MemoryStore struct {
+	mu  sync.Mutex
+	cal *CalibrationData
+	cfg *Config
+}
+
+// NewMemoryStore returns an empty in memory Store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// LoadCalibration returns the saved calibration data
+func (m *MemoryStore) LoadCalibration() (CalibrationData, error) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cal == nil {
+		return CalibrationData{}, ErrNotStored
+	}
+
+	return *m.cal, nil
+}
+
+// SaveCalibration saves the calibration data
+func (m *MemoryStore) SaveCalibration(cal CalibrationData) error {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.cal = &cal
+	return nil
+}
+
+// LoadConfig returns the saved configuration
+func (m *MemoryStore) LoadConfig() (Config, error) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cfg == nil {
+		return Config{}, ErrNotStored
+	}
+
+	return *m.cfg, nil
+}
+
+// SaveConfig saves the configuration
+func (m *MemoryStore) SaveConfig(cfg Config) error {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.cfg = &cfg
+	return nil
+}
+
+// SaveToStore saves the sensors current calibration and configuration to the
+// Store set with WithStore()
+func (v *VL53L1X) SaveToStore() error {
+
+	if v.store == nil {
+		return fmt.Errorf("no store configured")
+	}
+
+	cal, err := v.GetCalibration()
+
+	if err != nil {
+		return err
+	}
+
+	if err := v.store.SaveCalibration(cal); err != nil {
+		return err
+	}
+
+	cfg, err := v.GetConfig()
+
+	if err != nil {
+		return err
+	}
+
+	return v.store.SaveConfig(cfg)
+}
+
+// loadStoredConfig replaces the distance mode and timing budget with those
+// saved in the store before the sensor is initialized
+func (v *VL53L1X) loadStoredConfig() (*Config, error) {
+
+	cfg, err := v.store.LoadConfig()
+
+	if errors.Is(err, ErrNotStored) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	v.distanceMode = cfg.DistanceMode
+
+	if cfg.TimingBudget > 0 {
+		v.timingBudget = cfg.TimingBudget
+	}
+
+	return &cfg, nil
+}
+
+// applyStore applies the ROI from the stored config and stored calibration
+// to an initialized sensor
+func (v *VL53L1X) applyStore(cfg *Config) error {
+
+	if cfg != nil {
+		if err := v.ApplyConfig(*cfg); err != nil {
+			return fmt.Errorf("failed to apply stored config: %w", err)
+		}
+	}
+
+	cal, err := v.store.LoadCalibration()
+
+	if errors.Is(err, ErrNotStored) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return v.SetCalibration(cal)
+}
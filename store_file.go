@@ -0,0 +1,140 @@
+//go:build !tinygo
+
+package vl53l1x
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// FileStore is a Store that saves data as JSON to a file
+type FileStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// fileData is the JSON layout of a FileStore file
+type fileData struct {
+	Calibration *CalibrationData `json:"calibration,omitempty"`
+	Config      *Config          `json:"config,omitempty"`
+}
+
+// NewFileStore returns a Store that saves to the file at the given path
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// LoadCalibration returns the calibration data saved in the file
+func (f *FileStore) LoadCalibration() (CalibrationData, error) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := f.read()
+
+	if err != nil {
+		return CalibrationData{}, err
+	}
+
+	if data.Calibration == nil {
+		return CalibrationData{}, ErrNotStored
+	}
+
+	return *data.Calibration, nil
+}
+
+// SaveCalibration saves the calibration data to the file
+func (f *FileStore) SaveCalibration(cal CalibrationData) error {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := f.read()
+
+	if err != nil && !errors.Is(err, ErrNotStored) {
+		return err
+	}
+
+	data.Calibration = &cal
+	return f.write(data)
+}
+
+// LoadConfig returns the configuration saved in the file
+func (f *FileStore) LoadConfig() (Config, error) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := f.read()
+
+	if err != nil {
+		return Config{}, err
+	}
+
+	if data.Config == nil {
+		return Config{}, ErrNotStored
+	}
+
+	return *data.Config, nil
+}
+
+// SaveConfig saves the configuration to the file
+func (f *FileStore) SaveConfig(cfg Config) error {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := f.read()
+
+	if err != nil && !errors.Is(err, ErrNotStored) {
+		return err
+	}
+
+	data.Config = &cfg
+	return f.write(data)
+}
+
+// read loads the file contents, returning ErrNotStored if the file does not
+// exist yet
+func (f *FileStore) read() (fileData, error) {
+
+	var data fileData
+
+	b, err := os.ReadFile(f.path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return data, ErrNotStored
+	}
+
+	if err != nil {
+		return data, err
+	}
+
+	if err := json.Unmarshal(b, &data); err != nil {
+		return data, fmt.Errorf("invalid store file %s: %w", f.path, err)
+	}
+
+	return data, nil
+}
+
+// write saves the data to a temporary file then renames it over the store
+// file so a failed write does not corrupt existing data
+func (f *FileStore) write(data fileData) error {
+
+	b, err := json.MarshalIndent(data, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	tmp := f.path + ".tmp"
+
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, f.path)
+}
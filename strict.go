@@ -0,0 +1,24 @@
+package vl53l1x
+
+// MeasurementError is returned by the read methods in strict status mode,
+// enabled with WithStrictStatus(), when the measurement status is not
+// RangeValid.  The measurement is carried so it can still be inspected
+type MeasurementError struct {
+	Data RangingData
+}
+
+// Error returns the error message
+func (e *MeasurementError) Error() string {
+	return "invalid measurement: " + e.Data.RangeStatus.String()
+}
+
+// strict returns a MeasurementError for a measurement that is not RangeValid
+// when strict status mode is enabled
+func (v *VL53L1X) strict(rData RangingData, err error) (RangingData, error) {
+
+	if err == nil && v.strictStatus && rData.RangeStatus != RangeValid {
+		return rData, &MeasurementError{Data: rData}
+	}
+
+	return rData, err
+}
@@ -0,0 +1,184 @@
+package vl53l1x
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// SurfaceConfig configures MeasureSurfaceAngle()
+type SurfaceConfig struct {
+	// Zones is the number of ROI's measured.  2 measures left and right of
+	// the field of view giving TiltX only, 3 measures a triangle and 4 left,
+	// right, top and bottom giving both TiltX and TiltY.  Defaults to 4
+	Zones int
+	// Samples is the number of measurements taken in each zone, defaults to 3
+	Samples int
+}
+
+// SurfaceAngle is the estimated tilt of a planar target
+type SurfaceAngle struct {
+	// TiltX and TiltY are the angles in degrees of the target surface from
+	// perpendicular to the optical axis.  They are oriented as per
+	// SPADRect.Bearing() with TiltX positive when the surface is further away
+	// to the right and TiltY positive when it is further away at the top
+	TiltX, TiltY float64
+	// Confidence is between 0 and 1 and is the fraction of measurements with
+	// a valid range, reduced by how far the zones are from lying on a plane
+	// when 4 zones are measured
+	Confidence float64
+	// Zones holds the measurement of each zone
+	Zones []ZoneResult
+}
+
+// surfaceLayouts are the zones measured for each supported zone count
+var surfaceLayouts = map[int][]SPADRect{
+	2: {
+		{Col: 0, Row: 5, Width: 6, Height: 6},
+		{Col: 10, Row: 5, Width: 6, Height: 6},
+	},
+	3: {
+		{Col: 0, Row: 0, Width: 6, Height: 6},
+		{Col: 10, Row: 0, Width: 6, Height: 6},
+		{Col: 5, Row: 10, Width: 6, Height: 6},
+	},
+	4: {
+		{Col: 0, Row: 5, Width: 6, Height: 6},
+		{Col: 10, Row: 5, Width: 6, Height: 6},
+		{Col: 5, Row: 0, Width: 6, Height: 6},
+		{Col: 5, Row: 10, Width: 6, Height: 6},
+	},
+}
+
+// MeasureSurfaceAngle measures the distance in several ROI's across the field
+// of view and estimates the tilt of a planar target from them, such as for
+// docking or levelling.  The previous ROI is restored afterwards
+func (v *VL53L1X) MeasureSurfaceAngle(ctx context.Context, cfg SurfaceConfig) (SurfaceAngle, error) {
+
+	if cfg.Zones == 0 {
+		cfg.Zones = 4
+	}
+
+	if cfg.Samples <= 0 {
+		cfg.Samples = 3
+	}
+
+	layout, ok := surfaceLayouts[cfg.Zones]
+
+	if !ok {
+		return SurfaceAngle{}, fmt.Errorf("surface angle requires 2 to 4 zones")
+	}
+
+	width, height, err := v.GetROISize()
+
+	if err != nil {
+		return SurfaceAngle{}, err
+	}
+
+	center, err := v.GetROICenter()
+
+	if err != nil {
+		return SurfaceAngle{}, err
+	}
+
+	defer v.restoreROI(width, height, center)
+
+	res := SurfaceAngle{Zones: make([]ZoneResult, 0, len(layout))}
+	points := make([][3]float64, 0, len(layout))
+	valid, samples := 0, 0
+
+	for _, zone := range layout {
+		zr, err := v.MeasureZone(ctx, zone, cfg.Samples)
+
+		if err != nil {
+			return SurfaceAngle{}, err
+		}
+
+		if zr.Valid == 0 {
+			return SurfaceAngle{}, fmt.Errorf("no valid range in zone centered on SPAD %d",
+				zone.Center())
+		}
+
+		res.Zones = append(res.Zones, zr)
+		points = append(points, surfacePoint(zr))
+		valid += zr.Valid
+		samples += zr.Samples
+	}
+
+	res.Confidence = float64(valid) / float64(samples)
+
+	if len(points) == 2 {
+		dx := points[1][0] - points[0][0]
+		dz := points[1][2] - points[0][2]
+		res.TiltX = math.Atan(dz/dx) * 180 / math.Pi
+		return res, nil
+	}
+
+	a, b, rms, err := fitPlane(points)
+
+	if err != nil {
+		return SurfaceAngle{}, err
+	}
+
+	res.TiltX = math.Atan(a) * 180 / math.Pi
+	res.TiltY = math.Atan(b) * 180 / math.Pi
+
+	// scale confidence down as the zones depart from a plane, an error of
+	// 10mm halves it
+	res.Confidence /= 1 + rms/10
+
+	return res, nil
+}
+
+// surfacePoint returns the position in millimeters of the target seen by the
+// zone with z along the optical axis
+func surfacePoint(zr ZoneResult) [3]float64 {
+
+	bx, by := zr.Zone.Bearing()
+	tx := math.Tan(bx * math.Pi / 180)
+	ty := math.Tan(by * math.Pi / 180)
+
+	// the range is measured along the ray through the zone
+	r := float64(zr.RangeMM) / math.Sqrt(1+tx*tx+ty*ty)
+
+	return [3]float64{r * tx, r * ty, r}
+}
+
+// fitPlane fits the plane z = a*x + b*y + c to the points by least squares and
+// returns the gradients and the RMS residual in millimeters
+func fitPlane(points [][3]float64) (a, b, rms float64, err error) {
+
+	var sxx, sxy, syy, sx, sy, sxz, syz, sz float64
+	n := float64(len(points))
+
+	for _, p := range points {
+		sxx += p[0] * p[0]
+		sxy += p[0] * p[1]
+		syy += p[1] * p[1]
+		sx += p[0]
+		sy += p[1]
+		sxz += p[0] * p[2]
+		syz += p[1] * p[2]
+		sz += p[2]
+	}
+
+	// solve the normal equations by Cramer's rule
+	det := sxx*(syy*n-sy*sy) - sxy*(sxy*n-sy*sx) + sx*(sxy*sy-syy*sx)
+
+	if math.Abs(det) < 1e-9 {
+		return 0, 0, 0, fmt.Errorf("zones are too close together to fit a plane")
+	}
+
+	a = (sxz*(syy*n-sy*sy) - sxy*(syz*n-sy*sz) + sx*(syz*sy-syy*sz)) / det
+	b = (sxx*(syz*n-sz*sy) - sxz*(sxy*n-sy*sx) + sx*(sxy*sz-syz*sx)) / det
+	c := (sxx*(syy*sz-sy*syz) - sxy*(sxy*sz-sy*sxz) + sx*(sxy*syz-syy*sxz)) / det
+
+	for _, p := range points {
+		d := p[2] - (a*p[0] + b*p[1] + c)
+		rms += d * d
+	}
+
+	rms = math.Sqrt(rms / n)
+
+	return a, b, rms, nil
+}
@@ -0,0 +1,136 @@
+package vl53l1x
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// DefaultTemperatureDelta is the change in temperature in degrees Celsius
+	// since the last VHV calibration at which the sensor is recalibrated, as
+	// recommended by ST
+	DefaultTemperatureDelta float32 = 8
+	// temperatureCheckInterval is the minimum time between temperature reads
+	temperatureCheckInterval = time.Second
+)
+
+// temperatureState holds the temperature compensation scheduling state
+type temperatureState struct {
+	provider func() (float32, error)
+	delta    float32
+	// calTemp is the temperature at the last VHV calibration, valid when
+	// haveCalTemp is set
+	calTemp     float32
+	haveCalTemp bool
+	lastCheck   time.Time
+}
+
+// SetTemperatureProvider sets a function returning the temperature in degrees
+// Celsius near the sensor, such as from a board sensor.  While ranging the
+// driver reads it at most once a second and calls RecalibrateTemperature()
+// when it has shifted by more than the delta set with SetTemperatureDelta()
+// since the last VHV calibration.  Pass nil to disable
+func (v *VL53L1X) SetTemperatureProvider(fn func() (float32, error)) {
+
+	v.temp.provider = fn
+	v.temp.haveCalTemp = false
+
+	if v.temp.delta == 0 {
+		v.temp.delta = DefaultTemperatureDelta
+	}
+}
+
+// SetTemperatureDelta sets the temperature shift in degrees Celsius that
+// triggers recalibration, defaults to DefaultTemperatureDelta
+func (v *VL53L1X) SetTemperatureDelta(delta float32) {
+	v.temp.delta = delta
+}
+
+// RecalibrateTemperature re-enables the VHV calibration so the sensor
+// compensates for a change in temperature on the next measurement.  When not
+// ranging the calibration already runs on the first measurement so nothing
+// is done
+func (v *VL53L1X) RecalibrateTemperature() error {
+
+	if !v.calibrated {
+		return nil
+	}
+
+	end := v.traceOp(SpanRecalibrateTemp)
+	err := v.recalibrateTemperature()
+	end(err)
+
+	return err
+}
+
+// recalibrateTemperature restores the VHV and phase calibration for
+// RecalibrateTemperature()
+func (v *VL53L1X) recalibrateTemperature() error {
+
+	v.log.Printf("Recalibrating VHV for temperature")
+
+	if err := v.writeReg(VHV_CONFIG_INIT, v.savedVHVInit); err != nil {
+		return err
+	}
+
+	if err := v.writeReg(VHV_CONFIG_TIMEOUT_MACROP_LOOP_BOUND, v.savedVHVTimeout); err != nil {
+		return err
+	}
+
+	if err := v.writeReg(PHASECAL_CONFIG_OVERRIDE, 0x00); err != nil {
+		return err
+	}
+
+	// manual calibration is set up again after the next measurement
+	v.calibrated = false
+	return nil
+}
+
+// noteCalibrationTemp records the temperature when the VHV calibration ran
+func (v *VL53L1X) noteCalibrationTemp() {
+
+	if v.temp.provider == nil {
+		return
+	}
+
+	t, err := v.temp.provider()
+
+	if err != nil {
+		v.emitError("temperature", err)
+		return
+	}
+
+	v.temp.calTemp = t
+	v.temp.haveCalTemp = true
+	v.temp.lastCheck = time.Now()
+}
+
+// checkTemperature recalibrates when the temperature has shifted since the
+// last VHV calibration
+func (v *VL53L1X) checkTemperature() {
+
+	ts := &v.temp
+
+	if ts.provider == nil || !ts.haveCalTemp || !v.calibrated ||
+		time.Since(ts.lastCheck) < temperatureCheckInterval {
+		return
+	}
+
+	ts.lastCheck = time.Now()
+	t, err := ts.provider()
+
+	if err != nil {
+		v.emitError("temperature", err)
+		return
+	}
+
+	if math.Abs(float64(t-ts.calTemp)) <= float64(ts.delta) {
+		return
+	}
+
+	v.log.Printf("Temperature shifted from %.1fC to %.1fC", ts.calTemp, t)
+
+	if err := v.RecalibrateTemperature(); err != nil {
+		v.emitError("temperature recalibration", err)
+	}
+}
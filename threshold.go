@@ -0,0 +1,399 @@
+package vl53l1x
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ThresholdWindow selects when a distance threshold raises the interrupt,
+// as SYSTEM_INTERRUPT_CONFIG_GPIO bits 0-2
+type ThresholdWindow uint8
+
+const (
+	// ThresholdBelow interrupts when the distance is below the low threshold
+	ThresholdBelow ThresholdWindow = 0
+	// ThresholdAbove interrupts when the distance is above the high threshold
+	ThresholdAbove ThresholdWindow = 1
+	// ThresholdOutside interrupts when the distance is below the low or above
+	// the high threshold
+	ThresholdOutside ThresholdWindow = 2
+	// ThresholdInside interrupts when the distance is between the thresholds
+	ThresholdInside ThresholdWindow = 3
+)
+
+// String returns the name of the window
+func (w ThresholdWindow) String() string {
+	switch w {
+	case ThresholdBelow:
+		return "below"
+	case ThresholdAbove:
+		return "above"
+	case ThresholdOutside:
+		return "outside"
+	case ThresholdInside:
+		return "inside"
+	default:
+		return fmt.Sprintf("ThresholdWindow(%d)", uint8(w))
+	}
+}
+
+// ParseThresholdWindow returns the ThresholdWindow named below, above,
+// outside or inside, case insensitive
+func ParseThresholdWindow(s string) (ThresholdWindow, error) {
+
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "below":
+		return ThresholdBelow, nil
+	case "above":
+		return ThresholdAbove, nil
+	case "outside":
+		return ThresholdOutside, nil
+	case "inside":
+		return ThresholdInside, nil
+	default:
+		return ThresholdBelow, fmt.Errorf("unknown threshold window %q, expected below, above, outside or inside", s)
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler so the window is written by
+// name in configuration files
+func (w ThresholdWindow) MarshalText() ([]byte, error) {
+
+	if w > ThresholdInside {
+		return nil, fmt.Errorf("invalid threshold window %d", uint8(w))
+	}
+
+	return []byte(w.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler so the window can be
+// given by name in configuration files
+func (w *ThresholdWindow) UnmarshalText(text []byte) error {
+
+	window, err := ParseThresholdWindow(string(text))
+
+	if err != nil {
+		return err
+	}
+
+	*w = window
+
+	return nil
+}
+
+// EventMode selects which measurements raise the interrupt, modelled on
+// VL53L1_DetectionMode of ST's full API
+type EventMode uint8
+
+const (
+	// EventEveryMeasurement interrupts on every measurement, the default
+	EventEveryMeasurement EventMode = 0
+	// EventDistance interrupts when the distance meets its window
+	EventDistance EventMode = 1
+	// EventRate interrupts when the signal rate meets its window
+	EventRate EventMode = 2
+	// EventDistanceAndRate interrupts when both windows are met
+	EventDistanceAndRate EventMode = 3
+	// EventDistanceOrRate interrupts when either window is met
+	EventDistanceOrRate EventMode = 4
+)
+
+// String returns the name of the event mode
+func (m EventMode) String() string {
+	switch m {
+	case EventEveryMeasurement:
+		return "every measurement"
+	case EventDistance:
+		return "distance"
+	case EventRate:
+		return "rate"
+	case EventDistanceAndRate:
+		return "distance and rate"
+	case EventDistanceOrRate:
+		return "distance or rate"
+	default:
+		return fmt.Sprintf("EventMode(%d)", uint8(m))
+	}
+}
+
+// SYSTEM_INTERRUPT_CONFIG_GPIO bits
+const (
+	// intConfigWindow are the bits selecting the distance window
+	intConfigWindow uint8 = 0x03
+	// intConfigRateWindow are the bits selecting the signal rate window
+	intConfigRateWindow uint8 = 0x0C
+	// intConfigNewSample interrupts on every new measurement, the default
+	intConfigNewSample uint8 = 0x20
+	// intConfigNoTarget interrupts in threshold mode when there is no target
+	intConfigNoTarget uint8 = 0x40
+	// intConfigCombined requires both the distance and rate windows to be
+	// met, otherwise either is enough
+	intConfigCombined uint8 = 0x80
+)
+
+// EventConfig is the configuration of the measurements that raise the
+// interrupt
+type EventConfig struct {
+	Mode EventMode
+	// DistanceWindow, LowMM and HighMM are the distance window of the
+	// EventDistance modes
+	DistanceWindow ThresholdWindow
+	LowMM          uint16
+	HighMM         uint16
+	// RateWindow, LowRate and HighRate are the peak signal rate window of the
+	// EventRate modes, with the rates in 9.7 fixed point MCPS
+	RateWindow ThresholdWindow
+	LowRate    uint16
+	HighRate   uint16
+	// OnNoTarget also raises the interrupt when there is no target
+	OnNoTarget bool
+}
+
+// SetEventConfig makes the sensor raise the interrupt only for measurements
+// meeting the distance and signal rate windows of the event mode, based on
+// VL53L1_SetThresholdConfig() from ST's full API.  Measurements that do not
+// are taken but not reported, so Read() waits until one does and can time
+// out.  Each measurement read while an event mode is set is also emitted as
+// a ThresholdEvent
+func (v *VL53L1X) SetEventConfig(cfg EventConfig) error {
+
+	if cfg.Mode > EventDistanceOrRate {
+		return fmt.Errorf("invalid event mode %d", cfg.Mode)
+	}
+
+	if cfg.DistanceWindow > ThresholdInside || cfg.RateWindow > ThresholdInside {
+		return fmt.Errorf("invalid threshold window")
+	}
+
+	if cfg.LowMM > cfg.HighMM {
+		return fmt.Errorf("low threshold %dmm is above high threshold %dmm", cfg.LowMM, cfg.HighMM)
+	}
+
+	if cfg.LowRate > cfg.HighRate {
+		return fmt.Errorf("low rate threshold 0x%04X is above high rate threshold 0x%04X",
+			cfg.LowRate, cfg.HighRate)
+	}
+
+	gpio, err := v.readReg(SYSTEM_INTERRUPT_CONFIG_GPIO)
+
+	if err != nil {
+		return err
+	}
+
+	gpio &^= intConfigWindow | intConfigRateWindow | intConfigNewSample |
+		intConfigNoTarget | intConfigCombined
+
+	if cfg.Mode == EventEveryMeasurement {
+		if err := v.writeReg(SYSTEM_INTERRUPT_CONFIG_GPIO, gpio|intConfigNewSample); err != nil {
+			return err
+		}
+
+		v.eventDetect = false
+
+		return nil
+	}
+
+	// a window that is not used is set below a threshold of 0 so it is never
+	// met, leaving the other to decide alone
+	switch cfg.Mode {
+	case EventDistance:
+		cfg.RateWindow, cfg.LowRate, cfg.HighRate = ThresholdBelow, 0, 0
+	case EventRate:
+		cfg.DistanceWindow, cfg.LowMM, cfg.HighMM = ThresholdBelow, 0, 0
+	case EventDistanceAndRate:
+		gpio |= intConfigCombined
+	}
+
+	gpio |= uint8(cfg.DistanceWindow) | uint8(cfg.RateWindow)<<2
+
+	if cfg.OnNoTarget {
+		gpio |= intConfigNoTarget
+	}
+
+	regs := []regValue{
+		{SYSTEM_INTERRUPT_CONFIG_GPIO, 1, uint32(gpio)},
+		{SYSTEM_THRESH_HIGH, 2, uint32(cfg.HighMM)},
+		{SYSTEM_THRESH_LOW, 2, uint32(cfg.LowMM)},
+		{SYSTEM_THRESH_RATE_HIGH, 2, uint32(cfg.HighRate)},
+		{SYSTEM_THRESH_RATE_LOW, 2, uint32(cfg.LowRate)},
+	}
+
+	for _, r := range regs {
+		if err := v.writeRegSized(r.reg, r.size, r.value); err != nil {
+			return err
+		}
+	}
+
+	v.eventDetect = true
+
+	return nil
+}
+
+// GetEventConfig reads the configuration of the measurements that raise the
+// interrupt
+func (v *VL53L1X) GetEventConfig() (EventConfig, error) {
+
+	gpio, err := v.readReg(SYSTEM_INTERRUPT_CONFIG_GPIO)
+
+	if err != nil {
+		return EventConfig{}, err
+	}
+
+	if gpio&intConfigNewSample != 0 {
+		return EventConfig{Mode: EventEveryMeasurement}, nil
+	}
+
+	cfg := EventConfig{
+		DistanceWindow: ThresholdWindow(gpio & intConfigWindow),
+		RateWindow:     ThresholdWindow(gpio & intConfigRateWindow >> 2),
+		OnNoTarget:     gpio&intConfigNoTarget != 0,
+	}
+
+	regs := []struct {
+		reg uint16
+		dst *uint16
+	}{
+		{SYSTEM_THRESH_HIGH, &cfg.HighMM},
+		{SYSTEM_THRESH_LOW, &cfg.LowMM},
+		{SYSTEM_THRESH_RATE_HIGH, &cfg.HighRate},
+		{SYSTEM_THRESH_RATE_LOW, &cfg.LowRate},
+	}
+
+	for _, r := range regs {
+		if *r.dst, err = v.readReg16Bit(r.reg); err != nil {
+			return EventConfig{}, err
+		}
+	}
+
+	// a window below a threshold of 0 is never met so was not used
+	switch {
+	case gpio&intConfigCombined != 0:
+		cfg.Mode = EventDistanceAndRate
+	case cfg.RateWindow == ThresholdBelow && cfg.LowRate == 0:
+		cfg.Mode = EventDistance
+	case cfg.DistanceWindow == ThresholdBelow && cfg.LowMM == 0:
+		cfg.Mode = EventRate
+	default:
+		cfg.Mode = EventDistanceOrRate
+	}
+
+	return cfg, nil
+}
+
+// DistanceThreshold is the distance threshold configuration of the sensor
+type DistanceThreshold struct {
+	// Enabled is false when the interrupt is not raised by distance
+	Enabled bool
+	Window  ThresholdWindow
+	LowMM   uint16
+	HighMM  uint16
+	// OnNoTarget also raises the interrupt when there is no target
+	OnNoTarget bool
+}
+
+// SetDistanceThreshold makes the sensor raise the interrupt only for
+// measurements meeting the window, based on VL53L1X_SetDistanceThreshold()
+// from the ULD.  It sets the EventDistance mode of SetEventConfig().  When
+// onNoTarget is set measurements without a target are also reported
+func (v *VL53L1X) SetDistanceThreshold(lowMM, highMM uint16, window ThresholdWindow, onNoTarget bool) error {
+
+	return v.SetEventConfig(EventConfig{
+		Mode:           EventDistance,
+		DistanceWindow: window,
+		LowMM:          lowMM,
+		HighMM:         highMM,
+		OnNoTarget:     onNoTarget,
+	})
+}
+
+// GetDistanceThreshold reads the distance threshold configuration
+func (v *VL53L1X) GetDistanceThreshold() (DistanceThreshold, error) {
+
+	cfg, err := v.GetEventConfig()
+
+	if err != nil {
+		return DistanceThreshold{}, err
+	}
+
+	return DistanceThreshold{
+		Enabled:    cfg.Mode != EventEveryMeasurement && cfg.Mode != EventRate,
+		Window:     cfg.DistanceWindow,
+		LowMM:      cfg.LowMM,
+		HighMM:     cfg.HighMM,
+		OnNoTarget: cfg.OnNoTarget,
+	}, nil
+}
+
+// ClearDistanceThreshold returns to raising the interrupt on every
+// measurement
+func (v *VL53L1X) ClearDistanceThreshold() error {
+	return v.SetEventConfig(EventConfig{Mode: EventEveryMeasurement})
+}
+
+// InterruptPolarity is the level of the GPIO1 interrupt output when a
+// measurement is ready
+type InterruptPolarity uint8
+
+const (
+	// InterruptActiveLow drives GPIO1 low when ready, the default
+	InterruptActiveLow InterruptPolarity = 0
+	// InterruptActiveHigh drives GPIO1 high when ready
+	InterruptActiveHigh InterruptPolarity = 1
+)
+
+// String returns the name of the polarity
+func (p InterruptPolarity) String() string {
+
+	if p == InterruptActiveHigh {
+		return "active high"
+	}
+
+	return "active low"
+}
+
+// gpioActiveLow is the GPIO_HV_MUX_CTRL bit selecting an active low
+// interrupt output
+const gpioActiveLow uint8 = 0x10
+
+// SetInterruptPolarity sets the level of the GPIO1 interrupt output when a
+// measurement is ready, based on VL53L1X_SetInterruptPolarity() from the ULD,
+// for hosts wiring the interrupt to an input that needs a particular edge
+func (v *VL53L1X) SetInterruptPolarity(p InterruptPolarity) error {
+
+	val, err := v.readReg(GPIO_HV_MUX_CTRL)
+
+	if err != nil {
+		return err
+	}
+
+	val &^= gpioActiveLow
+
+	if p == InterruptActiveLow {
+		val |= gpioActiveLow
+	}
+
+	if err := v.writeReg(GPIO_HV_MUX_CTRL, val); err != nil {
+		return err
+	}
+
+	v.intActiveHigh = p == InterruptActiveHigh
+
+	return nil
+}
+
+// GetInterruptPolarity reads the level of the GPIO1 interrupt output when a
+// measurement is ready
+func (v *VL53L1X) GetInterruptPolarity() (InterruptPolarity, error) {
+
+	val, err := v.readReg(GPIO_HV_MUX_CTRL)
+
+	if err != nil {
+		return InterruptActiveLow, err
+	}
+
+	if val&gpioActiveLow != 0 {
+		return InterruptActiveLow, nil
+	}
+
+	return InterruptActiveHigh, nil
+}
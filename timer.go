@@ -1,25 +1,144 @@
 package vl53l1x
 
-import "time"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
 
-// SetTimeout set the timeout duration for reading sensor values
+// ErrTimeout is returned when the sensor does not become ready within the
+// timeout set with SetTimeout() or the deadline of the context passed
+var ErrTimeout = errors.New("timeout")
+
+// TimeoutError is returned when the sensor does not become ready in time.  It
+// wraps ErrTimeout and carries the sensor state when the wait gave up so a
+// sensor that was never started can be told apart from one whose interrupt
+// never fires
+type TimeoutError struct {
+	// What is the condition that was waited for
+	What string
+	// Waited is how long was waited
+	Waited time.Duration
+	// GPIOStatus is the last GPIO_TIO_HV_STATUS value read while waiting
+	GPIOStatus uint8
+	// SystemStatus is the FIRMWARE_SYSTEM_STATUS read after the timeout
+	SystemStatus SystemStatus
+	// RangingStarted is true if continuous or single shot ranging was started
+	RangingStarted bool
+}
+
+// Error implements the error interface
+func (e *TimeoutError) Error() string {
+
+	msg := fmt.Sprintf("%v waiting for %s after %v", ErrTimeout, e.What, e.Waited)
+
+	if e.What != "data" {
+		return msg
+	}
+
+	switch {
+	case !e.RangingStarted:
+		return msg + ": ranging not started"
+	case !e.SystemStatus.Booted:
+		return msg + ": sensor not booted"
+	default:
+		return fmt.Sprintf("%s: GPIO status 0x%02X, check interrupt polarity", msg, e.GPIOStatus)
+	}
+}
+
+// Unwrap returns ErrTimeout so errors.Is(err, ErrTimeout) matches
+func (e *TimeoutError) Unwrap() error {
+	return ErrTimeout
+}
+
+// SetTimeout set the timeout duration for reading sensor values.  A timeout
+// of 0 waits indefinitely
 func (v *VL53L1X) SetTimeout(timeout time.Duration) {
-	v.ioTimeout = timeout
+	v.ioTimeout.Store(int64(timeout))
 }
 
-// TimeoutOccurred reports whether a timeout has occurred
+// TimeoutOccurred reports whether a timeout has occurred since it was last
+// called.
+//
+// Deprecated: timeouts are returned as errors wrapping ErrTimeout, use
+// errors.Is(err, ErrTimeout) on the error returned by the call instead
 func (v *VL53L1X) TimeoutOccurred() bool {
-	tmp := v.didTimeout
-	v.didTimeout = false
-	return tmp
+	return v.didTimeout.Swap(false)
 }
 
-// startTimeout starts the timeout counter
-func (v *VL53L1X) startTimeout() {
-	v.timeoutStart = time.Now()
+// waitFor polls cond every millisecond until it returns true or an error.  The
+// wait is bound by the context deadline or, if the context has none, the
+// timeout set with SetTimeout().  Each call has its own deadline so waits in
+// different operations do not interfere.  On timeout a *TimeoutError is
+// returned.  The wait does not allocate, so it is part of an allocation free
+// Read()
+func (v *VL53L1X) waitFor(ctx context.Context, what string, cond func() (bool, error)) error {
+
+	start := time.Now()
+	deadline, ok := ctx.Deadline()
+
+	if timeout := time.Duration(v.ioTimeout.Load()); !ok && timeout > 0 {
+		deadline, ok = start.Add(timeout), true
+	}
+
+	for {
+		done, err := cond()
+
+		if err != nil {
+			return err
+		}
+
+		if done {
+			return nil
+		}
+
+		if ok && !time.Now().Before(deadline) {
+			v.didTimeout.Store(true)
+			v.stats.timeouts.Add(1)
+			return &TimeoutError{What: what, Waited: time.Since(start)}
+		}
+
+		if err := v.pollDelay(ctx); err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				v.didTimeout.Store(true)
+				v.stats.timeouts.Add(1)
+				return &TimeoutError{What: what, Waited: time.Since(start)}
+			}
+
+			return err
+		}
+	}
 }
 
-// checkTimeoutExpired checks if timeout has expired
-func (v *VL53L1X) checkTimeoutExpired() bool {
-	return (v.ioTimeout > 0) && (time.Since(v.timeoutStart) > v.ioTimeout)
+// pollDelay waits the polling interval of waitFor() or until the context is
+// done.  A context that is never done is waited out with a sleep, otherwise
+// the timer is reused between waits
+func (v *VL53L1X) pollDelay(ctx context.Context) error {
+
+	done := ctx.Done()
+
+	if done == nil {
+		time.Sleep(time.Millisecond)
+		return nil
+	}
+
+	if v.poll == nil {
+		v.poll = time.NewTimer(time.Millisecond)
+	} else {
+		v.poll.Reset(time.Millisecond)
+	}
+
+	select {
+	case <-done:
+		// stop the timer and drain it so it can be reset
+		if !v.poll.Stop() {
+			<-v.poll.C
+		}
+
+		return ctx.Err()
+
+	case <-v.poll.C:
+		return nil
+	}
 }
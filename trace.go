@@ -0,0 +1,90 @@
+package vl53l1x
+
+import "context"
+
+// Span names of the traced sensor operations
+const (
+	SpanInit            = "vl53l1x.Init"
+	SpanCalibrateOffset = "vl53l1x.CalibrateOffset"
+	SpanCalibrateXtalk  = "vl53l1x.CalibrateXtalk"
+	SpanCalibrateZones  = "vl53l1x.CalibrateZoneOffsets"
+	SpanRecalibrateTemp = "vl53l1x.RecalibrateTemperature"
+	SpanMeasurement     = "vl53l1x.Measurement"
+)
+
+// Tracer starts spans around sensor operations so their latency and failures
+// appear in the distributed traces of an application.  It is implemented by
+// an adapter to a tracing library such as OpenTelemetry, keeping the driver
+// free of the dependency
+type Tracer interface {
+	// Start begins a span named name as a child of any span in ctx,
+	// returning a context holding the new span
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a traced operation started by a Tracer
+type Span interface {
+	// SetAttributes records attributes of the operation
+	SetAttributes(attrs ...Attribute)
+	// End completes the span, recording err as its status when not nil
+	End(err error)
+}
+
+// Attribute is a key value pair recorded on a Span, where Value is a string,
+// int64, float64 or bool
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// traceOp starts a span for the named operation with the measurements taken
+// during it as children, returning the function that ends it
+func (v *VL53L1X) traceOp(name string) (end func(error)) {
+
+	if v.tracer == nil {
+		return func(error) {}
+	}
+
+	ctx, span := v.tracer.Start(context.Background(), name)
+	parent := v.traceCtx
+	v.traceCtx = ctx
+
+	return func(err error) {
+		v.traceCtx = parent
+		span.End(err)
+	}
+}
+
+// traceMeasurement starts a span for a measurement cycle as a child of the
+// operation in progress, or otherwise of ctx, returning nil when tracing is
+// not enabled
+func (v *VL53L1X) traceMeasurement(ctx context.Context) Span {
+
+	if v.tracer == nil {
+		return nil
+	}
+
+	if v.traceCtx != nil {
+		ctx = v.traceCtx
+	}
+
+	_, span := v.tracer.Start(ctx, SpanMeasurement)
+
+	return span
+}
+
+// endMeasurement records the measurement on the span and ends it
+func endMeasurement(span Span, rData RangingData, err error) {
+
+	if err == nil {
+		span.SetAttributes(
+			Attribute{Key: "vl53l1x.range_status", Value: rData.RangeStatus.String()},
+			Attribute{Key: "vl53l1x.range_mm", Value: int64(rData.RangeMM)},
+			Attribute{Key: "vl53l1x.signal_rate_mcps", Value: float64(rData.PeakSignalCountRateMCPS)},
+			Attribute{Key: "vl53l1x.ambient_rate_mcps", Value: float64(rData.AmbientCountRateMCPS)},
+			Attribute{Key: "vl53l1x.sigma_mm", Value: float64(rData.SigmaMM)},
+		)
+	}
+
+	span.End(err)
+}
@@ -0,0 +1,173 @@
+package vl53l1x
+
+import "context"
+
+// TrackerConfig configures a Tracker
+type TrackerConfig struct {
+	// Width and Height of the tracking zone, defaults to 4x4
+	Width, Height uint8
+	// Step is the number of SPADs the zone is shifted to each neighbour,
+	// defaults to 2
+	Step uint8
+	// Samples is the number of measurements taken in each zone, defaults to 1
+	Samples int
+	// MaxRangeDeltaMM is the largest change in range between updates for a
+	// zone to be considered the same target, defaults to 150mm
+	MaxRangeDeltaMM uint16
+}
+
+// TrackResult is the state of the Tracker after an update
+type TrackResult struct {
+	// Zone the target was found in
+	Zone SPADRect
+	// RangeMM and PeakSignalCountRateMCPS of the target
+	RangeMM                 uint16
+	PeakSignalCountRateMCPS float32
+	// BearingX and BearingY are the direction of the target in degrees as
+	// returned by SPADRect.Bearing()
+	BearingX, BearingY float64
+	// Locked is true while the target is being followed, it is false when
+	// the target was lost or has just been reacquired
+	Locked bool
+}
+
+// Tracker follows a moving target across the SPAD array by shifting the ROI
+// toward the neighbouring zone with a consistent range and the strongest
+// signal, reporting the targets bearing along with its distance
+type Tracker struct {
+	v         *VL53L1X
+	cfg       TrackerConfig
+	zone      SPADRect
+	lastRange uint16
+	locked    bool
+}
+
+// NewTracker returns a Tracker starting from a zone at the center of the SPAD
+// array.  Continuous ranging must be stopped while tracking
+func NewTracker(v *VL53L1X, cfg TrackerConfig) *Tracker {
+
+	if cfg.Width < 4 || cfg.Height < 4 {
+		cfg.Width, cfg.Height = 4, 4
+	}
+
+	if cfg.Step == 0 {
+		cfg.Step = 2
+	}
+
+	if cfg.Samples <= 0 {
+		cfg.Samples = 1
+	}
+
+	if cfg.MaxRangeDeltaMM == 0 {
+		cfg.MaxRangeDeltaMM = 150
+	}
+
+	return &Tracker{
+		v:   v,
+		cfg: cfg,
+		zone: SPADRect{
+			Col:    (16 - cfg.Width) / 2,
+			Row:    (16 - cfg.Height) / 2,
+			Width:  cfg.Width,
+			Height: cfg.Height,
+		},
+	}
+}
+
+// Update measures the current zone and its neighbours and moves the tracking
+// zone to the target
+func (t *Tracker) Update(ctx context.Context) (TrackResult, error) {
+
+	var best, consistent ZoneResult
+
+	for _, zone := range t.candidates() {
+		res, err := t.v.MeasureZone(ctx, zone, t.cfg.Samples)
+
+		if err != nil {
+			return TrackResult{}, err
+		}
+
+		if res.Valid == 0 {
+			continue
+		}
+
+		if res.PeakSignalCountRateMCPS > best.PeakSignalCountRateMCPS {
+			best = res
+		}
+
+		if t.locked && absDiff(res.RangeMM, t.lastRange) <= t.cfg.MaxRangeDeltaMM &&
+			res.PeakSignalCountRateMCPS > consistent.PeakSignalCountRateMCPS {
+			consistent = res
+		}
+	}
+
+	locked := consistent.Valid > 0
+
+	if locked {
+		best = consistent
+	}
+
+	if best.Valid == 0 {
+		t.locked = false
+		return t.result(ZoneResult{Zone: t.zone}, false), nil
+	}
+
+	// when the target was lost or is acquired for the first time follow the
+	// strongest return and lock on from the next update
+	t.zone = best.Zone
+	t.lastRange = best.RangeMM
+	t.locked = true
+
+	return t.result(best, locked), nil
+}
+
+// result returns the TrackResult for the zone measurement
+func (t *Tracker) result(res ZoneResult, locked bool) TrackResult {
+
+	x, y := res.Zone.Bearing()
+
+	return TrackResult{
+		Zone:                    res.Zone,
+		RangeMM:                 res.RangeMM,
+		PeakSignalCountRateMCPS: res.PeakSignalCountRateMCPS,
+		BearingX:                x,
+		BearingY:                y,
+		Locked:                  locked,
+	}
+}
+
+// candidates returns the current zone and its neighbours shifted left, right,
+// up and down by the step size
+func (t *Tracker) candidates() []SPADRect {
+
+	zones := []SPADRect{t.zone}
+	step := int(t.cfg.Step)
+
+	for _, d := range [][2]int{{-step, 0}, {step, 0}, {0, -step}, {0, step}} {
+		col := clampOffset(int(t.zone.Col)+d[0], t.zone.Width)
+		row := clampOffset(int(t.zone.Row)+d[1], t.zone.Height)
+
+		if col == t.zone.Col && row == t.zone.Row {
+			continue
+		}
+
+		zones = append(zones, SPADRect{Col: col, Row: row, Width: t.zone.Width, Height: t.zone.Height})
+	}
+
+	return zones
+}
+
+// clampOffset limits the offset so a zone of the size fits in the SPAD array
+func clampOffset(off int, size uint8) uint8 {
+	return uint8(max(0, min(off, 16-int(size))))
+}
+
+// absDiff returns the absolute difference between a and b
+func absDiff(a, b uint16) uint16 {
+
+	if a > b {
+		return a - b
+	}
+
+	return b - a
+}
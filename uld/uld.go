@@ -0,0 +1,496 @@
+// Package uld is a lightweight VL53L1X driver modelled on ST's Ultra Lite
+// Driver (VL53L1X_ULD).  It initialises the sensor by writing ST's default
+// configuration block and exposes the ULD ranging state machine of
+// StartRanging(), CheckForDataReady(), GetDistance() and StopRanging().
+//
+// It has no dependencies beyond the standard errors and time packages and
+// performs no floating point math or allocation while ranging, making it
+// suitable for memory constrained TinyGo targets where the full driver and
+// its calibration flow are not needed.
+package uld
+
+import (
+	"errors"
+	"time"
+)
+
+// Address is the default address of the sensor on the I2C bus
+const Address uint8 = 0x29
+
+// ModelID is the value of IDENTIFICATION_MODEL_ID reported by the sensor
+const ModelID uint16 = 0xEACC
+
+// registers used by the ULD
+const (
+	regVHVTimeoutLoopBound    = 0x0008
+	regVHVInit                = 0x000B
+	regGPIOHVMuxCtrl          = 0x0030
+	regGPIOTIOHVStatus        = 0x0031
+	regPhasecalTimeout        = 0x004B
+	regRangeTimeoutA          = 0x005E
+	regRangeVCSELPeriodA      = 0x0060
+	regRangeTimeoutB          = 0x0061
+	regRangeVCSELPeriodB      = 0x0063
+	regRangeValidPhaseHigh    = 0x0069
+	regIntermeasurementPeriod = 0x006C
+	regSDWOISD0               = 0x0078
+	regSDInitialPhaseSD0      = 0x007A
+	regInterruptClear         = 0x0086
+	regModeStart              = 0x0087
+	regResultRangeStatus      = 0x0089
+	regResultRangeMM          = 0x0096
+	regOscCalibrateVal        = 0x00DE
+	regFirmwareSystemStatus   = 0x00E5
+	regModelID                = 0x010F
+)
+
+// configStart is the first register of the default configuration block
+const configStart = 0x2D
+
+// DefaultConfiguration is ST's VL51L1X_DEFAULT_CONFIGURATION block written to
+// registers 0x2D to 0x87 by Init().  Of note are 0x30 which selects an active
+// high interrupt, 0x46 which raises the interrupt on new sample ready, the
+// sigma and minimum count rate thresholds at 0x64 and 0x66, the ROI at 0x7F and
+// 0x87 left at 0 so ranging is not started
+var DefaultConfiguration = [...]byte{
+	0x00, 0x00, 0x00, 0x01, 0x02, 0x00, 0x02, 0x08, // 0x2D
+	0x00, 0x08, 0x10, 0x01, 0x01, 0x00, 0x00, 0x00, // 0x35
+	0x00, 0xFF, 0x00, 0x0F, 0x00, 0x00, 0x00, 0x00, // 0x3D
+	0x00, 0x20, 0x0B, 0x00, 0x00, 0x02, 0x0A, 0x21, // 0x45
+	0x00, 0x00, 0x05, 0x00, 0x00, 0x00, 0x00, 0xC8, // 0x4D
+	0x00, 0x00, 0x38, 0xFF, 0x01, 0x00, 0x08, 0x00, // 0x55
+	0x00, 0x01, 0xCC, 0x0F, 0x01, 0xF1, 0x0D, 0x01, // 0x5D
+	0x68, 0x00, 0x80, 0x08, 0xB8, 0x00, 0x00, 0x00, // 0x65
+	0x00, 0x0F, 0x89, 0x00, 0x00, 0x00, 0x00, 0x00, // 0x6D
+	0x00, 0x00, 0x01, 0x0F, 0x0D, 0x0E, 0x0E, 0x00, // 0x75
+	0x00, 0x02, 0xC7, 0xFF, 0x9B, 0x00, 0x00, 0x00, // 0x7D
+	0x01, 0x00, 0x00, // 0x85
+}
+
+// DistanceMode is the ULD distance mode
+type DistanceMode uint8
+
+const (
+	Short DistanceMode = 1
+	Long  DistanceMode = 2
+)
+
+// ErrTimeout is returned when the sensor does not boot or produce a
+// measurement in time
+var ErrTimeout = errors.New("timeout")
+
+// ErrInvalid is returned when a distance mode or timing budget is not
+// supported
+var ErrInvalid = errors.New("invalid parameter")
+
+// Bus is the transport used to communicate with the sensor, it has the same
+// methods as vl53l1x.Bus so the same transports can be used
+type Bus interface {
+	ReadBytes(buf []byte) (int, error)
+	WriteBytes(buf []byte) (int, error)
+}
+
+// Sensor is a VL53L1X driven by the ULD state machine
+type Sensor struct {
+	bus Bus
+	buf [6]byte
+	// Timeout is the time to wait for boot and for the first measurement
+	// during Init()
+	Timeout time.Duration
+}
+
+// New returns a sensor using the bus, Init() must be called before ranging
+func New(bus Bus) *Sensor {
+	return &Sensor{bus: bus, Timeout: 500 * time.Millisecond}
+}
+
+// Init waits for the sensor to boot, writes the default configuration and
+// performs a first measurement so VHV calibration is done, as per
+// VL53L1X_SensorInit()
+func (s *Sensor) Init() error {
+
+	deadline := time.Now().Add(s.Timeout)
+
+	for {
+		state, err := s.BootState()
+
+		if err != nil {
+			return err
+		}
+
+		if state&0x01 != 0 {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			return ErrTimeout
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	for i, val := range DefaultConfiguration {
+		if err := s.writeReg(configStart+uint16(i), val); err != nil {
+			return err
+		}
+	}
+
+	if err := s.StartRanging(); err != nil {
+		return err
+	}
+
+	for {
+		ready, err := s.CheckForDataReady()
+
+		if err != nil {
+			return err
+		}
+
+		if ready {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			return ErrTimeout
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := s.ClearInterrupt(); err != nil {
+		return err
+	}
+
+	if err := s.StopRanging(); err != nil {
+		return err
+	}
+
+	// two bounds VHV and start VHV from the previous temperature
+	if err := s.writeReg(regVHVTimeoutLoopBound, 0x09); err != nil {
+		return err
+	}
+
+	return s.writeReg(regVHVInit, 0x00)
+}
+
+// BootState returns the firmware system status, bit 0 is set once booted
+func (s *Sensor) BootState() (uint8, error) {
+	return s.readReg(regFirmwareSystemStatus)
+}
+
+// SensorID returns the model ID of the sensor
+func (s *Sensor) SensorID() (uint16, error) {
+	return s.readReg16(regModelID)
+}
+
+// StartRanging starts continuous timed ranging
+func (s *Sensor) StartRanging() error {
+	return s.writeReg(regModeStart, 0x40)
+}
+
+// StopRanging stops ranging
+func (s *Sensor) StopRanging() error {
+	return s.writeReg(regModeStart, 0x00)
+}
+
+// ClearInterrupt clears the data ready interrupt so the next measurement can
+// be reported
+func (s *Sensor) ClearInterrupt() error {
+	return s.writeReg(regInterruptClear, 0x01)
+}
+
+// CheckForDataReady reports whether a new measurement is available
+func (s *Sensor) CheckForDataReady() (bool, error) {
+
+	mux, err := s.readReg(regGPIOHVMuxCtrl)
+
+	if err != nil {
+		return false, err
+	}
+
+	// interrupt is active high when bit 4 is clear
+	polarity := uint8(1)
+
+	if mux&0x10 != 0 {
+		polarity = 0
+	}
+
+	status, err := s.readReg(regGPIOTIOHVStatus)
+
+	if err != nil {
+		return false, err
+	}
+
+	return status&0x01 == polarity, nil
+}
+
+// GetDistance returns the distance of the last measurement in millimeters
+func (s *Sensor) GetDistance() (uint16, error) {
+	return s.readReg16(regResultRangeMM)
+}
+
+// statusMap converts the raw range status to the ULD range status
+var statusMap = [24]uint8{
+	255, 255, 255, 5, 2, 4, 1, 7, 3, 0,
+	255, 255, 9, 13, 255, 255, 255, 255, 10, 6,
+	255, 255, 11, 12,
+}
+
+// GetRangeStatus returns the ULD range status of the last measurement, 0 for
+// a valid range, 1 sigma failure, 2 signal failure, 4 phase out of bounds, 7
+// wrap around and 255 for other errors
+func (s *Sensor) GetRangeStatus() (uint8, error) {
+
+	raw, err := s.readReg(regResultRangeStatus)
+
+	if err != nil {
+		return 255, err
+	}
+
+	raw &= 0x1F
+
+	if int(raw) >= len(statusMap) {
+		return 255, nil
+	}
+
+	return statusMap[raw], nil
+}
+
+// modePreset holds the registers written for a distance mode
+type modePreset struct {
+	phasecalTimeout, vcselA, vcselB, validPhaseHigh uint8
+	woiSD0, initialPhaseSD0                         uint16
+}
+
+var modePresets = map[DistanceMode]modePreset{
+	Short: {0x14, 0x07, 0x05, 0x38, 0x0705, 0x0606},
+	Long:  {0x0A, 0x0F, 0x0D, 0xB8, 0x0F0D, 0x0E0E},
+}
+
+// budgetTimeouts holds the macro period timeouts A and B for each timing
+// budget in milliseconds per distance mode
+var budgetTimeouts = map[DistanceMode]map[uint16][2]uint16{
+	Short: {
+		15:  {0x001D, 0x0027},
+		20:  {0x0051, 0x006E},
+		33:  {0x00D6, 0x006E},
+		50:  {0x01AE, 0x01E8},
+		100: {0x02E1, 0x0388},
+		200: {0x03E1, 0x0496},
+		500: {0x0591, 0x05C1},
+	},
+	Long: {
+		20:  {0x001E, 0x0022},
+		33:  {0x0060, 0x006E},
+		50:  {0x00AD, 0x00C6},
+		100: {0x01CC, 0x01EA},
+		200: {0x02D9, 0x02F8},
+		500: {0x048F, 0x04A4},
+	},
+}
+
+// GetDistanceMode returns the current distance mode
+func (s *Sensor) GetDistanceMode() (DistanceMode, error) {
+
+	phase, err := s.readReg(regPhasecalTimeout)
+
+	if err != nil {
+		return 0, err
+	}
+
+	for mode, p := range modePresets {
+		if p.phasecalTimeout == phase {
+			return mode, nil
+		}
+	}
+
+	return 0, ErrInvalid
+}
+
+// SetDistanceMode sets the distance mode keeping the current timing budget
+func (s *Sensor) SetDistanceMode(mode DistanceMode) error {
+
+	p, ok := modePresets[mode]
+
+	if !ok {
+		return ErrInvalid
+	}
+
+	budget, err := s.GetTimingBudget()
+
+	if err != nil {
+		return err
+	}
+
+	regs := []struct {
+		reg uint16
+		val uint8
+	}{
+		{regPhasecalTimeout, p.phasecalTimeout},
+		{regRangeVCSELPeriodA, p.vcselA},
+		{regRangeVCSELPeriodB, p.vcselB},
+		{regRangeValidPhaseHigh, p.validPhaseHigh},
+	}
+
+	for _, r := range regs {
+		if err := s.writeReg(r.reg, r.val); err != nil {
+			return err
+		}
+	}
+
+	if err := s.writeReg16(regSDWOISD0, p.woiSD0); err != nil {
+		return err
+	}
+
+	if err := s.writeReg16(regSDInitialPhaseSD0, p.initialPhaseSD0); err != nil {
+		return err
+	}
+
+	// short mode has no 15ms budget so an unknown budget is not restored
+	if _, ok := budgetTimeouts[mode][budget]; ok {
+		return s.SetTimingBudget(budget)
+	}
+
+	return nil
+}
+
+// SetTimingBudget sets the timing budget in milliseconds, one of 15 (short
+// mode only), 20, 33, 50, 100, 200 or 500
+func (s *Sensor) SetTimingBudget(ms uint16) error {
+
+	mode, err := s.GetDistanceMode()
+
+	if err != nil {
+		return err
+	}
+
+	t, ok := budgetTimeouts[mode][ms]
+
+	if !ok {
+		return ErrInvalid
+	}
+
+	if err := s.writeReg16(regRangeTimeoutA, t[0]); err != nil {
+		return err
+	}
+
+	return s.writeReg16(regRangeTimeoutB, t[1])
+}
+
+// GetTimingBudget returns the timing budget in milliseconds, or 0 if the
+// timeouts do not match a ULD budget
+func (s *Sensor) GetTimingBudget() (uint16, error) {
+
+	a, err := s.readReg16(regRangeTimeoutA)
+
+	if err != nil {
+		return 0, err
+	}
+
+	for _, budgets := range budgetTimeouts {
+		for ms, t := range budgets {
+			if t[0] == a {
+				return ms, nil
+			}
+		}
+	}
+
+	return 0, nil
+}
+
+// SetInterMeasurement sets the inter-measurement period in milliseconds, which
+// must be greater than or equal to the timing budget
+func (s *Sensor) SetInterMeasurement(ms uint32) error {
+
+	osc, err := s.readReg16(regOscCalibrateVal)
+
+	if err != nil {
+		return err
+	}
+
+	// the ULD applies a 1.075 correction to the oscillator calibration
+	period := uint32(osc&0x3FF) * ms * 1075 / 1000
+
+	return s.writeReg32(regIntermeasurementPeriod, period)
+}
+
+// writeReg writes an 8-bit register
+func (s *Sensor) writeReg(reg uint16, val uint8) error {
+
+	buf := s.buf[:3]
+	buf[0], buf[1], buf[2] = byte(reg>>8), byte(reg), val
+
+	_, err := s.bus.WriteBytes(buf)
+	return err
+}
+
+// writeReg16 writes a 16-bit register
+func (s *Sensor) writeReg16(reg uint16, val uint16) error {
+
+	buf := s.buf[:4]
+	buf[0], buf[1], buf[2], buf[3] = byte(reg>>8), byte(reg), byte(val>>8), byte(val)
+
+	_, err := s.bus.WriteBytes(buf)
+	return err
+}
+
+// writeReg32 writes a 32-bit register
+func (s *Sensor) writeReg32(reg uint16, val uint32) error {
+
+	buf := s.buf[:6]
+	buf[0], buf[1] = byte(reg>>8), byte(reg)
+	buf[2], buf[3], buf[4], buf[5] = byte(val>>24), byte(val>>16), byte(val>>8), byte(val)
+
+	_, err := s.bus.WriteBytes(buf)
+	return err
+}
+
+// errShortRead is returned when the sensor returns fewer bytes than requested
+var errShortRead = errors.New("short read")
+
+// read reads len(buf) bytes starting at the register
+func (s *Sensor) read(reg uint16, buf []byte) error {
+
+	addr := s.buf[:2]
+	addr[0], addr[1] = byte(reg>>8), byte(reg)
+
+	if _, err := s.bus.WriteBytes(addr); err != nil {
+		return err
+	}
+
+	n, err := s.bus.ReadBytes(buf)
+
+	if err != nil {
+		return err
+	}
+
+	if n < len(buf) {
+		return errShortRead
+	}
+
+	return nil
+}
+
+// readReg reads an 8-bit register
+func (s *Sensor) readReg(reg uint16) (uint8, error) {
+
+	buf := s.buf[2:3]
+
+	if err := s.read(reg, buf); err != nil {
+		return 0, err
+	}
+
+	return buf[0], nil
+}
+
+// readReg16 reads a 16-bit register
+func (s *Sensor) readReg16(reg uint16) (uint16, error) {
+
+	buf := s.buf[2:4]
+
+	if err := s.read(reg, buf); err != nil {
+		return 0, err
+	}
+
+	return uint16(buf[0])<<8 | uint16(buf[1]), nil
+}
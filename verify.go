@@ -0,0 +1,181 @@
+package vl53l1x
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// verifiedRegisters are the configuration registers tracked for
+// verification, covering timing, ROI, thresholds and compensation
+var verifiedRegisters = map[uint16]bool{
+	RANGE_CONFIG_TIMEOUT_MACROP_A:                     true,
+	RANGE_CONFIG_TIMEOUT_MACROP_B:                     true,
+	MM_CONFIG_TIMEOUT_MACROP_A:                        true,
+	MM_CONFIG_TIMEOUT_MACROP_B:                        true,
+	RANGE_CONFIG_VCSEL_PERIOD_A:                       true,
+	RANGE_CONFIG_VCSEL_PERIOD_B:                       true,
+	RANGE_CONFIG_VALID_PHASE_LOW:                      true,
+	RANGE_CONFIG_VALID_PHASE_HIGH:                     true,
+	SD_CONFIG_WOI_SD0:                                 true,
+	SD_CONFIG_WOI_SD1:                                 true,
+	SD_CONFIG_INITIAL_PHASE_SD0:                       true,
+	SD_CONFIG_INITIAL_PHASE_SD1:                       true,
+	SYSTEM_INTERMEASUREMENT_PERIOD:                    true,
+	ROI_CONFIG_USER_ROI_CENTRE_SPAD:                   true,
+	ROI_CONFIG_USER_ROI_REQUESTED_GLOBAL_XY_SIZE:      true,
+	SYSTEM_THRESH_RATE_HIGH:                           true,
+	SYSTEM_THRESH_RATE_LOW:                            true,
+	SYSTEM_THRESH_HIGH:                                true,
+	SYSTEM_THRESH_LOW:                                 true,
+	SYSTEM_INTERRUPT_CONFIG_GPIO:                      true,
+	GPIO_HV_MUX_CTRL:                                  true,
+	RANGE_CONFIG_SIGMA_THRESH:                         true,
+	RANGE_CONFIG_MIN_COUNT_RATE_RTN_LIMIT_MCPS:        true,
+	DSS_CONFIG_TARGET_TOTAL_RATE_MCPS:                 true,
+	ALGO_PART_TO_PART_RANGE_OFFSET_MM:                 true,
+	MM_CONFIG_INNER_OFFSET_MM:                         true,
+	MM_CONFIG_OUTER_OFFSET_MM:                         true,
+	ALGO_CROSSTALK_COMPENSATION_PLANE_OFFSET_KCPS:     true,
+	ALGO_CROSSTALK_COMPENSATION_X_PLANE_GRADIENT_KCPS: true,
+	ALGO_CROSSTALK_COMPENSATION_Y_PLANE_GRADIENT_KCPS: true,
+	ALGO_RANGE_IGNORE_THRESHOLD_MCPS:                  true,
+	ALGO_CONSISTENCY_CHECK_TOLERANCE:                  true,
+}
+
+// shadowReg is the last value written to a verified register
+type shadowReg struct {
+	size  int
+	value uint32
+}
+
+// ConfigMismatch is a configuration register that does not hold the value the
+// driver last wrote to it
+type ConfigMismatch struct {
+	Register uint16
+	Name     string
+	Want     uint32
+	Got      uint32
+}
+
+// String returns the mismatch as register name and values
+func (m ConfigMismatch) String() string {
+	return fmt.Sprintf("%s (0x%04X) want 0x%X got 0x%X", m.Name, m.Register, m.Want, m.Got)
+}
+
+// WriteVerifyError is returned by a configuration write when write verify
+// mode is enabled and the register does not read back the value written
+type WriteVerifyError struct {
+	Mismatch ConfigMismatch
+}
+
+// Error returns the error message
+func (e *WriteVerifyError) Error() string {
+	return "write verify failed: " + e.Mismatch.String()
+}
+
+// VerifyConfig reads back the timing, ROI, threshold and compensation
+// registers the driver has written since the last sensor reset and returns
+// those that no longer hold the value written, ordered by register.  An empty
+// result means the sensor holds the intended configuration
+func (v *VL53L1X) VerifyConfig() ([]ConfigMismatch, error) {
+
+	regs := make([]uint16, 0, len(v.shadow))
+
+	for reg := range v.shadow {
+		regs = append(regs, reg)
+	}
+
+	sort.Slice(regs, func(i, j int) bool { return regs[i] < regs[j] })
+
+	var diff []ConfigMismatch
+
+	for _, reg := range regs {
+		want := v.shadow[reg]
+		got, err := v.readSized(reg, want.size)
+
+		if err != nil {
+			return diff, fmt.Errorf("failed to read back %s: %w", RegisterName(reg), err)
+		}
+
+		if got != want.value {
+			diff = append(diff, ConfigMismatch{
+				Register: reg,
+				Name:     RegisterName(reg),
+				Want:     want.value,
+				Got:      got,
+			})
+		}
+	}
+
+	return diff, nil
+}
+
+// FormatMismatches returns the mismatches one per line
+func FormatMismatches(diff []ConfigMismatch) string {
+
+	lines := make([]string, len(diff))
+
+	for i, m := range diff {
+		lines[i] = m.String()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// recordWrite keeps the value written to a verified register and, in write
+// verify mode, reads it back.  A soft reset returns the registers to their
+// defaults so the values kept are discarded
+func (v *VL53L1X) recordWrite(reg uint16, size int, value uint32) error {
+
+	if reg == SOFT_RESET {
+		clear(v.shadow)
+		return nil
+	}
+
+	if !verifiedRegisters[reg] {
+		return nil
+	}
+
+	if v.shadow == nil {
+		v.shadow = make(map[uint16]shadowReg)
+	}
+
+	v.shadow[reg] = shadowReg{size: size, value: value}
+
+	if !v.writeVerify {
+		return nil
+	}
+
+	got, err := v.readSized(reg, size)
+
+	if err != nil {
+		return fmt.Errorf("failed to read back %s: %w", RegisterName(reg), err)
+	}
+
+	if got != value {
+		return &WriteVerifyError{Mismatch: ConfigMismatch{
+			Register: reg,
+			Name:     RegisterName(reg),
+			Want:     value,
+			Got:      got,
+		}}
+	}
+
+	return nil
+}
+
+// readSized reads a 1, 2 or 4 byte register
+func (v *VL53L1X) readSized(reg uint16, size int) (uint32, error) {
+
+	switch size {
+	case 1:
+		val, err := v.readReg(reg)
+		return uint32(val), err
+	case 2:
+		val, err := v.readReg16Bit(reg)
+		return uint32(val), err
+	default:
+		return v.readReg32Bit(reg)
+	}
+}
@@ -1,43 +1,131 @@
 // go-vl53l1x is an I2C driver for the ST VL53L1X time‐of‐flight sensor.
+//
+// The driver starts no goroutines of its own.  All measurement, calibration
+// and monitoring APIs, including the Run() methods, execute on the calling
+// goroutine, and those that range several sensors at once wait for the
+// goroutines they start before returning, so no goroutine started by the
+// driver is left running.
 package vl53l1x
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
+	"sync/atomic"
 	"time"
-
-	"github.com/swdee/go-i2c"
 )
 
 const (
 	// Address is the default address of the sensor on I2C bus
 	Address uint8 = 0x29
+	// ModelID is the value of IDENTIFICATION_MODEL_ID reported by the sensor
+	ModelID uint16 = 0xEACC
 	// TimingGuard is used in measurement timing budget calculations and is
 	// given in microseconds
 	TimingGuard uint32 = 4528
-	// TargetRate is used in DSS calculations
+	// TargetRate is the default target signal rate used in DSS calculations
+	// in 9.7 fixed point MCPS (20 MCPS)
 	TargetRate uint16 = 0x0A00
+	// DefaultTimingBudget is the timing budget in milliseconds used by
+	// NewFromPath() unless set with WithTimingBudget()
+	DefaultTimingBudget uint32 = 100
+	// DefaultConsistencyTolerance is the phase consistency check tolerance
+	// the sensor is initialized with, matching ST's ULD
+	DefaultConsistencyTolerance uint8 = 2
+)
+
+// DefaultDistanceMode is the distance mode used by NewFromPath() unless set
+// with WithDistanceMode()
+const DefaultDistanceMode = Long
+
+const (
+	// resultBufferSize is the number of bytes in the result block read
+	// starting at RESULT_RANGE_STATUS
+	resultBufferSize = 17
+	// resultBufferSizeSD1 is the number of bytes in the result block when
+	// extended to include the SD1 fields up to final_crosstalk_corrected_range_mm_sd1
+	resultBufferSizeSD1 = 35
 )
 
 // resultBuffer holds raw values read from the sensor
 type resultBuffer struct {
 	rangeStatus                                   uint8
+	reportStatus                                  uint8
 	streamCount                                   uint8
 	dssActualEffectiveSpadsSD0                    uint16
+	peakSignalCountRateMCPS_SD0                   uint16
 	ambientCountRateMCPS_SD0                      uint16
+	sigmaSD0                                      uint16
+	phaseSD0                                      uint16
 	finalCrosstalkCorrectedRangeMM_SD0            uint16
 	peakSignalCountRateCrosstalkCorrectedMCPS_SD0 uint16
+	peakSignalCountRateMCPS_SD1                   uint16
+	ambientCountRateMCPS_SD1                      uint16
+	sigmaSD1                                      uint16
+	finalCrosstalkCorrectedRangeMM_SD1            uint16
 }
 
 // VL53L1X represents a single VL53L1X sensor instance.
 type VL53L1X struct {
 	// bus is the I2C interface
-	bus *i2c.Options
-
-	ioTimeout    time.Duration
-	didTimeout   bool
-	timeoutStart time.Time
+	bus Bus
+	// maxTransfer is the maximum number of bytes read in a single transfer,
+	// 0 for no limit
+	maxTransfer int
+	// openBus opens a new connection on the bus when the address changes
+	openBus func(addr uint8, dev string) (Bus, error)
+	// ownsBus is set when the driver opened the bus and must close it
+	ownsBus bool
+	// xshut is the optional pin driving the sensors XSHUT input
+	xshut Pin
+	// closed is set once Close() has released the sensor
+	closed bool
+	// ranging is set while continuous ranging is active with the
+	// inter-measurement period periodMs, 0 when ranging back to back
+	ranging  bool
+	periodMs uint32
+	// paused is set while continuous ranging is halted by Pause()
+	paused bool
+	// autoSingleShot takes a single shot measurement on a blocking read when
+	// continuous ranging is not started
+	autoSingleShot bool
+	// singleShot is set while waiting for a single shot measurement
+	singleShot bool
+	// lastGPIOStatus is the last GPIO_TIO_HV_STATUS value read
+	lastGPIOStatus uint8
+	// intActiveHigh is set when the interrupt polarity is active high
+	intActiveHigh bool
+	// statusLED shows measurement quality set with WithStatusLED()
+	statusLED *statusLED
+	// meta is the name and labels set with WithName() and WithLabels()
+	meta meta
+	// eventDetect is set when an event mode limits the measurements raising
+	// the interrupt
+	eventDetect bool
+	// uldInit makes Init() write ST's default configuration block
+	uldInit bool
+
+	// busSpeed is the I2C clock rate in Hz set with SetBusSpeed(), 0 if not
+	// known
+	busSpeed int
+
+	// verifyAddress enables read back of the address register after
+	// SetAddress
+	verifyAddress bool
+	// writeVerify enables read back of every configuration register write
+	writeVerify bool
+	// shadow holds the values last written to the verified configuration
+	// registers
+	shadow map[uint16]shadowReg
+
+	// ioTimeout is the time.Duration to wait for the sensor
+	ioTimeout atomic.Int64
+	// didTimeout backs the deprecated TimeoutOccurred()
+	didTimeout atomic.Bool
+	// poll is the timer reused by waitFor() between polls of a cancellable
+	// wait
+	poll *time.Timer
 
 	fastOscFrequency uint16
 	oscCalibrateVal  uint16
@@ -45,31 +133,103 @@ type VL53L1X struct {
 	calibrated      bool
 	savedVHVInit    uint8
 	savedVHVTimeout uint8
+	// temp schedules VHV recalibration from SetTemperatureProvider()
+	temp temperatureState
 
 	distanceMode DistanceMode
+	// profiles are custom preset register tables set with SetModeProfile()
+	profiles map[DistanceMode]Profile
+	// targetRate is the DSS target signal rate in 9.7 fixed point MCPS
+	targetRate uint16
+	// consistencyTol is the phase consistency check tolerance
+	consistencyTol uint8
 	// timing budget in milliseconds
 	timingBudget uint32
-
-	lastStatus uint8
+	// autoAdjustBudget clamps illegal mode and budget combinations instead
+	// of returning an error
+	autoAdjustBudget bool
+
+	// stats are the counters returned by Stats()
+	stats counters
+
+	// holdOffFrames is the number of measurements discarded after a
+	// configuration change, or HoldOffAuto, and holdOff is the number still
+	// to be discarded
+	holdOffFrames int
+	holdOff       int
+	// startupDiscard is the number of measurements discarded after each
+	// start of ranging
+	startupDiscard int
+	// trim corrects the inter-measurement period for oscillator drift
+	trim *periodTrimmer
+	// tracer starts spans around sensor operations and traceCtx holds the
+	// span of the operation in progress
+	tracer   Tracer
+	traceCtx context.Context
 
 	results resultBuffer
 
+	// readSD1 extends the result block read to include the SD1 fields
+	readSD1 bool
+	// integerResults skips conversion of the result rates to floating point
+	integerResults bool
+
+	// store persists calibration and config, loaded during Init() when set
+	store Store
+
+	// strictStatus returns a MeasurementError from the read methods for
+	// measurements that are not RangeValid
+	strictStatus bool
+
+	// checkConsistency enables cross validation of result fields
+	checkConsistency bool
+	// lastStreamCount is the stream count of the previous measurement used
+	// for continuity checks and haveStreamCount is set once one is recorded
+	lastStreamCount uint8
+	haveStreamCount bool
+	// loss is set when lost measurements are counted, see WithFrameLoss()
+	loss *frameLoss
+	// cycle is set when measurement cycles are timed, see WithCycleTiming()
+	cycle *cycleTimer
+
+	// snapshots is set when raw snapshots are enabled with WithSnapshots()
+	snapshots *snapshotRing
+
+	// smudge is set when smudge detection is enabled
+	smudge *smudgeDetector
+	// xtalk is set when dynamic crosstalk correction is enabled
+	xtalk *xtalkCorrector
+
+	// wbuf and rbuf are scratch buffers reused by the register and result
+	// read/write helpers so the measurement path does not allocate
+	wbuf [6]byte
+	rbuf [resultBufferSizeSD1]byte
+
+	// events is the channel returned by Events()
+	events eventBus
+
 	// log logger for debugging
 	log *log.Logger
+	// regLog logs every register transaction with values of the registers
+	// in regRedact hidden
+	regLog    bool
+	regRedact map[uint16]bool
 }
 
 // New returns a new VL53L1X sensor instance configured with the specified
 // DistanceMode and Timing Budget interval in milliseconds
-func New(i2c *i2c.Options, mode DistanceMode, budget uint32) (*VL53L1X, error) {
+func New(bus Bus, mode DistanceMode, budget uint32, opts ...Option) (*VL53L1X, error) {
 
-	v, err := new(i2c, mode, budget)
+	v, err := new(bus, mode, budget, opts...)
 
 	if err != nil {
 		return nil, err
 	}
 
-	// create null logger
-	v.log = log.New(io.Discard, "", log.LstdFlags)
+	// create null logger unless set by WithLogger()
+	if v.log == nil {
+		v.log = log.New(io.Discard, "", log.LstdFlags)
+	}
 
 	// finish device setup
 	err = v.setup()
@@ -79,10 +239,10 @@ func New(i2c *i2c.Options, mode DistanceMode, budget uint32) (*VL53L1X, error) {
 
 // New creates sensor instance with logger to be used for debugging configured
 // with the specified DistanceMode and Timing Budget interval in milliseconds
-func NewWithLog(i2c *i2c.Options, mode DistanceMode, budget uint32,
-	log *log.Logger) (*VL53L1X, error) {
+func NewWithLog(bus Bus, mode DistanceMode, budget uint32,
+	log *log.Logger, opts ...Option) (*VL53L1X, error) {
 
-	v, err := new(i2c, mode, budget)
+	v, err := new(bus, mode, budget, opts...)
 
 	if err != nil {
 		return nil, err
@@ -97,21 +257,70 @@ func NewWithLog(i2c *i2c.Options, mode DistanceMode, budget uint32,
 	return v, err
 }
 
+// NewFromPath opens the I2C bus at the device path, eg: /dev/i2c-1, and returns
+// a new VL53L1X sensor instance at the given address.  The driver owns the bus
+// connection and closes it on Close().  The sensor is configured in
+// DefaultDistanceMode with DefaultTimingBudget unless changed with the
+// WithDistanceMode() and WithTimingBudget() options
+func NewFromPath(path string, addr uint8, opts ...Option) (*VL53L1X, error) {
+
+	bus, err := openI2C(addr, path)
+
+	if err != nil {
+		if bus != nil {
+			bus.Close()
+		}
+
+		return nil, fmt.Errorf("failed to open I2C bus %s: %w", path, err)
+	}
+
+	v, err := new(bus, DefaultDistanceMode, DefaultTimingBudget, opts...)
+
+	if err != nil {
+		bus.Close()
+		return nil, err
+	}
+
+	v.ownsBus = true
+
+	if v.log == nil {
+		v.log = log.New(io.Discard, "", log.LstdFlags)
+	}
+
+	if err := v.setup(); err != nil {
+		bus.Close()
+		return nil, err
+	}
+
+	return v, nil
+}
+
 // new returns a new VL53L1X sensor instance
-func new(i2c *i2c.Options, mode DistanceMode, budget uint32) (*VL53L1X, error) {
+func new(bus Bus, mode DistanceMode, budget uint32, opts ...Option) (*VL53L1X, error) {
 
-	addr := i2c.GetAddr()
+	addr := bus.GetAddr()
 
 	if addr == 0 {
 		return nil, fmt.Errorf("I2C device is not initiated")
 	}
 
 	v := &VL53L1X{
-		bus:          i2c,
-		ioTimeout:    0, // no timeout by default
-		calibrated:   false,
-		distanceMode: mode,
-		timingBudget: budget,
+		bus:            bus,
+		openBus:        openI2C,
+		calibrated:     false,
+		distanceMode:   mode,
+		timingBudget:   budget,
+		targetRate:     TargetRate,
+		consistencyTol: DefaultConsistencyTolerance,
+	}
+
+	// use the transports transfer limit unless overridden by an Option
+	if ts, ok := bus.(TransferSizer); ok {
+		v.maxTransfer = ts.MaxTransferSize()
+	}
+
+	for _, opt := range opts {
+		opt(v)
 	}
 
 	return v, nil
@@ -121,8 +330,19 @@ func new(i2c *i2c.Options, mode DistanceMode, budget uint32) (*VL53L1X, error) {
 // NewWithLog()
 func (v *VL53L1X) setup() error {
 
+	// prefix the log lines with the name set by WithName()
+	v.meta.mu.Lock()
+	v.applyName()
+	v.meta.mu.Unlock()
+
 	v.log.Printf("Starting Setup()")
 
+	if v.busSpeed != 0 {
+		if err := v.SetBusSpeed(v.busSpeed); err != nil {
+			return err
+		}
+	}
+
 	// initialize device
 	err := v.Init()
 
@@ -135,24 +355,101 @@ func (v *VL53L1X) setup() error {
 	return nil
 }
 
-// SetAddress change default address of sensor and reopen I2C-connection.
-func (v *VL53L1X) SetAddress(newAddr uint8) error {
+// Address returns the I2C address of the sensor
+func (v *VL53L1X) Address() uint8 {
+	return v.bus.GetAddr()
+}
 
-	if err := v.writeReg(I2C_SLAVE_DEVICE_ADDRESS, newAddr&0x7F); err != nil {
-		return err
+// Device returns the device path of the bus the sensor is on
+func (v *VL53L1X) Device() string {
+	return v.bus.GetDev()
+}
+
+// SetAddress change default address of sensor and reopen I2C-connection.  The
+// new address must be a valid 7-bit address that is not reserved by the I2C
+// specification.  The previous address is returned so callers can recover,
+// and if the new connection can not be used the sensor is rolled back to its
+// previous address
+func (v *VL53L1X) SetAddress(newAddr uint8) (uint8, error) {
+
+	oldAddr := v.bus.GetAddr()
+
+	if err := ValidateAddress(newAddr); err != nil {
+		return oldAddr, err
+	}
+
+	if newAddr == oldAddr {
+		return oldAddr, nil
+	}
+
+	if err := v.writeReg(I2C_SLAVE_DEVICE_ADDRESS, newAddr); err != nil {
+		return oldAddr, err
 	}
 
 	// open new connection
-	i2c, err := i2c.New(newAddr, v.bus.GetDev())
+	bus, err := v.openBus(newAddr, v.bus.GetDev())
 
 	if err != nil {
-		return err
+		err = fmt.Errorf("failed to open connection at address 0x%02X: %w", newAddr, err)
+
+		// best effort attempt to return sensor to old address, which only
+		// succeeds if it did not act on the address change
+		if rerr := v.writeReg(I2C_SLAVE_DEVICE_ADDRESS, oldAddr); rerr != nil {
+			return oldAddr, fmt.Errorf("%w, rolling back to 0x%02X failed: %v", err, oldAddr, rerr)
+		}
+
+		return oldAddr, fmt.Errorf("address change rolled back: %w", err)
+	}
+
+	oldBus := v.bus
+	v.bus = bus
+
+	if v.verifyAddress {
+		if err := v.verifyAddressChange(newAddr); err != nil {
+			// best effort attempt to return sensor to old address
+			v.writeReg(I2C_SLAVE_DEVICE_ADDRESS, oldAddr)
+			v.bus.Close()
+			v.bus = oldBus
+			return oldAddr, fmt.Errorf("address change rolled back: %w", err)
+		}
 	}
 
 	// close existing connection
-	v.bus.Close()
+	oldBus.Close()
+
+	return oldAddr, nil
+}
+
+// verifyAddressChange reads back the I2C address register over the current
+// connection and checks it matches the expected address
+func (v *VL53L1X) verifyAddressChange(addr uint8) error {
+
+	val, err := v.readReg(I2C_SLAVE_DEVICE_ADDRESS)
+
+	if err != nil {
+		return fmt.Errorf("verification read failed: %w", err)
+	}
+
+	if val&0x7F != addr {
+		return fmt.Errorf("verification read returned address 0x%02X, expected 0x%02X",
+			val&0x7F, addr)
+	}
+
+	return nil
+}
+
+// ValidateAddress checks the address can be assigned to the sensor.  The
+// sensor only supports 7-bit addressing and addresses 0x00-0x07 and 0x78-0x7F
+// are reserved by the I2C specification
+func ValidateAddress(addr uint8) error {
+
+	if addr > 0x7F {
+		return fmt.Errorf("address 0x%02X is not a 7-bit address, 10-bit addressing is not supported", addr)
+	}
+
+	if addr <= 0x07 || addr >= 0x78 {
+		return fmt.Errorf("address 0x%02X is reserved", addr)
+	}
 
-	// replace with new connection
-	v.bus = i2c
 	return nil
 }
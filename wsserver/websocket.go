@@ -0,0 +1,148 @@
+package wsserver
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// websocketGUID is appended to the client key to compute the accept key as
+// defined in RFC 6455
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket frame opcodes
+const (
+	opText  = 0x1
+	opClose = 0x8
+)
+
+// upgrade performs the WebSocket opening handshake and returns the hijacked
+// connection
+func upgrade(w http.ResponseWriter, r *http.Request) (net.Conn, *bufio.ReadWriter, error) {
+
+	if !headerContains(r.Header, "Connection", "upgrade") ||
+		!headerContains(r.Header, "Upgrade", "websocket") {
+		return nil, nil, fmt.Errorf("not a websocket upgrade request")
+	}
+
+	key := r.Header.Get("Sec-WebSocket-Key")
+
+	if key == "" {
+		return nil, nil, fmt.Errorf("missing Sec-WebSocket-Key")
+	}
+
+	hj, ok := w.(http.Hijacker)
+
+	if !ok {
+		return nil, nil, fmt.Errorf("connection can not be hijacked")
+	}
+
+	conn, rw, err := hj.Hijack()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sum := sha1.Sum([]byte(key + websocketGUID))
+
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\n"+
+		"Upgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Accept: %s\r\n\r\n", base64.StdEncoding.EncodeToString(sum[:]))
+
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	return conn, rw, nil
+}
+
+// headerContains checks if the comma separated header contains the token
+func headerContains(h http.Header, name, token string) bool {
+
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// writeFrame writes an unmasked server to client frame
+func writeFrame(w io.Writer, opcode byte, payload []byte) error {
+
+	hdr := make([]byte, 2, 10)
+	hdr[0] = 0x80 | opcode
+
+	switch n := len(payload); {
+	case n < 126:
+		hdr[1] = byte(n)
+	case n <= 0xFFFF:
+		hdr[1] = 126
+		hdr = binary.BigEndian.AppendUint16(hdr, uint16(n))
+	default:
+		hdr[1] = 127
+		hdr = binary.BigEndian.AppendUint64(hdr, uint64(n))
+	}
+
+	if _, err := w.Write(hdr); err != nil {
+		return err
+	}
+
+	_, err := w.Write(payload)
+	return err
+}
+
+// readFrame reads a client frame discarding its payload and returns the
+// opcode.  Clients only need to be read to detect when they close
+func readFrame(r io.Reader) (byte, error) {
+
+	var hdr [2]byte
+
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return 0, err
+	}
+
+	opcode := hdr[0] & 0x0F
+	n := uint64(hdr[1] & 0x7F)
+
+	switch n {
+	case 126:
+		var ext [2]byte
+
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, err
+		}
+
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+
+	case 127:
+		var ext [8]byte
+
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, err
+		}
+
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+
+	// client frames carry a 4 byte mask key
+	if hdr[1]&0x80 != 0 {
+		n += 4
+	}
+
+	if _, err := io.CopyN(io.Discard, r, int64(n)); err != nil {
+		return 0, err
+	}
+
+	return opcode, nil
+}
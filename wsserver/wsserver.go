@@ -0,0 +1,189 @@
+// Package wsserver streams sensor readings as JSON to WebSocket clients and
+// serves a small embedded page visualizing them, to eyeball sensor behavior
+// during bring-up.
+package wsserver
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/swdee/go-vl53l1x"
+)
+
+// clientBufferSize is the number of messages queued per client before new
+// messages to that client are dropped
+const clientBufferSize = 16
+
+//go:embed index.html
+var indexHTML []byte
+
+// Reader is the sensor interface used by Run(), satisfied by
+// *vl53l1x.VL53L1X
+type Reader interface {
+	ReadContext(ctx context.Context) (vl53l1x.RangingData, error)
+}
+
+// Message is the JSON sent to clients for each reading
+type Message struct {
+	Time        time.Time `json:"time"`
+	RangeMM     uint16    `json:"rangeMM"`
+	Status      uint8     `json:"status"`
+	StatusText  string    `json:"statusText"`
+	SignalMCPS  float32   `json:"signalMCPS"`
+	AmbientMCPS float32   `json:"ambientMCPS"`
+}
+
+// Server is an http.Handler serving the visualization page on / and the
+// WebSocket stream on /ws
+type Server struct {
+	mu      sync.Mutex
+	clients map[chan []byte]struct{}
+	mux     *http.ServeMux
+}
+
+// New returns a Server with no clients
+func New() *Server {
+
+	s := &Server{
+		clients: make(map[chan []byte]struct{}),
+		mux:     http.NewServeMux(),
+	}
+
+	s.mux.HandleFunc("/", s.handleIndex)
+	s.mux.HandleFunc("/ws", s.handleWS)
+
+	return s
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Publish sends the reading to all connected clients.  Clients that are not
+// keeping up have the reading dropped
+func (s *Server) Publish(data vl53l1x.RangingData) {
+
+	msg, err := json.Marshal(Message{
+		Time:        time.Now(),
+		RangeMM:     data.RangeMM,
+		Status:      uint8(data.RangeStatus),
+		StatusText:  data.RangeStatus.String(),
+		SignalMCPS:  data.PeakSignalCountRateMCPS,
+		AmbientMCPS: data.AmbientCountRateMCPS,
+	})
+
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ch := range s.clients {
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
+
+// Run reads measurements from the sensor, which must already be ranging, and
+// publishes them until the context is cancelled
+func (s *Server) Run(ctx context.Context, sensor Reader) error {
+
+	for {
+		data, err := sensor.ReadContext(ctx)
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		s.Publish(data)
+	}
+}
+
+// handleIndex serves the visualization page
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(indexHTML)
+}
+
+// handleWS upgrades the connection and streams readings to it until the
+// client disconnects
+func (s *Server) handleWS(w http.ResponseWriter, r *http.Request) {
+
+	conn, rw, err := upgrade(w, r)
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ch := make(chan []byte, clientBufferSize)
+
+	s.mu.Lock()
+	s.clients[ch] = struct{}{}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+
+	go s.readClient(rw.Reader, done)
+
+	s.writeClient(conn, ch, done)
+
+	s.mu.Lock()
+	delete(s.clients, ch)
+	s.mu.Unlock()
+
+	conn.Close()
+}
+
+// readClient reads frames from the client until it closes the connection
+func (s *Server) readClient(r *bufio.Reader, done chan struct{}) {
+
+	defer close(done)
+
+	for {
+		op, err := readFrame(r)
+
+		if err != nil || op == opClose {
+			return
+		}
+	}
+}
+
+// writeClient writes queued messages to the client until it disconnects
+func (s *Server) writeClient(conn net.Conn, ch chan []byte, done chan struct{}) {
+
+	for {
+		select {
+		case <-done:
+			writeFrame(conn, opClose, nil)
+			return
+
+		case msg := <-ch:
+			conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+
+			if err := writeFrame(conn, opText, msg); err != nil {
+				return
+			}
+		}
+	}
+}
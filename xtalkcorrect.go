@@ -0,0 +1,173 @@
+package vl53l1x
+
+import (
+	"math"
+	"time"
+)
+
+// XtalkCorrectConfig configures dynamic crosstalk correction.  Rates are in
+// kcps per SPAD
+type XtalkCorrectConfig struct {
+	// Interval is the minimum time between compensation updates, defaults
+	// to 1 minute
+	Interval time.Duration
+	// MinExcessKCPS is the excess crosstalk below which no update is made,
+	// defaults to 0.1
+	MinExcessKCPS float32
+	// MaxStepKCPS limits the change made by a single update, defaults to 0.5
+	MaxStepKCPS float32
+	// MaxKCPS bounds the total compensation that may be applied, defaults
+	// to 10
+	MaxKCPS float32
+}
+
+// xtalkCorrector holds the state of dynamic crosstalk correction
+type xtalkCorrector struct {
+	cfg XtalkCorrectConfig
+	// base is the compensation in 7.9 fixed point before the first update or
+	// since it was last set, and haveBase is set once it is known
+	base     uint16
+	haveBase bool
+	current  uint16
+	frozen   bool
+	last     time.Time
+}
+
+// newXtalkCorrector returns an xtalkCorrector with defaults applied
+func newXtalkCorrector(cfg XtalkCorrectConfig) *xtalkCorrector {
+
+	if cfg.Interval == 0 {
+		cfg.Interval = time.Minute
+	}
+
+	if cfg.MinExcessKCPS == 0 {
+		cfg.MinExcessKCPS = 0.1
+	}
+
+	if cfg.MaxStepKCPS == 0 {
+		cfg.MaxStepKCPS = 0.5
+	}
+
+	if cfg.MaxKCPS == 0 {
+		cfg.MaxKCPS = 10
+	}
+
+	return &xtalkCorrector{cfg: cfg}
+}
+
+// correctXtalk updates the crosstalk compensation from the smudge estimate
+// when dynamic correction is enabled and due
+func (v *VL53L1X) correctXtalk() error {
+
+	c := v.xtalk
+
+	if c == nil || c.frozen || v.smudge == nil {
+		return nil
+	}
+
+	s := v.smudge.status
+	now := time.Now()
+
+	if s.Samples < v.smudge.cfg.Samples || now.Sub(c.last) < c.cfg.Interval {
+		return nil
+	}
+
+	if math.Abs(float64(s.ExcessXtalkKCPS)) < float64(c.cfg.MinExcessKCPS) {
+		return nil
+	}
+
+	if !c.haveBase {
+		base, err := v.readReg16Bit(ALGO_CROSSTALK_COMPENSATION_PLANE_OFFSET_KCPS)
+
+		if err != nil {
+			return err
+		}
+
+		c.base, c.current, c.haveBase = base, base, true
+	}
+
+	step := max(-c.cfg.MaxStepKCPS, min(s.ExcessXtalkKCPS, c.cfg.MaxStepKCPS))
+
+	// compensation is in 7.9 fixed point
+	next := float32(c.current) + step*512
+	next = max(0, min(next, c.cfg.MaxKCPS*512))
+
+	c.last = now
+
+	if uint16(next) == c.current {
+		return nil
+	}
+
+	v.log.Printf("Dynamic xtalk correction %d -> %d", c.current, uint16(next))
+
+	// only the plane offset is corrected, leaving any gradients in place
+	if err := v.writeReg16Bit(ALGO_CROSSTALK_COMPENSATION_PLANE_OFFSET_KCPS, uint16(next)); err != nil {
+		return err
+	}
+
+	c.current = uint16(next)
+
+	// the estimate was relative to the previous compensation
+	v.ResetSmudge()
+	v.configChanged("xtalk compensation")
+
+	return nil
+}
+
+// rebaseXtalkCorrection makes dynamic correction continue from the plane
+// offset applied by calibration or SetXtalkPlane() rather than the
+// compensation it last wrote
+func (v *VL53L1X) rebaseXtalkCorrection(offset uint16) {
+
+	c := v.xtalk
+
+	if c == nil {
+		return
+	}
+
+	c.base, c.current, c.haveBase = offset, offset, true
+
+	// the estimate was relative to the previous compensation
+	v.ResetSmudge()
+}
+
+// FreezeXtalkCorrection stops or restarts dynamic crosstalk correction
+// leaving the learnt compensation applied
+func (v *VL53L1X) FreezeXtalkCorrection(freeze bool) {
+
+	if v.xtalk != nil {
+		v.xtalk.frozen = freeze
+	}
+}
+
+// ResetXtalkCorrection restores the crosstalk compensation in place before
+// dynamic correction made its first update, or since it was last set
+func (v *VL53L1X) ResetXtalkCorrection() error {
+
+	c := v.xtalk
+
+	if c == nil || !c.haveBase {
+		return nil
+	}
+
+	if err := v.writeReg16Bit(ALGO_CROSSTALK_COMPENSATION_PLANE_OFFSET_KCPS, c.base); err != nil {
+		return err
+	}
+
+	c.current = c.base
+	v.ResetSmudge()
+	v.configChanged("xtalk compensation")
+
+	return nil
+}
+
+// XtalkCorrection returns the crosstalk compensation in 7.9 fixed point kcps
+// per SPAD before dynamic correction and as currently learnt
+func (v *VL53L1X) XtalkCorrection() (base, current uint16) {
+
+	if v.xtalk == nil || !v.xtalk.haveBase {
+		return 0, 0
+	}
+
+	return v.xtalk.base, v.xtalk.current
+}
@@ -0,0 +1,62 @@
+package vl53l1x
+
+// XtalkPlane is the crosstalk compensation model of the sensor, a plane over
+// the SPAD array given by its offset at the ROI center and its gradients
+// across the array.  Large cover windows give crosstalk that varies across
+// the array which a single offset can not compensate as the ROI is moved
+type XtalkPlane struct {
+	// OffsetKCPS is the plane offset in kcps per SPAD in 7.9 fixed point
+	// format, as CalibrationData.XtalkKCPS
+	OffsetKCPS uint16
+	// XGradientKCPS and YGradientKCPS are the change in kcps per SPAD for
+	// each SPAD moved across columns and rows, in signed 5.11 fixed point
+	// format
+	XGradientKCPS int16
+	YGradientKCPS int16
+}
+
+// SetXtalkPlane applies the full crosstalk compensation model to the sensor,
+// for plane gradients characterized externally
+func (v *VL53L1X) SetXtalkPlane(p XtalkPlane) error {
+
+	if err := v.writeReg16Bit(ALGO_CROSSTALK_COMPENSATION_X_PLANE_GRADIENT_KCPS, uint16(p.XGradientKCPS)); err != nil {
+		return err
+	}
+
+	if err := v.writeReg16Bit(ALGO_CROSSTALK_COMPENSATION_Y_PLANE_GRADIENT_KCPS, uint16(p.YGradientKCPS)); err != nil {
+		return err
+	}
+
+	if err := v.writeReg16Bit(ALGO_CROSSTALK_COMPENSATION_PLANE_OFFSET_KCPS, p.OffsetKCPS); err != nil {
+		return err
+	}
+
+	v.rebaseXtalkCorrection(p.OffsetKCPS)
+
+	return nil
+}
+
+// GetXtalkPlane returns the crosstalk compensation model applied to the
+// sensor
+func (v *VL53L1X) GetXtalkPlane() (XtalkPlane, error) {
+
+	offset, err := v.readReg16Bit(ALGO_CROSSTALK_COMPENSATION_PLANE_OFFSET_KCPS)
+
+	if err != nil {
+		return XtalkPlane{}, err
+	}
+
+	x, err := v.readReg16Bit(ALGO_CROSSTALK_COMPENSATION_X_PLANE_GRADIENT_KCPS)
+
+	if err != nil {
+		return XtalkPlane{}, err
+	}
+
+	y, err := v.readReg16Bit(ALGO_CROSSTALK_COMPENSATION_Y_PLANE_GRADIENT_KCPS)
+
+	if err != nil {
+		return XtalkPlane{}, err
+	}
+
+	return XtalkPlane{OffsetKCPS: offset, XGradientKCPS: int16(x), YGradientKCPS: int16(y)}, nil
+}
@@ -0,0 +1,155 @@
+package vl53l1x
+
+import (
+	"context"
+	"fmt"
+)
+
+// ZoneResult holds the averaged measurements taken in a zone of the SPAD array
+type ZoneResult struct {
+	Zone SPADRect
+	// RangeMM is the mean range of the valid measurements
+	RangeMM uint16
+	// PeakSignalCountRateMCPS is the mean peak signal rate of the valid
+	// measurements
+	PeakSignalCountRateMCPS float32
+	// Valid is the number of measurements with a valid range status
+	Valid int
+	// Samples is the number of measurements taken
+	Samples int
+	// LastStatus is the range status of the last measurement taken
+	LastStatus RangeStatus
+}
+
+// MeasureZone sets the ROI to the zone and takes the number of single shot
+// measurements given, returning the mean of those with a valid range status.
+// Continuous ranging must be stopped before zones are measured
+func (v *VL53L1X) MeasureZone(ctx context.Context, zone SPADRect, samples int) (ZoneResult, error) {
+
+	if v.ranging {
+		return ZoneResult{}, fmt.Errorf("stop continuous ranging before measuring zones")
+	}
+
+	if !zone.valid() || zone.Width < 4 || zone.Height < 4 {
+		return ZoneResult{}, fmt.Errorf("zone must be at least 4x4 and fit in SPAD array")
+	}
+
+	if samples < 1 {
+		samples = 1
+	}
+
+	if err := v.SetROISize(zone.Width, zone.Height); err != nil {
+		return ZoneResult{}, err
+	}
+
+	if err := v.SetROICenter(zone.Center()); err != nil {
+		return ZoneResult{}, err
+	}
+
+	res := ZoneResult{Zone: zone, Samples: samples}
+
+	var rangeSum uint32
+	var signalSum float32
+
+	for i := 0; i < samples; i++ {
+		if err := ctx.Err(); err != nil {
+			return ZoneResult{}, err
+		}
+
+		rData, err := v.readSingle(ctx)
+
+		if err != nil {
+			return ZoneResult{}, err
+		}
+
+		res.LastStatus = rData.RangeStatus
+
+		if !rData.RangeStatus.IsValid() {
+			continue
+		}
+
+		rangeSum += uint32(rData.RangeMM)
+		signalSum += rData.Raw.peakSignalMCPS()
+		res.Valid++
+	}
+
+	if res.Valid > 0 {
+		res.RangeMM = uint16(rangeSum / uint32(res.Valid))
+		res.PeakSignalCountRateMCPS = signalSum / float32(res.Valid)
+	}
+
+	return res, nil
+}
+
+// Bearing returns the approximate direction in degrees seen by a ROI over the
+// rectangle relative to the optical axis.  It is oriented as per the SPAD
+// table looking into the front of the sensor with positive x to the right
+// and positive y up.  As the lens inverts the image a rectangle to the right
+// of the array center sees to the left
+func (r SPADRect) Bearing() (x, y float64) {
+
+	degPerSPAD := SPADFieldOfView(16) / 16
+
+	cx := float64(r.Col) + float64(r.Width)/2
+	cy := float64(r.Row) + float64(r.Height)/2
+
+	return (8 - cx) * degPerSPAD, (cy - 8) * degPerSPAD
+}
+
+// ZoneSweep returns the zones of the given size stepped across the SPAD array
+// from the top left, always including zones at the right and bottom edges
+func ZoneSweep(width, height, step uint8) []SPADRect {
+
+	if step == 0 {
+		step = 1
+	}
+
+	if width > 16 || height > 16 {
+		return nil
+	}
+
+	var zones []SPADRect
+
+	for _, row := range sweepOffsets(height, step) {
+		for _, col := range sweepOffsets(width, step) {
+			zones = append(zones, SPADRect{Col: col, Row: row, Width: width, Height: height})
+		}
+	}
+
+	return zones
+}
+
+// ZoneGrid divides the SPAD array into a grid of cols x rows equally sized
+// zones ordered left to right, top to bottom.  Zones must be at least 4x4 so
+// no more than 4 columns or rows are allowed
+func ZoneGrid(cols, rows uint8) ([]SPADRect, error) {
+
+	if cols < 1 || rows < 1 || cols > 4 || rows > 4 {
+		return nil, fmt.Errorf("zone grid must be between 1x1 and 4x4")
+	}
+
+	width, height := 16/cols, 16/rows
+	zones := make([]SPADRect, 0, int(cols)*int(rows))
+
+	for r := uint8(0); r < rows; r++ {
+		for c := uint8(0); c < cols; c++ {
+			zones = append(zones, SPADRect{Col: c * width, Row: r * height, Width: width, Height: height})
+		}
+	}
+
+	return zones, nil
+}
+
+// sweepOffsets returns the offsets a zone of the given size takes when stepped
+// along one side of the SPAD array
+func sweepOffsets(size, step uint8) []uint8 {
+
+	last := 16 - size
+	var offsets []uint8
+
+	for off := uint8(0); off < last; off += step {
+		offsets = append(offsets, off)
+	}
+
+	return append(offsets, last)
+}
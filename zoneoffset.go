@@ -0,0 +1,139 @@
+package vl53l1x
+
+import (
+	"fmt"
+	"math"
+)
+
+// ZoneOffsets holds the offsets in millimeters the sensor applies to ranges
+// measured by the inner SPADs of the ROI and by the ring of outer SPADs around
+// them, compensating for the offset changing across the field of view when
+// the ROI is resized or shifted from the center of the array
+type ZoneOffsets struct {
+	InnerMM int16
+	OuterMM int16
+}
+
+// SetZoneOffsets applies known inner and outer zone offsets in millimeters
+// through MM_CONFIG_INNER_OFFSET_MM and MM_CONFIG_OUTER_OFFSET_MM, leaving the
+// range offset of SetRangeOffsetMM() in place
+func (v *VL53L1X) SetZoneOffsets(offsets ZoneOffsets) error {
+
+	if err := v.writeReg16Bit(MM_CONFIG_INNER_OFFSET_MM, uint16(offsets.InnerMM)); err != nil {
+		return err
+	}
+
+	return v.writeReg16Bit(MM_CONFIG_OUTER_OFFSET_MM, uint16(offsets.OuterMM))
+}
+
+// GetZoneOffsets returns the inner and outer zone offsets in millimeters
+func (v *VL53L1X) GetZoneOffsets() (ZoneOffsets, error) {
+
+	inner, err := v.readReg16Bit(MM_CONFIG_INNER_OFFSET_MM)
+
+	if err != nil {
+		return ZoneOffsets{}, err
+	}
+
+	outer, err := v.readReg16Bit(MM_CONFIG_OUTER_OFFSET_MM)
+
+	if err != nil {
+		return ZoneOffsets{}, err
+	}
+
+	return ZoneOffsets{InnerMM: int16(inner), OuterMM: int16(outer)}, nil
+}
+
+// CalibrateZoneOffsets performs inner and outer zone offset calibration with
+// a target placed at the given distance in millimeters, following the MM1 and
+// MM2 steps of ST's full API offset calibration.  It is run after
+// CalibrateOffset() with the ROI size and center to be used, which must be at
+// least 8x8 so the inner SPADs can be separated from the outer ring.  The
+// inner SPADs are ranged with the ROI reduced to the central quarter, the
+// outer ring is derived from the range of the whole ROI weighted by SPAD
+// count, and the calculated offsets are applied to the sensor and returned
+func (v *VL53L1X) CalibrateZoneOffsets(targetMM uint16) (ZoneOffsets, error) {
+
+	end := v.traceOp(SpanCalibrateZones)
+	offsets, err := v.calibrateZoneOffsets(targetMM)
+	end(err)
+
+	return offsets, err
+}
+
+// calibrateZoneOffsets performs the calibration of CalibrateZoneOffsets()
+func (v *VL53L1X) calibrateZoneOffsets(targetMM uint16) (ZoneOffsets, error) {
+
+	if targetMM == 0 {
+		return ZoneOffsets{}, fmt.Errorf("target distance must be greater than zero")
+	}
+
+	if v.ranging {
+		return ZoneOffsets{}, fmt.Errorf("stop continuous ranging before calibrating zone offsets")
+	}
+
+	width, height, err := v.GetROISize()
+
+	if err != nil {
+		return ZoneOffsets{}, err
+	}
+
+	if width < 8 || height < 8 {
+		return ZoneOffsets{}, fmt.Errorf("ROI must be at least 8x8 to calibrate zone offsets, is %dx%d",
+			width, height)
+	}
+
+	center, err := v.GetROICenter()
+
+	if err != nil {
+		return ZoneOffsets{}, err
+	}
+
+	v.log.Printf("Calibrating zone offsets with target at %dmm", targetMM)
+
+	// clear existing zone offsets so only the range offset is applied
+	if err := v.SetZoneOffsets(ZoneOffsets{}); err != nil {
+		return ZoneOffsets{}, err
+	}
+
+	fullRange, _, _, err := v.sampleCalibration()
+
+	if err != nil {
+		return ZoneOffsets{}, err
+	}
+
+	innerW, innerH := width/2, height/2
+
+	// ROIs wider than 10 SPADs are forced to the array center, so restore the
+	// center after reducing to the inner SPADs
+	if err := v.SetROISize(innerW, innerH); err != nil {
+		return ZoneOffsets{}, err
+	}
+
+	if err := v.SetROICenter(center); err != nil {
+		v.restoreROI(width, height, center)
+		return ZoneOffsets{}, err
+	}
+
+	innerRange, _, _, err := v.sampleCalibration()
+	v.restoreROI(width, height, center)
+
+	if err != nil {
+		return ZoneOffsets{}, err
+	}
+
+	fullSpads := float64(width) * float64(height)
+	innerSpads := float64(innerW) * float64(innerH)
+	outerRange := (fullRange*fullSpads - innerRange*innerSpads) / (fullSpads - innerSpads)
+
+	offsets := ZoneOffsets{
+		InnerMM: int16(math.Round(float64(targetMM) - innerRange)),
+		OuterMM: int16(math.Round(float64(targetMM) - outerRange)),
+	}
+
+	if err := v.SetZoneOffsets(offsets); err != nil {
+		return ZoneOffsets{}, err
+	}
+
+	return offsets, nil
+}